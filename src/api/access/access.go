@@ -74,13 +74,19 @@ func Deck(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	deckPasswordHash, err := database.GetDeckPasswordHash(deckId)
+	deck, err := database.GetDeck(deckId)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		_, _ = w.Write([]byte(err.Error()))
 		return
 	}
 
+	if deck.PasswordRole == "NONE" {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte("This deck is invite only."))
+		return
+	}
+
 	err = r.ParseForm()
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
@@ -97,7 +103,7 @@ func Deck(w http.ResponseWriter, r *http.Request) {
 		break
 	}
 
-	if !auth.PasswordMatchesHash(password, deckPasswordHash) {
+	if !auth.PasswordMatchesHash(password, deck.PasswordHash) {
 		w.WriteHeader(http.StatusBadRequest)
 		_, _ = w.Write([]byte("Provided password is not valid."))
 		return
@@ -110,7 +116,7 @@ func Deck(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = database.AddUserDeckAccess(userId, deckId)
+	err = database.AddUserDeckAccess(userId, deckId, deck.PasswordRole)
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		_, _ = w.Write([]byte("Failed to add access."))
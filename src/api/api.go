@@ -19,6 +19,9 @@ type BasePageData struct {
 	PageTitle string
 	User      database.User
 	LoggedIn  bool
+
+	// reviewed card suggestions the user has not seen yet
+	NotificationCount int
 }
 
 func MiddlewareForPages(next http.Handler) http.Handler {
@@ -37,6 +40,7 @@ func MiddlewareForPages(next http.Handler) http.Handler {
 			} else if err == nil {
 				basePageData.User = user
 				basePageData.LoggedIn = true
+				basePageData.NotificationCount, _ = database.CountUnseenCardSuggestions(userId)
 			}
 		}
 
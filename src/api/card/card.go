@@ -1,10 +1,11 @@
 package apiCard
 
 import (
+	"crypto/sha1"
+	"fmt"
+	"html"
 	"io"
 	"net/http"
-	"regexp"
-	"strings"
 	"text/template"
 
 	"github.com/google/uuid"
@@ -69,6 +70,7 @@ func Create(w http.ResponseWriter, r *http.Request) {
 	var category string
 	var text string
 	var youtube string
+	var allowSimilar bool
 	for key, val := range r.Form {
 		switch key {
 		case "deckId":
@@ -84,6 +86,8 @@ func Create(w http.ResponseWriter, r *http.Request) {
 			text = val[0]
 		case "youtube":
 			youtube = val[0]
+		case "allowSimilar":
+			allowSimilar = true
 		}
 	}
 
@@ -94,7 +98,7 @@ func Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	hasDeckAccess, err := database.UserHasDeckAccess(userId, deckId)
+	hasDeckAccess, err := database.UserHasDeckAccess(userId, deckId, "EDIT")
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		_, _ = w.Write([]byte("Failed to check deck access."))
@@ -107,7 +111,7 @@ func Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	text, err = processCardText(text)
+	text, err = database.ProcessCardText(text)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		_, _ = w.Write([]byte(err.Error()))
@@ -139,6 +143,27 @@ func Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !allowSimilar {
+		similarCards, err := database.GetSimilarCards(userId, category, text)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte(err.Error()))
+			return
+		}
+
+		if len(similarCards) > 0 {
+			message := "Similar cards already exist:"
+			for _, similarCard := range similarCards {
+				message += fmt.Sprintf("\n%d%% similar in %s: %s", similarCard.SimilarityPercent(), html.EscapeString(similarCard.DeckName), html.EscapeString(similarCard.Text))
+			}
+			message += "\nCheck \"Allow Near Duplicate\" to create it anyway."
+
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(message))
+			return
+		}
+	}
+
 	_, err = database.CreateCard(deckId, category, text, youtube)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
@@ -195,7 +220,7 @@ func Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	hasDeckAccess, err := database.UserHasDeckAccess(userId, deckId)
+	hasDeckAccess, err := database.UserHasDeckAccess(userId, deckId, "EDIT")
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		_, _ = w.Write([]byte("Failed to check deck access."))
@@ -208,7 +233,7 @@ func Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	text, err = processCardText(text)
+	text, err = database.ProcessCardText(text)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		_, _ = w.Write([]byte(err.Error()))
@@ -286,7 +311,7 @@ func SetImage(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	hasDeckAccess, err := database.UserHasDeckAccess(userId, deckId)
+	hasDeckAccess, err := database.UserHasDeckAccess(userId, deckId, "EDIT")
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		_, _ = w.Write([]byte("Failed to check deck access."))
@@ -311,9 +336,16 @@ func SetImage(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		if len(imageBytes) > 65000 {
+		if len(imageBytes) > 10<<20 {
 			w.WriteHeader(http.StatusBadRequest)
-			_, _ = w.Write([]byte("Image cannot be over 65 KB in size"))
+			_, _ = w.Write([]byte("Image cannot be over 10 MB in size"))
+			return
+		}
+
+		imageBytes, err = processCardImage(imageBytes)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(err.Error()))
 			return
 		}
 	}
@@ -329,6 +361,143 @@ func SetImage(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 }
 
+// GetImage serves a card image with an ETag so browsers can cache it. Pages
+// link to it with the image version in the query string.
+func GetImage(w http.ResponseWriter, r *http.Request) {
+	cardIdString := r.PathValue("cardId")
+	cardId, err := uuid.Parse(cardIdString)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to get card id from path."))
+		return
+	}
+
+	userId := api.GetUserId(r)
+	if userId == uuid.Nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte("Failed to get user id."))
+		return
+	}
+
+	canSeeCard, err := database.UserCanSeeCardImage(userId, cardId)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("Failed to check card access."))
+		return
+	}
+
+	if !canSeeCard {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte("User does not have access."))
+		return
+	}
+
+	imageBytes, err := database.GetCardImage(cardId)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(err.Error()))
+		return
+	}
+
+	if imageBytes == nil {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte("Card has no image."))
+		return
+	}
+
+	// matches the image version selected alongside cards
+	etag := fmt.Sprintf(`"%x"`, sha1.Sum(imageBytes))
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "private, max-age=86400")
+
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", http.DetectContentType(imageBytes))
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(imageBytes)
+}
+
+func SetTags(w http.ResponseWriter, r *http.Request) {
+	cardIdString := r.PathValue("cardId")
+	cardId, err := uuid.Parse(cardIdString)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to get card id from path."))
+		return
+	}
+
+	err = r.ParseForm()
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to parse form."))
+		return
+	}
+
+	var tags []string
+	var contentRating string
+	for key, val := range r.Form {
+		switch key {
+		case "tags":
+			tags = database.ParseTags(val[0])
+		case "contentRating":
+			contentRating = val[0]
+		}
+	}
+
+	if !database.IsValidContentRating(contentRating) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Invalid content rating."))
+		return
+	}
+
+	userId := api.GetUserId(r)
+	if userId == uuid.Nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to get user id."))
+		return
+	}
+
+	card, err := database.GetCard(cardId)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to get card."))
+		return
+	}
+
+	hasDeckAccess, err := database.UserHasDeckAccess(userId, card.DeckId, "EDIT")
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("Failed to check deck access."))
+		return
+	}
+
+	if !hasDeckAccess {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte("User does not have access."))
+		return
+	}
+
+	err = database.SetCardTags(cardId, tags)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(err.Error()))
+		return
+	}
+
+	err = database.SetCardContentRating(cardId, contentRating)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(err.Error()))
+		return
+	}
+
+	w.Header().Add("HX-Refresh", "true")
+	w.WriteHeader(http.StatusOK)
+}
+
 func Delete(w http.ResponseWriter, r *http.Request) {
 	cardIdString := r.PathValue("cardId")
 	cardId, err := uuid.Parse(cardIdString)
@@ -352,7 +521,7 @@ func Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	hasDeckAccess, err := database.UserHasDeckAccess(userId, card.DeckId)
+	hasDeckAccess, err := database.UserHasDeckAccess(userId, card.DeckId, "EDIT")
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		_, _ = w.Write([]byte("Failed to check deck access."))
@@ -416,16 +585,378 @@ func PermanentlyDelete(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 }
 
-func processCardText(text string) (string, error) {
-	normalizedText := text
+func Revert(w http.ResponseWriter, r *http.Request) {
+	idString := r.PathValue("Id")
+	id, err := uuid.Parse(idString)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to get id from path."))
+		return
+	}
+
+	userId := api.GetUserId(r)
+	if userId == uuid.Nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to get user id."))
+		return
+	}
+
+	deckId, err := database.GetCardVersionDeckId(id)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to get card version."))
+		return
+	}
+
+	hasDeckAccess, err := database.UserHasDeckAccess(userId, deckId, "EDIT")
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("Failed to check deck access."))
+		return
+	}
+
+	if !hasDeckAccess {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte("User does not have access."))
+		return
+	}
+
+	isReverted, err := database.RevertCard(id)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(err.Error()))
+		return
+	}
+
+	if !isReverted {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Card text already exists."))
+		return
+	}
+
+	w.Header().Add("HX-Refresh", "true")
+	w.WriteHeader(http.StatusOK)
+}
+
+func Suggest(w http.ResponseWriter, r *http.Request) {
+	err := r.ParseForm()
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to parse form."))
+		return
+	}
+
+	var deckId uuid.UUID
+	var cardId uuid.UUID
+	var category string
+	var text string
+	var youtube string
+	for key, val := range r.Form {
+		switch key {
+		case "deckId":
+			deckId, err = uuid.Parse(val[0])
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte("Failed to parse deck id."))
+				return
+			}
+		case "cardId":
+			// a nil card id suggests a new card
+			cardId, err = uuid.Parse(val[0])
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte("Failed to parse card id."))
+				return
+			}
+		case "category":
+			category = val[0]
+		case "text":
+			text = val[0]
+		case "youtube":
+			youtube = val[0]
+		}
+	}
+
+	userId := api.GetUserId(r)
+	if userId == uuid.Nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to get user id."))
+		return
+	}
+
+	hasDeckAccess, err := database.UserHasDeckAccess(userId, deckId, "READ")
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("Failed to check deck access."))
+		return
+	}
+
+	// anyone can suggest cards for a public read-only deck
+	if !hasDeckAccess {
+		deck, err := database.GetDeck(deckId)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte("Failed to get deck."))
+			return
+		}
+
+		hasDeckAccess = deck.IsPublicReadOnly
+	}
+
+	if !hasDeckAccess {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte("User does not have access."))
+		return
+	}
+
+	if cardId != uuid.Nil {
+		card, err := database.GetCard(cardId)
+		if err != nil || card.DeckId != deckId {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte("Failed to get card."))
+			return
+		}
+	}
+
+	text, err = database.ProcessCardText(text)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(err.Error()))
+		return
+	}
+
+	if text == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("No text found."))
+		return
+	}
+
+	existingCardId, err := database.GetCardId(deckId, text)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(err.Error()))
+		return
+	}
+
+	if existingCardId != uuid.Nil && existingCardId != cardId {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Card text already exists."))
+		return
+	}
+
+	if len(youtube) != 0 && len(youtube) != 11 {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Invalid YouTube Video ID."))
+		return
+	}
+
+	if category != "PROMPT" && category != "RESPONSE" {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Invalid category."))
+		return
+	}
+
+	err = database.CreateCardSuggestion(deckId, cardId, userId, category, text, youtube)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(err.Error()))
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	_, _ = w.Write([]byte("Suggestion sent to the deck editors."))
+}
+
+func ApproveSuggestion(w http.ResponseWriter, r *http.Request) {
+	suggestionIdString := r.PathValue("suggestionId")
+	suggestionId, err := uuid.Parse(suggestionIdString)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to get suggestion id from path."))
+		return
+	}
+
+	err = r.ParseForm()
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to parse form."))
+		return
+	}
+
+	var category string
+	var text string
+	var youtube string
+	var note string
+	for key, val := range r.Form {
+		switch key {
+		case "category":
+			category = val[0]
+		case "text":
+			text = val[0]
+		case "youtube":
+			youtube = val[0]
+		case "note":
+			note = val[0]
+		}
+	}
+
+	userId := api.GetUserId(r)
+	if userId == uuid.Nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to get user id."))
+		return
+	}
+
+	suggestion, err := database.GetCardSuggestion(suggestionId)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to get suggestion."))
+		return
+	}
+
+	// an edited card may have moved since the suggestion was made
+	deckId := suggestion.DeckId
+	if suggestion.CardId.Valid {
+		card, err := database.GetCard(suggestion.CardId.UUID)
+		if err != nil || card.Id == uuid.Nil {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte("Failed to get card."))
+			return
+		}
+		deckId = card.DeckId
+	}
+
+	hasDeckAccess, err := database.UserHasDeckAccess(userId, deckId, "EDIT")
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("Failed to check deck access."))
+		return
+	}
+
+	if !hasDeckAccess {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte("User does not have access."))
+		return
+	}
+
+	if suggestion.Status != "PENDING" {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Suggestion has already been reviewed."))
+		return
+	}
+
+	text, err = database.ProcessCardText(text)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(err.Error()))
+		return
+	}
+
+	if text == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("No text found."))
+		return
+	}
+
+	existingCardId, err := database.GetCardId(deckId, text)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(err.Error()))
+		return
+	}
+
+	if existingCardId != uuid.Nil && existingCardId != suggestion.CardId.UUID {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Card text already exists."))
+		return
+	}
+
+	if len(youtube) != 0 && len(youtube) != 11 {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Invalid YouTube Video ID."))
+		return
+	}
+
+	if category != "PROMPT" && category != "RESPONSE" {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Invalid category."))
+		return
+	}
+
+	err = database.ApproveCardSuggestion(suggestionId, userId, deckId, suggestion.CardId, category, text, youtube, note)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(err.Error()))
+		return
+	}
+
+	w.Header().Add("HX-Refresh", "true")
+	w.WriteHeader(http.StatusOK)
+}
+
+func RejectSuggestion(w http.ResponseWriter, r *http.Request) {
+	suggestionIdString := r.PathValue("suggestionId")
+	suggestionId, err := uuid.Parse(suggestionIdString)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to get suggestion id from path."))
+		return
+	}
 
-	blankRegExp, err := regexp.Compile(`__+`)
+	err = r.ParseForm()
 	if err != nil {
-		return normalizedText, err
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to parse form."))
+		return
 	}
 
-	normalizedText = blankRegExp.ReplaceAllString(text, "_____")
-	normalizedText = strings.TrimSpace(normalizedText)
+	var note string
+	for key, val := range r.Form {
+		if key == "note" {
+			note = val[0]
+		}
+	}
 
-	return normalizedText, err
+	userId := api.GetUserId(r)
+	if userId == uuid.Nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to get user id."))
+		return
+	}
+
+	suggestion, err := database.GetCardSuggestion(suggestionId)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to get suggestion."))
+		return
+	}
+
+	hasDeckAccess, err := database.UserHasDeckAccess(userId, suggestion.DeckId, "EDIT")
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("Failed to check deck access."))
+		return
+	}
+
+	if !hasDeckAccess {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte("User does not have access."))
+		return
+	}
+
+	if suggestion.Status != "PENDING" {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Suggestion has already been reviewed."))
+		return
+	}
+
+	err = database.SetCardSuggestionReviewed(suggestionId, userId, "REJECTED", note)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(err.Error()))
+		return
+	}
+
+	w.Header().Add("HX-Refresh", "true")
+	w.WriteHeader(http.StatusOK)
 }
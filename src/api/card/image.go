@@ -0,0 +1,214 @@
+package apiCard
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+// cards store images in a BLOB column
+const maxImageBytes = 65000
+
+const maxImageDimension = 512
+const minImageDimension = 32
+
+// refuse to decode anything that would take an unreasonable amount of memory
+const maxImagePixels = 40_000_000
+
+// processCardImage decodes an uploaded PNG, JPEG, GIF or WebP image and
+// re-encodes it small enough to store. Re-encoding drops any EXIF metadata,
+// so the orientation is applied to the pixels first.
+func processCardImage(imageBytes []byte) ([]byte, error) {
+	config, format, err := image.DecodeConfig(bytes.NewReader(imageBytes))
+	if err != nil {
+		return nil, errors.New("image must be a PNG, JPEG, GIF or WebP")
+	}
+
+	if config.Width*config.Height > maxImagePixels {
+		return nil, errors.New("image dimensions are too large")
+	}
+
+	// only the first frame of an animated GIF is kept
+	img, _, err := image.Decode(bytes.NewReader(imageBytes))
+	if err != nil {
+		return nil, errors.New("failed to decode image")
+	}
+
+	if format == "jpeg" {
+		img = applyExifOrientation(img, getExifOrientation(imageBytes))
+	}
+
+	// keep transparency by using PNG, everything else compresses better as JPEG
+	usePNG := format != "jpeg" && !isOpaque(img)
+
+	scale := min(1, float64(maxImageDimension)/float64(max(img.Bounds().Dx(), img.Bounds().Dy())))
+	for {
+		scaled := scaleImage(img, scale)
+		if scaled.Bounds().Dx() < minImageDimension && scaled.Bounds().Dy() < minImageDimension {
+			return nil, errors.New("image cannot be made small enough to store")
+		}
+
+		if usePNG {
+			var buffer bytes.Buffer
+			encoder := png.Encoder{CompressionLevel: png.BestCompression}
+			err = encoder.Encode(&buffer, scaled)
+			if err != nil {
+				return nil, errors.New("failed to encode image")
+			}
+
+			if buffer.Len() <= maxImageBytes {
+				return buffer.Bytes(), nil
+			}
+		} else {
+			for _, quality := range []int{85, 70, 55, 40} {
+				var buffer bytes.Buffer
+				err = jpeg.Encode(&buffer, scaled, &jpeg.Options{Quality: quality})
+				if err != nil {
+					return nil, errors.New("failed to encode image")
+				}
+
+				if buffer.Len() <= maxImageBytes {
+					return buffer.Bytes(), nil
+				}
+			}
+		}
+
+		scale *= 0.75
+	}
+}
+
+func scaleImage(img image.Image, scale float64) image.Image {
+	if scale >= 1 {
+		return img
+	}
+
+	bounds := img.Bounds()
+	width := max(1, int(float64(bounds.Dx())*scale))
+	height := max(1, int(float64(bounds.Dy())*scale))
+	scaled := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(scaled, scaled.Bounds(), img, bounds, draw.Src, nil)
+	return scaled
+}
+
+func isOpaque(img image.Image) bool {
+	if opaque, ok := img.(interface{ Opaque() bool }); ok {
+		return opaque.Opaque()
+	}
+
+	bounds := img.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			_, _, _, a := img.At(x, y).RGBA()
+			if a != 0xffff {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// getExifOrientation finds the orientation tag in a JPEG's EXIF segment,
+// returning 1 (no change) when there is none.
+func getExifOrientation(imageBytes []byte) int {
+	// walk the JPEG segments looking for APP1
+	i := 2
+	for i+4 <= len(imageBytes) && imageBytes[i] == 0xFF {
+		marker := imageBytes[i+1]
+		length := int(binary.BigEndian.Uint16(imageBytes[i+2:]))
+		if marker == 0xDA || i+2+length > len(imageBytes) {
+			break
+		}
+
+		segment := imageBytes[i+4 : i+2+length]
+		if marker == 0xE1 && len(segment) > 14 && string(segment[:6]) == "Exif\x00\x00" {
+			return getTiffOrientation(segment[6:])
+		}
+
+		i += 2 + length
+	}
+	return 1
+}
+
+func getTiffOrientation(tiff []byte) int {
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	offset := int(order.Uint32(tiff[4:]))
+	if offset+2 > len(tiff) {
+		return 1
+	}
+
+	entryCount := int(order.Uint16(tiff[offset:]))
+	for e := 0; e < entryCount; e++ {
+		entry := offset + 2 + e*12
+		if entry+12 > len(tiff) {
+			break
+		}
+
+		if order.Uint16(tiff[entry:]) == 0x0112 {
+			orientation := int(order.Uint16(tiff[entry+8:]))
+			if orientation >= 1 && orientation <= 8 {
+				return orientation
+			}
+			break
+		}
+	}
+	return 1
+}
+
+// applyExifOrientation rotates and flips the image so it displays upright
+// without the EXIF orientation tag.
+func applyExifOrientation(img image.Image, orientation int) image.Image {
+	if orientation <= 1 || orientation > 8 {
+		return img
+	}
+
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	// orientations 5 through 8 swap width and height
+	outWidth, outHeight := width, height
+	if orientation >= 5 {
+		outWidth, outHeight = height, width
+	}
+
+	oriented := image.NewRGBA(image.Rect(0, 0, outWidth, outHeight))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			var outX, outY int
+			switch orientation {
+			case 2:
+				outX, outY = width-1-x, y
+			case 3:
+				outX, outY = width-1-x, height-1-y
+			case 4:
+				outX, outY = x, height-1-y
+			case 5:
+				outX, outY = y, x
+			case 6:
+				outX, outY = height-1-y, x
+			case 7:
+				outX, outY = height-1-y, width-1-x
+			case 8:
+				outX, outY = y, width-1-x
+			}
+			oriented.Set(outX, outY, color.RGBAModel.Convert(img.At(bounds.Min.X+x, bounds.Min.Y+y)))
+		}
+	}
+	return oriented
+}
@@ -0,0 +1,374 @@
+package apiDeck
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/google/uuid"
+	"github.com/grantfbarnes/card-judge/api"
+	"github.com/grantfbarnes/card-judge/database"
+	"github.com/grantfbarnes/card-judge/static"
+)
+
+func BulkEdit(w http.ResponseWriter, r *http.Request) {
+	deckIdString := r.PathValue("deckId")
+	deckId, err := uuid.Parse(deckIdString)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to get deck id from path."))
+		return
+	}
+
+	userId := api.GetUserId(r)
+	if userId == uuid.Nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to get user id."))
+		return
+	}
+
+	hasDeckAccess, err := database.UserHasDeckAccess(userId, deckId, "EDIT")
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("Failed to check deck access."))
+		return
+	}
+
+	if !hasDeckAccess {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte("User does not have access."))
+		return
+	}
+
+	err = r.ParseForm()
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to parse form."))
+		return
+	}
+
+	selection, err := getCardSelection(r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(err.Error()))
+		return
+	}
+
+	var operation string
+	var targetDeckId uuid.UUID
+	var category string
+	var tags []string
+	for key, val := range r.Form {
+		switch key {
+		case "operation":
+			operation = val[0]
+		case "targetDeckId":
+			if val[0] == "" {
+				continue
+			}
+			targetDeckId, err = uuid.Parse(val[0])
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte("Failed to parse target deck id."))
+				return
+			}
+		case "newCategory":
+			category = val[0]
+		case "tags":
+			tags = database.ParseTags(val[0])
+		}
+	}
+
+	var count int
+	switch operation {
+	case "MOVE":
+		if targetDeckId == uuid.Nil || targetDeckId == deckId {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte("Another deck is required."))
+			return
+		}
+
+		var hasTargetDeckAccess bool
+		hasTargetDeckAccess, err = database.UserHasDeckAccess(userId, targetDeckId, "EDIT")
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte("Failed to check deck access."))
+			return
+		}
+
+		if !hasTargetDeckAccess {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte("User does not have access to the target deck."))
+			return
+		}
+
+		var targetDeck database.Deck
+		targetDeck, err = database.GetDeck(targetDeckId)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte(err.Error()))
+			return
+		}
+
+		count, err = database.BulkMoveCards(deckId, userId, selection, targetDeckId, targetDeck.Name)
+	case "CATEGORY":
+		if category != "PROMPT" && category != "RESPONSE" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte("Invalid category."))
+			return
+		}
+
+		count, err = database.BulkSetCardCategory(deckId, userId, selection, category)
+	case "REPLACE":
+		find, replace, findErr := getFindReplace(r)
+		if findErr != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(findErr.Error()))
+			return
+		}
+
+		count, err = database.BulkReplaceCardText(deckId, userId, selection, find, replace)
+	case "DELETE":
+		count, err = database.BulkDeleteCards(deckId, userId, selection)
+	case "ADD_TAGS", "REMOVE_TAGS":
+		if len(tags) == 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte("No tags found."))
+			return
+		}
+
+		if operation == "ADD_TAGS" {
+			count, err = database.BulkAddCardTags(deckId, userId, selection, tags)
+		} else {
+			count, err = database.BulkRemoveCardTags(deckId, userId, selection, tags)
+		}
+	case "CLEAR_IMAGES":
+		count, err = database.BulkClearCardImages(deckId, userId, selection)
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Invalid operation."))
+		return
+	}
+
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(err.Error()))
+		return
+	}
+
+	if count == 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("No cards would change."))
+		return
+	}
+
+	w.Header().Add("HX-Refresh", "true")
+	w.WriteHeader(http.StatusOK)
+}
+
+func BulkReplacePreview(w http.ResponseWriter, r *http.Request) {
+	deckIdString := r.PathValue("deckId")
+	deckId, err := uuid.Parse(deckIdString)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to get deck id from path."))
+		return
+	}
+
+	userId := api.GetUserId(r)
+	if userId == uuid.Nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to get user id."))
+		return
+	}
+
+	hasDeckAccess, err := database.UserHasDeckAccess(userId, deckId, "EDIT")
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("Failed to check deck access."))
+		return
+	}
+
+	if !hasDeckAccess {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte("User does not have access."))
+		return
+	}
+
+	err = r.ParseForm()
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to parse form."))
+		return
+	}
+
+	selection, err := getCardSelection(r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(err.Error()))
+		return
+	}
+
+	find, replace, err := getFindReplace(r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(err.Error()))
+		return
+	}
+
+	replacements, err := database.GetCardReplacements(deckId, selection, find, replace)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(err.Error()))
+		return
+	}
+
+	tmpl, err := template.ParseFS(
+		static.StaticFiles,
+		"html/components/tables/card-replace-table.html",
+	)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("Failed to parse HTML."))
+		return
+	}
+
+	_ = tmpl.ExecuteTemplate(w, "card-replace-table", replacements)
+}
+
+func UndoCardBatch(w http.ResponseWriter, r *http.Request) {
+	deckIdString := r.PathValue("deckId")
+	deckId, err := uuid.Parse(deckIdString)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to get deck id from path."))
+		return
+	}
+
+	batchIdString := r.PathValue("batchId")
+	batchId, err := uuid.Parse(batchIdString)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to get batch id from path."))
+		return
+	}
+
+	userId := api.GetUserId(r)
+	if userId == uuid.Nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to get user id."))
+		return
+	}
+
+	cardBatch, err := database.GetCardBatch(batchId)
+	if err != nil || cardBatch.DeckId != deckId {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to get batch."))
+		return
+	}
+
+	// moved cards are undone out of the target deck as well
+	deckIds := []uuid.UUID{deckId}
+	if cardBatch.TargetDeckId.Valid {
+		deckIds = append(deckIds, cardBatch.TargetDeckId.UUID)
+	}
+
+	for _, accessDeckId := range deckIds {
+		hasDeckAccess, err := database.UserHasDeckAccess(userId, accessDeckId, "EDIT")
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte("Failed to check deck access."))
+			return
+		}
+
+		if !hasDeckAccess {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte("User does not have access."))
+			return
+		}
+	}
+
+	if cardBatch.UndoneOnDate.Valid {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Batch has already been undone."))
+		return
+	}
+
+	err = database.UndoCardBatch(batchId)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("Failed to undo batch, a card may have since been given the same text."))
+		return
+	}
+
+	w.Header().Add("HX-Refresh", "true")
+	w.WriteHeader(http.StatusOK)
+}
+
+// getCardSelection reads the checked cards, or the deck page search when the
+// scope is the whole search.
+func getCardSelection(r *http.Request) (database.CardSelection, error) {
+	var selection database.CardSelection
+	for key, val := range r.Form {
+		switch key {
+		case "scope":
+			selection.UseSearch = val[0] == "SEARCH"
+		case "category":
+			selection.Category = val[0]
+		case "text":
+			selection.Text = val[0]
+		case "tag":
+			selection.Tag = val[0]
+		default:
+			if strings.HasPrefix(key, "cardId") {
+				cardId, err := uuid.Parse(val[0])
+				if err != nil {
+					return selection, errors.New("failed to parse card id")
+				}
+				selection.CardIds = append(selection.CardIds, cardId)
+			}
+		}
+	}
+
+	if !selection.UseSearch && len(selection.CardIds) == 0 {
+		return selection, errors.New("no cards selected")
+	}
+
+	return selection, nil
+}
+
+// getFindReplace treats the find text literally unless it is marked as a
+// regular expression.
+func getFindReplace(r *http.Request) (*regexp.Regexp, string, error) {
+	var find string
+	var replace string
+	var isRegex bool
+	for key, val := range r.Form {
+		switch key {
+		case "find":
+			find = val[0]
+		case "replace":
+			replace = val[0]
+		case "isRegex":
+			isRegex = true
+		}
+	}
+
+	if find == "" {
+		return nil, replace, errors.New("no find text found")
+	}
+
+	if !isRegex {
+		return regexp.MustCompile(regexp.QuoteMeta(find)), strings.ReplaceAll(replace, "$", "$$"), nil
+	}
+
+	findRegExp, err := regexp.Compile(find)
+	if err != nil {
+		return nil, replace, fmt.Errorf("invalid regular expression: %s", err)
+	}
+
+	return findRegExp, replace, nil
+}
@@ -2,6 +2,8 @@ package apiDeck
 
 import (
 	"encoding/csv"
+	"fmt"
+	"html"
 	"net/http"
 
 	"github.com/google/uuid"
@@ -26,7 +28,7 @@ func GetCardExport(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	hasDeckAccess, err := database.UserHasDeckAccess(userId, deckId)
+	hasDeckAccess, err := database.UserHasDeckAccess(userId, deckId, "READ")
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		_, _ = w.Write([]byte("Failed to check deck access."))
@@ -123,7 +125,7 @@ func Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = database.AddUserDeckAccess(userId, id)
+	err = database.AddUserDeckAccess(userId, id, "OWNER")
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		_, _ = w.Write([]byte(err.Error()))
@@ -134,6 +136,216 @@ func Create(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusCreated)
 }
 
+func Clone(w http.ResponseWriter, r *http.Request) {
+	deckIdString := r.PathValue("deckId")
+	deckId, err := uuid.Parse(deckIdString)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to get deck id from path."))
+		return
+	}
+
+	userId := api.GetUserId(r)
+	if userId == uuid.Nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to get user id."))
+		return
+	}
+
+	hasDeckAccess, err := database.UserHasDeckAccess(userId, deckId, "READ")
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("Failed to check deck access."))
+		return
+	}
+
+	if !hasDeckAccess {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte("User does not have access."))
+		return
+	}
+
+	err = r.ParseForm()
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to parse form."))
+		return
+	}
+
+	var name string
+	var password string
+	var passwordConfirm string
+	var isPublicReadOnly bool
+	for key, val := range r.Form {
+		switch key {
+		case "name":
+			name = val[0]
+		case "password":
+			password = val[0]
+		case "passwordConfirm":
+			passwordConfirm = val[0]
+		case "isPublicReadOnly":
+			isPublicReadOnly = val[0] == "1"
+		}
+	}
+
+	if name == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("No name found."))
+		return
+	}
+
+	if password == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("No password found."))
+		return
+	}
+
+	if password != passwordConfirm {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Passwords do not match."))
+		return
+	}
+
+	existingDeckId, err := database.GetDeckId(name)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(err.Error()))
+		return
+	}
+	if existingDeckId != uuid.Nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Deck name already exists."))
+		return
+	}
+
+	id, err := database.CloneDeck(userId, deckId, name, password, isPublicReadOnly)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(err.Error()))
+		return
+	}
+
+	w.Header().Add("HX-Redirect", "/deck/"+id.String())
+	w.WriteHeader(http.StatusCreated)
+}
+
+func Merge(w http.ResponseWriter, r *http.Request) {
+	deckIdString := r.PathValue("deckId")
+	deckId, err := uuid.Parse(deckIdString)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to get deck id from path."))
+		return
+	}
+
+	userId := api.GetUserId(r)
+	if userId == uuid.Nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to get user id."))
+		return
+	}
+
+	hasDeckAccess, err := database.UserHasDeckAccess(userId, deckId, "EDIT")
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("Failed to check deck access."))
+		return
+	}
+
+	if !hasDeckAccess {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte("User does not have access."))
+		return
+	}
+
+	err = r.ParseForm()
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to parse form."))
+		return
+	}
+
+	var sourceDeckId uuid.UUID
+	var move bool
+	for key, val := range r.Form {
+		switch key {
+		case "sourceDeckId":
+			sourceDeckId, err = uuid.Parse(val[0])
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte("Failed to parse source deck id."))
+				return
+			}
+		case "mode":
+			move = val[0] == "MOVE"
+		}
+	}
+
+	if sourceDeckId == uuid.Nil || sourceDeckId == deckId {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Must select a different deck to merge from."))
+		return
+	}
+
+	// moving cards removes them from the source deck, so it needs edit access
+	sourceCapability := "READ"
+	if move {
+		sourceCapability = "EDIT"
+	}
+
+	hasSourceDeckAccess, err := database.UserHasDeckAccess(userId, sourceDeckId, sourceCapability)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("Failed to check deck access."))
+		return
+	}
+
+	if !hasSourceDeckAccess {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte("User does not have access to the source deck."))
+		return
+	}
+
+	sourceCardCount, err := database.CountCardsInDeck(sourceDeckId, "", "", "")
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(err.Error()))
+		return
+	}
+
+	conflicts, err := database.GetDeckMergeConflicts(sourceDeckId, deckId)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(err.Error()))
+		return
+	}
+
+	err = database.MergeDeck(sourceDeckId, deckId, move)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(err.Error()))
+		return
+	}
+
+	verb := "Copied"
+	if move {
+		verb = "Moved"
+	}
+
+	result := fmt.Sprintf("%s %d cards.", verb, sourceCardCount-len(conflicts))
+	if len(conflicts) > 0 {
+		result += fmt.Sprintf(" Skipped %d cards that already exist in this deck:<ul>", len(conflicts))
+		for _, conflict := range conflicts {
+			result += "<li>" + html.EscapeString(conflict.Text) + "</li>"
+		}
+		result += "</ul>"
+	}
+
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(result))
+}
+
 func SetName(w http.ResponseWriter, r *http.Request) {
 	deckIdString := r.PathValue("deckId")
 	deckId, err := uuid.Parse(deckIdString)
@@ -150,7 +362,7 @@ func SetName(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	hasDeckAccess, err := database.UserHasDeckAccess(userId, deckId)
+	hasDeckAccess, err := database.UserHasDeckAccess(userId, deckId, "MANAGE")
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		_, _ = w.Write([]byte("Failed to check deck access."))
@@ -223,7 +435,7 @@ func SetPassword(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	hasDeckAccess, err := database.UserHasDeckAccess(userId, deckId)
+	hasDeckAccess, err := database.UserHasDeckAccess(userId, deckId, "MANAGE")
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		_, _ = w.Write([]byte("Failed to check deck access."))
@@ -315,7 +527,7 @@ func SetIsPublicReadOnly(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	hasDeckAccess, err := database.UserHasDeckAccess(userId, deckId)
+	hasDeckAccess, err := database.UserHasDeckAccess(userId, deckId, "MANAGE")
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		_, _ = w.Write([]byte("Failed to check deck access."))
@@ -353,6 +565,66 @@ func SetIsPublicReadOnly(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 }
 
+func SetContentRating(w http.ResponseWriter, r *http.Request) {
+	deckIdString := r.PathValue("deckId")
+	deckId, err := uuid.Parse(deckIdString)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to get deck id from path."))
+		return
+	}
+
+	userId := api.GetUserId(r)
+	if userId == uuid.Nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to get user id."))
+		return
+	}
+
+	hasDeckAccess, err := database.UserHasDeckAccess(userId, deckId, "MANAGE")
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("Failed to check deck access."))
+		return
+	}
+
+	if !hasDeckAccess {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte("User does not have access."))
+		return
+	}
+
+	err = r.ParseForm()
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to parse form."))
+		return
+	}
+
+	var contentRating string
+	for key, val := range r.Form {
+		if key == "contentRating" {
+			contentRating = val[0]
+		}
+	}
+
+	if !database.IsValidContentRating(contentRating) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Invalid content rating."))
+		return
+	}
+
+	err = database.SetDeckContentRating(deckId, contentRating)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(err.Error()))
+		return
+	}
+
+	w.Header().Add("HX-Refresh", "true")
+	w.WriteHeader(http.StatusOK)
+}
+
 func Delete(w http.ResponseWriter, r *http.Request) {
 	deckIdString := r.PathValue("deckId")
 	deckId, err := uuid.Parse(deckIdString)
@@ -369,7 +641,7 @@ func Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	hasDeckAccess, err := database.UserHasDeckAccess(userId, deckId)
+	hasDeckAccess, err := database.UserHasDeckAccess(userId, deckId, "MANAGE")
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		_, _ = w.Write([]byte("Failed to check deck access."))
@@ -392,3 +664,307 @@ func Delete(w http.ResponseWriter, r *http.Request) {
 	w.Header().Add("HX-Redirect", "/decks")
 	w.WriteHeader(http.StatusOK)
 }
+
+func SetPasswordRole(w http.ResponseWriter, r *http.Request) {
+	deckIdString := r.PathValue("deckId")
+	deckId, err := uuid.Parse(deckIdString)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to get deck id from path."))
+		return
+	}
+
+	userId := api.GetUserId(r)
+	if userId == uuid.Nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to get user id."))
+		return
+	}
+
+	hasDeckAccess, err := database.UserHasDeckAccess(userId, deckId, "MANAGE")
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("Failed to check deck access."))
+		return
+	}
+
+	if !hasDeckAccess {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte("User does not have access."))
+		return
+	}
+
+	err = r.ParseForm()
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to parse form."))
+		return
+	}
+
+	var passwordRole string
+	for key, val := range r.Form {
+		if key == "passwordRole" {
+			passwordRole = val[0]
+		}
+	}
+
+	switch passwordRole {
+	case "NONE", "VIEWER", "EDITOR":
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Invalid password role."))
+		return
+	}
+
+	err = database.SetDeckPasswordRole(deckId, passwordRole)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(err.Error()))
+		return
+	}
+
+	w.Header().Add("HX-Refresh", "true")
+	w.WriteHeader(http.StatusOK)
+}
+
+func InviteUser(w http.ResponseWriter, r *http.Request) {
+	deckIdString := r.PathValue("deckId")
+	deckId, err := uuid.Parse(deckIdString)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to get deck id from path."))
+		return
+	}
+
+	userId := api.GetUserId(r)
+	if userId == uuid.Nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to get user id."))
+		return
+	}
+
+	hasDeckAccess, err := database.UserHasDeckAccess(userId, deckId, "MANAGE")
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("Failed to check deck access."))
+		return
+	}
+
+	if !hasDeckAccess {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte("User does not have access."))
+		return
+	}
+
+	err = r.ParseForm()
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to parse form."))
+		return
+	}
+
+	var userName string
+	var role string
+	for key, val := range r.Form {
+		switch key {
+		case "userName":
+			userName = val[0]
+		case "role":
+			role = val[0]
+		}
+	}
+
+	if !isValidDeckRole(role) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Invalid role."))
+		return
+	}
+
+	inviteUserId, err := database.GetUserIdByName(userName)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("User not found."))
+		return
+	}
+
+	if inviteUserId == userId {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Cannot change your own role."))
+		return
+	}
+
+	err = database.SetUserDeckAccess(inviteUserId, deckId, role)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(err.Error()))
+		return
+	}
+
+	w.Header().Add("HX-Refresh", "true")
+	w.WriteHeader(http.StatusOK)
+}
+
+func SetUserRole(w http.ResponseWriter, r *http.Request) {
+	deckIdString := r.PathValue("deckId")
+	deckId, err := uuid.Parse(deckIdString)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to get deck id from path."))
+		return
+	}
+
+	subjectUserIdString := r.PathValue("userId")
+	subjectUserId, err := uuid.Parse(subjectUserIdString)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to get user id from path."))
+		return
+	}
+
+	userId := api.GetUserId(r)
+	if userId == uuid.Nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to get user id."))
+		return
+	}
+
+	hasDeckAccess, err := database.UserHasDeckAccess(userId, deckId, "MANAGE")
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("Failed to check deck access."))
+		return
+	}
+
+	if !hasDeckAccess {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte("User does not have access."))
+		return
+	}
+
+	err = r.ParseForm()
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to parse form."))
+		return
+	}
+
+	var role string
+	for key, val := range r.Form {
+		if key == "role" {
+			role = val[0]
+		}
+	}
+
+	if !isValidDeckRole(role) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Invalid role."))
+		return
+	}
+
+	subjectRole, err := database.GetUserDeckRole(subjectUserId, deckId)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(err.Error()))
+		return
+	}
+
+	if subjectRole == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("User does not have access to this deck."))
+		return
+	}
+
+	if role != "OWNER" {
+		otherOwnerCount, err := database.GetDeckOtherOwnerCount(deckId, subjectUserId)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte(err.Error()))
+			return
+		}
+
+		if otherOwnerCount == 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte("A deck must have at least one owner."))
+			return
+		}
+	}
+
+	err = database.SetUserDeckRole(subjectUserId, deckId, role)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(err.Error()))
+		return
+	}
+
+	w.Header().Add("HX-Refresh", "true")
+	w.WriteHeader(http.StatusOK)
+}
+
+func RevokeUser(w http.ResponseWriter, r *http.Request) {
+	deckIdString := r.PathValue("deckId")
+	deckId, err := uuid.Parse(deckIdString)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to get deck id from path."))
+		return
+	}
+
+	subjectUserIdString := r.PathValue("userId")
+	subjectUserId, err := uuid.Parse(subjectUserIdString)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to get user id from path."))
+		return
+	}
+
+	userId := api.GetUserId(r)
+	if userId == uuid.Nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to get user id."))
+		return
+	}
+
+	hasDeckAccess, err := database.UserHasDeckAccess(userId, deckId, "MANAGE")
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("Failed to check deck access."))
+		return
+	}
+
+	if !hasDeckAccess {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte("User does not have access."))
+		return
+	}
+
+	otherOwnerCount, err := database.GetDeckOtherOwnerCount(deckId, subjectUserId)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(err.Error()))
+		return
+	}
+
+	if otherOwnerCount == 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("A deck must have at least one owner."))
+		return
+	}
+
+	err = database.RemoveUserDeckAccess(subjectUserId, deckId)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(err.Error()))
+		return
+	}
+
+	w.Header().Add("HX-Refresh", "true")
+	w.WriteHeader(http.StatusOK)
+}
+
+func isValidDeckRole(role string) bool {
+	switch role {
+	case "VIEWER", "EDITOR", "OWNER":
+		return true
+	}
+	return false
+}
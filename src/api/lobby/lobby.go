@@ -3,6 +3,7 @@ package apiLobby
 import (
 	"errors"
 	"fmt"
+	"log"
 	"net/http"
 	"strconv"
 	"strings"
@@ -224,6 +225,53 @@ func GetLobbyGameStatsHTML(w http.ResponseWriter, r *http.Request) {
 	_ = tmpl.ExecuteTemplate(w, "lobby-game-stats", data)
 }
 
+func GetChatHistory(w http.ResponseWriter, r *http.Request) {
+	lobbyIdString := r.PathValue("lobbyId")
+	lobbyId, err := uuid.Parse(lobbyIdString)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to get lobby id from path."))
+		return
+	}
+
+	_, err = getLobbyRequestPlayer(r, lobbyId)
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(err.Error()))
+		return
+	}
+
+	before := time.Now()
+	params := r.URL.Query()
+	for key, val := range params {
+		if key == "before" {
+			beforeMilli, err := strconv.ParseInt(val[0], 10, 64)
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte("Failed to parse before."))
+				return
+			}
+			before = time.UnixMilli(beforeMilli)
+		}
+	}
+
+	chatMessages, err := database.GetLobbyChatMessages(lobbyId, before, 50)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(err.Error()))
+		return
+	}
+
+	// one message per line, in the same format the websocket replays history
+	lines := make([]string, 0)
+	for _, chatMessage := range chatMessages {
+		lines = append(lines, websocket.ChatHistoryMessage(chatMessage))
+	}
+
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(strings.Join(lines, "\n")))
+}
+
 func Create(w http.ResponseWriter, r *http.Request) {
 	err := r.ParseForm()
 	if err != nil {
@@ -243,6 +291,7 @@ func Create(w http.ResponseWriter, r *http.Request) {
 	var freeSpecialCards bool
 	var winStreakThreshold int
 	var loseStreakThreshold int
+	var cardFilters database.LobbyCardFilters
 	var deckIdsPrompt = make([]uuid.UUID, 0)
 	var deckIdsResponse = make([]uuid.UUID, 0)
 	for key, val := range r.Form {
@@ -298,6 +347,19 @@ func Create(w http.ResponseWriter, r *http.Request) {
 				_, _ = w.Write([]byte("Failed to parse lose streak threshold."))
 				return
 			}
+		} else if key == "maxContentRating" {
+			cardFilters.MaxContentRating = val[0]
+		} else if key == "includeTags" {
+			cardFilters.IncludeTags = database.ParseTags(val[0])
+		} else if key == "excludeTags" {
+			cardFilters.ExcludeTags = database.ParseTags(val[0])
+		} else if key == "deduplicateCards" {
+			cardFilters.DeduplicateCards, err = strconv.ParseBool(val[0])
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte("Failed to parse deduplicate cards."))
+				return
+			}
 		} else if strings.HasPrefix(key, "deckIdPrompt") {
 			deckId, err := uuid.Parse(val[0])
 			if err != nil {
@@ -371,6 +433,12 @@ func Create(w http.ResponseWriter, r *http.Request) {
 		loseStreakThreshold = 5
 	}
 
+	if !database.IsValidContentRating(cardFilters.MaxContentRating) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Invalid content rating."))
+		return
+	}
+
 	if len(deckIdsPrompt) == 0 {
 		w.WriteHeader(http.StatusBadRequest)
 		_, _ = w.Write([]byte("At least one prompt deck is required."))
@@ -409,6 +477,14 @@ func Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// filters must be in place before the draw pile is filled
+	err = database.SetLobbyCardFilters(lobbyId, cardFilters)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(err.Error()))
+		return
+	}
+
 	err = database.SyncDecksInLobby(lobbyId, deckIdsPrompt, deckIdsResponse)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
@@ -451,6 +527,19 @@ func PlayCard(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	handData, err := database.GetPlayerHandData(player.Id)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(err.Error()))
+		return
+	}
+
+	if handData.PlayerIsReady {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("All response slots are already filled."))
+		return
+	}
+
 	err = database.PlayCard(player.Id, cardId)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
@@ -519,9 +608,10 @@ func PurchaseCredits(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	websocket.LobbyBroadcast(lobbyId, "<green>"+player.Name+"</>: Attempted to purchase credits for an unfair advantage... Everyone else receives a credit as a result.")
+	websocket.LobbyBroadcast(lobbyId, "<green>"+websocket.EscapeText(player.Name)+"</>: Attempted to purchase credits for an unfair advantage... Everyone else receives a credit as a result.")
 	websocket.LobbyBroadcast(lobbyId, "refresh-player-specials")
 
+	go announceAchievements(lobbyId, player.UserId)
 	w.WriteHeader(http.StatusOK)
 	_, _ = w.Write([]byte("<b>Shame on you.</b><br/><br/>This action has been reported in the lobby chat and everyone else has received a credit."))
 }
@@ -542,6 +632,13 @@ func SkipJudge(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	err = checkLobbySpecialIsEnabled(lobbyId, "SKIP-JUDGE")
+	if err != nil {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(err.Error()))
+		return
+	}
+
 	err = database.SkipJudge(player.Id)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
@@ -549,8 +646,9 @@ func SkipJudge(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	websocket.LobbyBroadcast(lobbyId, "<green>"+player.Name+"</>: Skipped their turn as judge.")
+	websocket.LobbyBroadcast(lobbyId, "<green>"+websocket.EscapeText(player.Name)+"</>: Skipped their turn as judge.")
 	websocket.LobbyBroadcast(lobbyId, "refresh")
+	go announceAchievements(lobbyId, player.UserId)
 	w.WriteHeader(http.StatusOK)
 }
 
@@ -577,7 +675,7 @@ func ResetResponses(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	websocket.LobbyBroadcast(lobbyId, "<green>"+player.Name+"</>: Reset responses.")
+	websocket.LobbyBroadcast(lobbyId, "<green>"+websocket.EscapeText(player.Name)+"</>: Reset responses.")
 
 	websocket.LobbyBroadcast(lobbyId, "refresh")
 	w.WriteHeader(http.StatusOK)
@@ -599,6 +697,13 @@ func AlertLobby(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	err = checkLobbySpecialIsEnabled(lobbyId, "ALERT")
+	if err != nil {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(err.Error()))
+		return
+	}
+
 	lobby, err := database.GetLobby(lobbyId)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
@@ -650,6 +755,7 @@ func AlertLobby(w http.ResponseWriter, r *http.Request) {
 
 	websocket.PlayerBroadcast(player.Id, "refresh-player-specials")
 	websocket.LobbyBroadcast(lobbyId, fmt.Sprintf("alert;;%d;;%s;;%s", credits, player.Name, text))
+	go announceAchievements(lobbyId, player.UserId)
 	w.WriteHeader(http.StatusOK)
 }
 
@@ -669,6 +775,13 @@ func GambleCredits(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	err = checkLobbySpecialIsEnabled(lobbyId, "GAMBLE")
+	if err != nil {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(err.Error()))
+		return
+	}
+
 	lobby, err := database.GetLobby(lobbyId)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
@@ -721,6 +834,7 @@ func GambleCredits(w http.ResponseWriter, r *http.Request) {
 	}
 
 	websocket.PlayerBroadcast(player.Id, "refresh-player-specials")
+	go announceAchievements(lobbyId, player.UserId)
 	w.WriteHeader(http.StatusOK)
 	_, _ = w.Write([]byte("success"))
 }
@@ -741,6 +855,13 @@ func BetOnWin(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	err = checkLobbySpecialIsEnabled(lobbyId, "BET")
+	if err != nil {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(err.Error()))
+		return
+	}
+
 	if player.BetOnWin > 0 {
 		w.WriteHeader(http.StatusNotAcceptable)
 		_, _ = w.Write([]byte(fmt.Sprintf("A bet of %d has already been placed.", player.BetOnWin)))
@@ -794,6 +915,7 @@ func BetOnWin(w http.ResponseWriter, r *http.Request) {
 
 	websocket.LobbyBroadcast(lobbyId, "refresh-player-specials")
 	websocket.LobbyBroadcast(lobbyId, "refresh-lobby-game-board")
+	go announceAchievements(lobbyId, player.UserId)
 	w.WriteHeader(http.StatusOK)
 	_, _ = w.Write([]byte("success"))
 }
@@ -849,6 +971,13 @@ func AddExtraResponse(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	err = checkLobbySpecialIsEnabled(lobbyId, "EXTRA-RESPONSE")
+	if err != nil {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(err.Error()))
+		return
+	}
+
 	err = database.AddExtraResponse(player.Id)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
@@ -856,11 +985,12 @@ func AddExtraResponse(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	websocket.LobbyBroadcast(lobbyId, "<green>"+player.Name+"</>: Purchased an extra response.")
+	websocket.LobbyBroadcast(lobbyId, "<green>"+websocket.EscapeText(player.Name)+"</>: Purchased an extra response.")
 
 	websocket.PlayerBroadcast(player.Id, "refresh-player-hand")
 	websocket.LobbyBroadcast(lobbyId, "refresh-player-specials")
 	websocket.LobbyBroadcast(lobbyId, "refresh-lobby-game-board")
+	go announceAchievements(lobbyId, player.UserId)
 	w.WriteHeader(http.StatusOK)
 }
 
@@ -887,7 +1017,7 @@ func AddExtraResponseUndo(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	websocket.LobbyBroadcast(lobbyId, "<green>"+player.Name+"</>: Undid purchase of an extra response.")
+	websocket.LobbyBroadcast(lobbyId, "<green>"+websocket.EscapeText(player.Name)+"</>: Undid purchase of an extra response.")
 
 	websocket.PlayerBroadcast(player.Id, "refresh-player-hand")
 	websocket.LobbyBroadcast(lobbyId, "refresh-player-specials")
@@ -911,6 +1041,13 @@ func BlockResponse(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	err = checkLobbySpecialIsEnabled(lobbyId, "BLOCK-RESPONSE")
+	if err != nil {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(err.Error()))
+		return
+	}
+
 	err = r.ParseForm()
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
@@ -944,11 +1081,12 @@ func BlockResponse(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	websocket.LobbyBroadcast(lobbyId, "<green>"+player.Name+"</>: Blocked <green>"+targetPlayer.Name+"</> from responding.")
+	websocket.LobbyBroadcast(lobbyId, "<green>"+websocket.EscapeText(player.Name)+"</>: Blocked <green>"+websocket.EscapeText(targetPlayer.Name)+"</> from responding.")
 
 	websocket.PlayerBroadcast(targetPlayerId, "refresh-player-hand")
 	websocket.LobbyBroadcast(lobbyId, "refresh-player-specials")
 	websocket.LobbyBroadcast(lobbyId, "refresh-lobby-game-board")
+	go announceAchievements(lobbyId, player.UserId)
 	w.WriteHeader(http.StatusOK)
 }
 
@@ -968,6 +1106,13 @@ func PlaySurpriseCard(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	err = checkLobbySpecialIsEnabled(lobbyId, "SURPRISE")
+	if err != nil {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(err.Error()))
+		return
+	}
+
 	err = database.PlaySurpriseCard(player.Id)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
@@ -977,6 +1122,7 @@ func PlaySurpriseCard(w http.ResponseWriter, r *http.Request) {
 
 	websocket.LobbyBroadcast(lobbyId, "refresh-player-specials")
 	websocket.LobbyBroadcast(lobbyId, "refresh-lobby-game-board")
+	go announceAchievements(lobbyId, player.UserId)
 	w.WriteHeader(http.StatusOK)
 }
 
@@ -996,6 +1142,13 @@ func PlayStealCard(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	err = checkLobbySpecialIsEnabled(lobbyId, "STEAL")
+	if err != nil {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(err.Error()))
+		return
+	}
+
 	err = database.PlayStealCard(player.Id)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
@@ -1006,6 +1159,7 @@ func PlayStealCard(w http.ResponseWriter, r *http.Request) {
 	websocket.LobbyBroadcast(lobbyId, "refresh-player-hand")
 	websocket.LobbyBroadcast(lobbyId, "refresh-player-specials")
 	websocket.LobbyBroadcast(lobbyId, "refresh-lobby-game-board")
+	go announceAchievements(lobbyId, player.UserId)
 	w.WriteHeader(http.StatusOK)
 }
 
@@ -1025,6 +1179,13 @@ func PlayFindCard(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	err = checkLobbySpecialIsEnabled(lobbyId, "FIND")
+	if err != nil {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(err.Error()))
+		return
+	}
+
 	err = r.ParseForm()
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
@@ -1053,6 +1214,7 @@ func PlayFindCard(w http.ResponseWriter, r *http.Request) {
 
 	websocket.LobbyBroadcast(lobbyId, "refresh-player-specials")
 	websocket.LobbyBroadcast(lobbyId, "refresh-lobby-game-board")
+	go announceAchievements(lobbyId, player.UserId)
 	w.WriteHeader(http.StatusOK)
 }
 
@@ -1072,6 +1234,13 @@ func PlayWildCard(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	err = checkLobbySpecialIsEnabled(lobbyId, "WILD")
+	if err != nil {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(err.Error()))
+		return
+	}
+
 	err = r.ParseForm()
 	if err != nil {
 		w.WriteHeader(http.StatusBadRequest)
@@ -1086,6 +1255,13 @@ func PlayWildCard(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	text, err = database.FilterLobbyText(lobbyId, text)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(err.Error()))
+		return
+	}
+
 	existingCardId, err := database.GetCardId(lobbyId, text)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
@@ -1108,6 +1284,7 @@ func PlayWildCard(w http.ResponseWriter, r *http.Request) {
 
 	websocket.LobbyBroadcast(lobbyId, "refresh-player-specials")
 	websocket.LobbyBroadcast(lobbyId, "refresh-lobby-game-board")
+	go announceAchievements(lobbyId, player.UserId)
 	w.WriteHeader(http.StatusOK)
 }
 
@@ -1127,6 +1304,13 @@ func PerkHandSizeAdvantage(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	err = checkLobbySpecialIsEnabled(lobbyId, "PERK")
+	if err != nil {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(err.Error()))
+		return
+	}
+
 	err = database.PerkHandSizeAdvantage(player.Id)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
@@ -1138,6 +1322,7 @@ func PerkHandSizeAdvantage(w http.ResponseWriter, r *http.Request) {
 	websocket.PlayerBroadcast(player.Id, "refresh-player-specials")
 	websocket.PlayerBroadcast(player.Id, fmt.Sprintf("Perk: Your hand size is now increased by <green>%d</> more than the lobby default.", player.HandSizeAdvantage+2))
 
+	go announceAchievements(lobbyId, player.UserId)
 	w.WriteHeader(http.StatusOK)
 	_, _ = w.Write([]byte("success"))
 }
@@ -1158,6 +1343,13 @@ func PerkDiscardAdvantage(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	err = checkLobbySpecialIsEnabled(lobbyId, "PERK")
+	if err != nil {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(err.Error()))
+		return
+	}
+
 	err = database.PerkDiscardAdvantage(player.Id)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
@@ -1169,6 +1361,7 @@ func PerkDiscardAdvantage(w http.ResponseWriter, r *http.Request) {
 	websocket.PlayerBroadcast(player.Id, "refresh-player-specials")
 	websocket.PlayerBroadcast(player.Id, "Perk: You can now discard more often (whenever you cannot play a card).")
 
+	go announceAchievements(lobbyId, player.UserId)
 	w.WriteHeader(http.StatusOK)
 	_, _ = w.Write([]byte("success"))
 }
@@ -1189,6 +1382,13 @@ func PerkHandicapAdvantage(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	err = checkLobbySpecialIsEnabled(lobbyId, "PERK")
+	if err != nil {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(err.Error()))
+		return
+	}
+
 	err = database.PerkHandicapAdvantage(player.Id)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
@@ -1199,6 +1399,7 @@ func PerkHandicapAdvantage(w http.ResponseWriter, r *http.Request) {
 	websocket.PlayerBroadcast(player.Id, "refresh-player-specials")
 	websocket.PlayerBroadcast(player.Id, "Perk: Your handicap is now decreased by <green>1</> (cannot go negative).")
 
+	go announceAchievements(lobbyId, player.UserId)
 	w.WriteHeader(http.StatusOK)
 	_, _ = w.Write([]byte("success"))
 }
@@ -1219,6 +1420,13 @@ func PerkSpyAdvantage(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	err = checkLobbySpecialIsEnabled(lobbyId, "PERK")
+	if err != nil {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(err.Error()))
+		return
+	}
+
 	err = database.PerkSpyAdvantage(player.Id)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
@@ -1230,6 +1438,7 @@ func PerkSpyAdvantage(w http.ResponseWriter, r *http.Request) {
 	websocket.PlayerBroadcast(player.Id, "refresh-lobby-game-stats")
 	websocket.PlayerBroadcast(player.Id, "Perk: You can now spy on other player's credits.")
 
+	go announceAchievements(lobbyId, player.UserId)
 	w.WriteHeader(http.StatusOK)
 	_, _ = w.Write([]byte("success"))
 }
@@ -1278,6 +1487,63 @@ func WithdrawCard(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 }
 
+func MoveResponseCard(w http.ResponseWriter, r *http.Request) {
+	lobbyIdString := r.PathValue("lobbyId")
+	lobbyId, err := uuid.Parse(lobbyIdString)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to get lobby id from path."))
+		return
+	}
+
+	responseCardIdString := r.PathValue("responseCardId")
+	responseCardId, err := uuid.Parse(responseCardIdString)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to get response card id from path."))
+		return
+	}
+
+	slotString := r.PathValue("slot")
+	slot, err := strconv.Atoi(slotString)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to get slot from path."))
+		return
+	}
+
+	player, err := getLobbyRequestPlayer(r, lobbyId)
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(err.Error()))
+		return
+	}
+
+	// cards are locked into their blanks once everyone has played
+	boardData, err := database.GetLobbyGameBoardData(player.Id)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(err.Error()))
+		return
+	}
+
+	if boardData.BoardIsReady {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("All players have played."))
+		return
+	}
+
+	err = database.MoveResponseCard(player.Id, responseCardId, slot)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(err.Error()))
+		return
+	}
+
+	websocket.PlayerBroadcast(player.Id, "refresh-lobby-game-board")
+	w.WriteHeader(http.StatusOK)
+}
+
 func DiscardCard(w http.ResponseWriter, r *http.Request) {
 	lobbyIdString := r.PathValue("lobbyId")
 	lobbyId, err := uuid.Parse(lobbyIdString)
@@ -1310,9 +1576,92 @@ func DiscardCard(w http.ResponseWriter, r *http.Request) {
 	}
 
 	websocket.PlayerBroadcast(player.Id, "refresh-player-hand")
+	go announceAchievements(lobbyId, player.UserId)
 	w.WriteHeader(http.StatusOK)
 }
 
+func ReportCard(w http.ResponseWriter, r *http.Request) {
+	lobbyIdString := r.PathValue("lobbyId")
+	lobbyId, err := uuid.Parse(lobbyIdString)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to get lobby id from path."))
+		return
+	}
+
+	player, err := getLobbyRequestPlayer(r, lobbyId)
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(err.Error()))
+		return
+	}
+
+	err = r.ParseForm()
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to parse form."))
+		return
+	}
+
+	var cardId uuid.UUID
+	var reason string
+	for key, val := range r.Form {
+		switch key {
+		case "cardId":
+			cardId, err = uuid.Parse(val[0])
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte("Failed to parse card id."))
+				return
+			}
+		case "reason":
+			reason = val[0]
+		}
+	}
+
+	if cardId == uuid.Nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("No card selected."))
+		return
+	}
+
+	switch reason {
+	case "OFFENSIVE", "BROKEN-BLANK", "DUPLICATE", "TYPO":
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Invalid report reason."))
+		return
+	}
+
+	canSeeCard, err := database.PlayerCanSeeCard(player.Id, cardId)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(err.Error()))
+		return
+	}
+
+	if !canSeeCard {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Card is not in this lobby."))
+		return
+	}
+
+	isInReview, err := database.ReportCard(cardId, player.Id, reason)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(err.Error()))
+		return
+	}
+
+	if isInReview {
+		websocket.LobbyBroadcast(lobbyId, "<red>Card Removed</>: A reported card was sent to review.")
+		websocket.LobbyBroadcast(lobbyId, "refresh")
+	}
+
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte("Card reported."))
+}
+
 func VoteToKick(w http.ResponseWriter, r *http.Request) {
 	lobbyIdString := r.PathValue("lobbyId")
 	lobbyId, err := uuid.Parse(lobbyIdString)
@@ -1352,14 +1701,15 @@ func VoteToKick(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if isKicked {
-		websocket.LobbyBroadcast(lobbyId, "<red>Player Kicked</>: <green>"+subjectPlayer.Name+"</>")
+		websocket.LobbyBroadcast(lobbyId, "<red>Player Kicked</>: <green>"+websocket.EscapeText(subjectPlayer.Name)+"</>")
 		websocket.LobbyBroadcast(lobbyId, "player-kicked")
 		go func() {
 			time.Sleep(2 * time.Second)
 			websocket.PlayerBroadcast(subjectPlayerId, "exit")
 		}()
+		go announceAchievements(lobbyId, subjectPlayer.UserId)
 	} else {
-		websocket.LobbyBroadcast(lobbyId, "Someone voted to kick <green>"+subjectPlayer.Name+"</> out of the lobby")
+		websocket.LobbyBroadcast(lobbyId, "Someone voted to kick <green>"+websocket.EscapeText(subjectPlayer.Name)+"</> out of the lobby")
 		websocket.PlayerBroadcast(player.Id, "refresh-lobby-game-stats")
 	}
 
@@ -1404,7 +1754,7 @@ func VoteToKickUndo(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	websocket.LobbyBroadcast(lobbyId, "Someone removed their vote to kick <green>"+subjectPlayer.Name+"</> out of the lobby")
+	websocket.LobbyBroadcast(lobbyId, "Someone removed their vote to kick <green>"+websocket.EscapeText(subjectPlayer.Name)+"</> out of the lobby")
 	websocket.PlayerBroadcast(player.Id, "refresh-lobby-game-stats")
 
 	w.WriteHeader(http.StatusOK)
@@ -1512,7 +1862,14 @@ func PickWinner(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	websocket.LobbyBroadcast(lobbyId, "<blue>Winning Card</>: "+cardTextStart)
+	userIds, err := database.GetLobbyRoundUserIds(lobbyId)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("Failed to get round users."))
+		return
+	}
+
+	websocket.LobbyBroadcast(lobbyId, "<blue>Winning Card</>: "+websocket.EscapeText(cardTextStart))
 
 	winnerName, err := database.PickWinner(responseId)
 	if err != nil {
@@ -1521,9 +1878,11 @@ func PickWinner(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	websocket.LobbyBroadcast(lobbyId, "<blue>Winner</>: <green>"+winnerName+"</>")
+	websocket.LobbyBroadcast(lobbyId, "<blue>Winner</>: <green>"+websocket.EscapeText(winnerName)+"</>")
 
 	websocket.LobbyBroadcast(lobbyId, "refresh")
+	go announceAchievements(lobbyId, userIds...)
+	go updateRatings()
 	w.WriteHeader(http.StatusOK)
 }
 
@@ -1543,7 +1902,14 @@ func PickRandomWinner(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	websocket.LobbyBroadcast(lobbyId, "<green>"+player.Name+"</>: Random Winner!")
+	userIds, err := database.GetLobbyRoundUserIds(lobbyId)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("Failed to get round users."))
+		return
+	}
+
+	websocket.LobbyBroadcast(lobbyId, "<green>"+websocket.EscapeText(player.Name)+"</>: Random Winner!")
 
 	winnerName, err := database.PickRandomWinner(lobbyId)
 	if err != nil {
@@ -1558,9 +1924,11 @@ func PickRandomWinner(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	websocket.LobbyBroadcast(lobbyId, "<blue>Winner</>: <green>"+winnerName+"</>")
+	websocket.LobbyBroadcast(lobbyId, "<blue>Winner</>: <green>"+websocket.EscapeText(winnerName)+"</>")
 
 	websocket.LobbyBroadcast(lobbyId, "refresh")
+	go announceAchievements(lobbyId, userIds...)
+	go updateRatings()
 	w.WriteHeader(http.StatusOK)
 }
 
@@ -1580,6 +1948,13 @@ func FlipTable(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	err = checkLobbySpecialIsEnabled(lobbyId, "FLIP-TABLE")
+	if err != nil {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(err.Error()))
+		return
+	}
+
 	err = database.FlipTable(player.Id)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
@@ -1587,13 +1962,14 @@ func FlipTable(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	websocket.LobbyBroadcast(lobbyId, "<green>"+player.Name+"</>: FLIP THE TABLE!")
+	websocket.LobbyBroadcast(lobbyId, "<green>"+websocket.EscapeText(player.Name)+"</>: FLIP THE TABLE!")
 	websocket.LobbyBroadcast(lobbyId, "table-flipped")
 	go func() {
 		time.Sleep(2 * time.Second)
 		websocket.PlayerBroadcast(player.Id, "exit")
 	}()
 
+	go announceAchievements(lobbyId, player.UserId)
 	w.WriteHeader(http.StatusOK)
 	_, _ = w.Write([]byte("Table Flipped!"))
 }
@@ -1614,6 +1990,13 @@ func SkipPrompt(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	userIds, err := database.GetLobbyRoundUserIds(lobbyId)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("Failed to get round users."))
+		return
+	}
+
 	err = database.SkipPrompt(lobbyId)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
@@ -1623,6 +2006,7 @@ func SkipPrompt(w http.ResponseWriter, r *http.Request) {
 
 	websocket.LobbyBroadcast(lobbyId, "refresh")
 
+	go announceAchievements(lobbyId, userIds...)
 	w.WriteHeader(http.StatusOK)
 }
 
@@ -1682,7 +2066,7 @@ func SetName(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	websocket.LobbyBroadcast(lobbyId, "<green>"+player.Name+"</>: Lobby name set to "+name)
+	websocket.LobbyBroadcast(lobbyId, "<green>"+websocket.EscapeText(player.Name)+"</>: Lobby name set to "+websocket.EscapeText(name))
 	websocket.LobbyBroadcast(lobbyId, "refresh-lobby-game-info")
 
 	w.WriteHeader(http.StatusOK)
@@ -1726,7 +2110,7 @@ func SetMessage(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	websocket.LobbyBroadcast(lobbyId, "<green>"+player.Name+"</>: Lobby message set to "+message)
+	websocket.LobbyBroadcast(lobbyId, "<green>"+websocket.EscapeText(player.Name)+"</>: Lobby message set to "+websocket.EscapeText(message))
 
 	w.WriteHeader(http.StatusOK)
 	_, _ = w.Write([]byte("success"))
@@ -1769,7 +2153,7 @@ func SetDrawPriority(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	websocket.LobbyBroadcast(lobbyId, fmt.Sprintf("<green>%s</>: Lobby draw priority set to %s", player.Name, drawPriority))
+	websocket.LobbyBroadcast(lobbyId, fmt.Sprintf("<green>%s</>: Lobby draw priority set to %s", websocket.EscapeText(player.Name), drawPriority))
 
 	w.WriteHeader(http.StatusOK)
 	_, _ = w.Write([]byte("success"))
@@ -1825,7 +2209,7 @@ func SetHandSize(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	websocket.LobbyBroadcast(lobbyId, fmt.Sprintf("<green>%s</>: Lobby hand size set to %d", player.Name, handSize))
+	websocket.LobbyBroadcast(lobbyId, fmt.Sprintf("<green>%s</>: Lobby hand size set to %d", websocket.EscapeText(player.Name), handSize))
 	websocket.LobbyBroadcast(lobbyId, "refresh-player-hand")
 
 	w.WriteHeader(http.StatusOK)
@@ -1883,11 +2267,11 @@ func SetRoundTimer(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if roundTimer > 60 {
-		websocket.LobbyBroadcast(lobbyId, fmt.Sprintf("<green>%s</>: Lobby round timer set to %d mintues", player.Name, roundTimer/60))
+		websocket.LobbyBroadcast(lobbyId, fmt.Sprintf("<green>%s</>: Lobby round timer set to %d mintues", websocket.EscapeText(player.Name), roundTimer/60))
 	} else if roundTimer > 0 {
-		websocket.LobbyBroadcast(lobbyId, fmt.Sprintf("<green>%s</>: Lobby round timer set to %d seconds", player.Name, roundTimer))
+		websocket.LobbyBroadcast(lobbyId, fmt.Sprintf("<green>%s</>: Lobby round timer set to %d seconds", websocket.EscapeText(player.Name), roundTimer))
 	} else {
-		websocket.LobbyBroadcast(lobbyId, fmt.Sprintf("<green>%s</>: Lobby round timer set to unlimited", player.Name))
+		websocket.LobbyBroadcast(lobbyId, fmt.Sprintf("<green>%s</>: Lobby round timer set to unlimited", websocket.EscapeText(player.Name)))
 	}
 	websocket.LobbyBroadcast(lobbyId, "refresh")
 
@@ -1967,7 +2351,7 @@ func SetFreeCredits(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	websocket.LobbyBroadcast(lobbyId, fmt.Sprintf("<green>%s</>: Lobby free credits set to %d", player.Name, freeCredits))
+	websocket.LobbyBroadcast(lobbyId, fmt.Sprintf("<green>%s</>: Lobby free credits set to %d", websocket.EscapeText(player.Name), freeCredits))
 	websocket.LobbyBroadcast(lobbyId, "refresh-player-specials")
 
 	w.WriteHeader(http.StatusOK)
@@ -2016,7 +2400,7 @@ func SetFreeSpecialCards(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	websocket.LobbyBroadcast(lobbyId, fmt.Sprintf("<green>%s</>: Lobby free special cards set to %t", player.Name, freeSpecialCards))
+	websocket.LobbyBroadcast(lobbyId, fmt.Sprintf("<green>%s</>: Lobby free special cards set to %t", websocket.EscapeText(player.Name), freeSpecialCards))
 	websocket.LobbyBroadcast(lobbyId, "refresh-player-specials")
 
 	w.WriteHeader(http.StatusOK)
@@ -2073,7 +2457,7 @@ func SetWinStreakThreshold(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	websocket.LobbyBroadcast(lobbyId, fmt.Sprintf("<green>%s</>: Lobby win streak threshold set to %d", player.Name, winStreakThreshold))
+	websocket.LobbyBroadcast(lobbyId, fmt.Sprintf("<green>%s</>: Lobby win streak threshold set to %d", websocket.EscapeText(player.Name), winStreakThreshold))
 	websocket.LobbyBroadcast(lobbyId, "refresh-player-specials")
 
 	w.WriteHeader(http.StatusOK)
@@ -2130,13 +2514,307 @@ func SetLoseStreakThreshold(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	websocket.LobbyBroadcast(lobbyId, fmt.Sprintf("<green>%s</>: Lobby lose streak threshold set to %d", player.Name, loseStreakThreshold))
+	websocket.LobbyBroadcast(lobbyId, fmt.Sprintf("<green>%s</>: Lobby lose streak threshold set to %d", websocket.EscapeText(player.Name), loseStreakThreshold))
+	websocket.LobbyBroadcast(lobbyId, "refresh-player-specials")
+
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte("success"))
+}
+
+func SetRules(w http.ResponseWriter, r *http.Request) {
+	lobbyIdString := r.PathValue("lobbyId")
+	lobbyId, err := uuid.Parse(lobbyIdString)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to get lobby id from path."))
+		return
+	}
+
+	player, err := getLobbyRequestPlayer(r, lobbyId)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(err.Error()))
+		return
+	}
+
+	ownerPlayerId, err := database.GetLobbyOwnerPlayerId(lobbyId)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(err.Error()))
+		return
+	}
+
+	if player.Id != ownerPlayerId {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte("Only the lobby owner can change the rules."))
+		return
+	}
+
+	err = r.ParseForm()
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to parse form."))
+		return
+	}
+
+	rules, err := database.GetLobbyRules(lobbyId)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(err.Error()))
+		return
+	}
+
+	// unchecked boxes are not submitted, so everything starts disabled, but
+	// costs left out of the form keep their current value
+	rules.EnabledAlert = false
+	rules.EnabledGamble = false
+	rules.EnabledBet = false
+	rules.EnabledSkipBeingJudge = false
+	rules.EnabledExtraResponse = false
+	rules.EnabledBlockResponse = false
+	rules.EnabledSurpriseCard = false
+	rules.EnabledStealCard = false
+	rules.EnabledFindCard = false
+	rules.EnabledWildCard = false
+	rules.EnabledPerk = false
+	rules.EnabledFlipTable = false
+	for key, val := range r.Form {
+		var cost *int
+		switch key {
+		case "enabledAlert":
+			rules.EnabledAlert = true
+		case "enabledGamble":
+			rules.EnabledGamble = true
+		case "enabledBet":
+			rules.EnabledBet = true
+		case "enabledSkipBeingJudge":
+			rules.EnabledSkipBeingJudge = true
+		case "enabledExtraResponse":
+			rules.EnabledExtraResponse = true
+		case "enabledBlockResponse":
+			rules.EnabledBlockResponse = true
+		case "enabledSurpriseCard":
+			rules.EnabledSurpriseCard = true
+		case "enabledStealCard":
+			rules.EnabledStealCard = true
+		case "enabledFindCard":
+			rules.EnabledFindCard = true
+		case "enabledWildCard":
+			rules.EnabledWildCard = true
+		case "enabledPerk":
+			rules.EnabledPerk = true
+		case "enabledFlipTable":
+			rules.EnabledFlipTable = true
+		case "costSkipBeingJudge":
+			cost = &rules.CostSkipBeingJudge
+		case "costExtraResponse":
+			cost = &rules.CostExtraResponse
+		case "costBlockResponse":
+			cost = &rules.CostBlockResponse
+		case "costSurpriseCard":
+			cost = &rules.CostSurpriseCard
+		case "costStealCard":
+			cost = &rules.CostStealCard
+		case "costFindCard":
+			cost = &rules.CostFindCard
+		case "costWildCard":
+			cost = &rules.CostWildCard
+		case "costPerk":
+			cost = &rules.CostPerk
+		}
+
+		if cost == nil {
+			continue
+		}
+
+		*cost, err = strconv.Atoi(val[0])
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte("Failed to parse cost."))
+			return
+		}
+
+		if *cost < 0 {
+			*cost = 0
+		}
+
+		if *cost > 50 {
+			*cost = 50
+		}
+	}
+
+	err = database.SetLobbyRules(lobbyId, rules)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(err.Error()))
+		return
+	}
+
+	websocket.LobbyBroadcast(lobbyId, "<green>"+websocket.EscapeText(player.Name)+"</>: Updated lobby rules.")
+	websocket.LobbyBroadcast(lobbyId, "refresh-player-specials")
+
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte("success"))
+}
+
+func SetFilterWords(w http.ResponseWriter, r *http.Request) {
+	lobbyIdString := r.PathValue("lobbyId")
+	lobbyId, err := uuid.Parse(lobbyIdString)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to get lobby id from path."))
+		return
+	}
+
+	player, err := getLobbyRequestPlayer(r, lobbyId)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(err.Error()))
+		return
+	}
+
+	err = checkPlayerCanModerateLobby(lobbyId, player)
+	if err != nil {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(err.Error()))
+		return
+	}
+
+	err = r.ParseForm()
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to parse form."))
+		return
+	}
+
+	words := make([]string, 0)
+	for key, val := range r.Form {
+		if key != "filterWords" {
+			continue
+		}
+
+		for _, word := range strings.FieldsFunc(val[0], func(c rune) bool {
+			return c == '\n' || c == ','
+		}) {
+			word = strings.TrimSpace(word)
+			if word == "" {
+				continue
+			}
+
+			if len(word) > 255 {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte("Filter words must be less than 255 characters."))
+				return
+			}
+
+			words = append(words, word)
+		}
+	}
+
+	err = database.SetLobbyFilterWords(lobbyId, words)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(err.Error()))
+		return
+	}
+
+	websocket.LobbyBroadcast(lobbyId, "<green>"+websocket.EscapeText(player.Name)+"</>: Updated chat filter words.")
 	websocket.LobbyBroadcast(lobbyId, "refresh-player-specials")
 
 	w.WriteHeader(http.StatusOK)
 	_, _ = w.Write([]byte("success"))
 }
 
+func MutePlayer(w http.ResponseWriter, r *http.Request) {
+	setPlayerMuted(w, r, true)
+}
+
+func UnmutePlayer(w http.ResponseWriter, r *http.Request) {
+	setPlayerMuted(w, r, false)
+}
+
+func setPlayerMuted(w http.ResponseWriter, r *http.Request, muted bool) {
+	lobbyIdString := r.PathValue("lobbyId")
+	lobbyId, err := uuid.Parse(lobbyIdString)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to get lobby id from path."))
+		return
+	}
+
+	subjectPlayerIdString := r.PathValue("playerId")
+	subjectPlayerId, err := uuid.Parse(subjectPlayerIdString)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to get player id from path."))
+		return
+	}
+
+	subjectPlayer, err := database.GetPlayer(subjectPlayerId)
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(err.Error()))
+		return
+	}
+
+	if subjectPlayer.LobbyId != lobbyId {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Player is not in this lobby."))
+		return
+	}
+
+	player, err := getLobbyRequestPlayer(r, lobbyId)
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(err.Error()))
+		return
+	}
+
+	err = checkPlayerCanModerateLobby(lobbyId, player)
+	if err != nil {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(err.Error()))
+		return
+	}
+
+	if muted {
+		err = database.MuteUserInLobby(lobbyId, subjectPlayer.UserId)
+	} else {
+		err = database.UnmuteUserInLobby(lobbyId, subjectPlayer.UserId)
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(err.Error()))
+		return
+	}
+
+	if muted {
+		websocket.LobbyBroadcast(lobbyId, "<red>Player Muted</>: <green>"+websocket.EscapeText(subjectPlayer.Name)+"</>")
+	} else {
+		websocket.LobbyBroadcast(lobbyId, "<blue>Player Unmuted</>: <green>"+websocket.EscapeText(subjectPlayer.Name)+"</>")
+	}
+	websocket.LobbyBroadcast(lobbyId, "refresh-lobby-game-stats")
+
+	data, err := database.GetLobbyGameStatsData(player.Id)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(err.Error()))
+		return
+	}
+
+	tmpl, err := template.ParseFS(
+		static.StaticFiles,
+		"html/components/game/lobby-game-stats.html",
+	)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("Failed to parse HTML."))
+		return
+	}
+
+	_ = tmpl.ExecuteTemplate(w, "lobby-game-stats", data)
+}
+
 func SetResponseCount(w http.ResponseWriter, r *http.Request) {
 	lobbyIdString := r.PathValue("lobbyId")
 	lobbyId, err := uuid.Parse(lobbyIdString)
@@ -2258,7 +2936,7 @@ func SetDecks(w http.ResponseWriter, r *http.Request) {
 	}
 
 	websocket.LobbyBroadcast(lobbyId, "refresh-lobby-game-info")
-	websocket.LobbyBroadcast(lobbyId, "<green>"+player.Name+"</>: Updated draw pile decks.")
+	websocket.LobbyBroadcast(lobbyId, "<green>"+websocket.EscapeText(player.Name)+"</>: Updated draw pile decks.")
 	w.WriteHeader(http.StatusOK)
 }
 
@@ -2281,3 +2959,64 @@ func getLobbyRequestPlayer(r *http.Request, lobbyId uuid.UUID) (database.Player,
 
 	return player, nil
 }
+
+func checkLobbySpecialIsEnabled(lobbyId uuid.UUID, category string) error {
+	isEnabled, err := database.GetLobbySpecialIsEnabled(lobbyId, category)
+	if err != nil {
+		return err
+	}
+
+	if !isEnabled {
+		return errors.New("this has been disabled by the lobby owner")
+	}
+
+	return nil
+}
+
+func checkPlayerCanModerateLobby(lobbyId uuid.UUID, player database.Player) error {
+	ownerPlayerId, err := database.GetLobbyOwnerPlayerId(lobbyId)
+	if err != nil {
+		return err
+	}
+
+	if player.Id == ownerPlayerId {
+		return nil
+	}
+
+	isAdmin, err := database.GetUserIsAdmin(player.UserId)
+	if err != nil {
+		return err
+	}
+
+	if !isAdmin {
+		return errors.New("only the lobby owner can moderate the chat")
+	}
+
+	return nil
+}
+
+// updateRatings rates the round that just ended. The winner has already been
+// picked, so errors are only logged.
+func updateRatings() {
+	err := database.UpdateUserRatings()
+	if err != nil {
+		log.Println(err)
+	}
+}
+
+// announceAchievements unlocks any achievement tiers the users have reached
+// and announces them in the lobby chat. The lobby action has already
+// succeeded, so errors are only logged.
+func announceAchievements(lobbyId uuid.UUID, userIds ...uuid.UUID) {
+	for _, userId := range userIds {
+		badges, err := database.UnlockUserAchievements(userId, lobbyId)
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+
+		for _, badge := range badges {
+			websocket.LobbyBroadcast(lobbyId, "<green>"+websocket.EscapeText(badge.UserName)+"</>: Unlocked the <blue>"+websocket.EscapeText(badge.FullName())+"</> achievement!")
+		}
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"html/template"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/grantfbarnes/card-judge/api"
@@ -70,6 +72,20 @@ func Account(w http.ResponseWriter, r *http.Request) {
 	basePageData := api.GetBasePageData(r)
 	basePageData.PageTitle = "Card Judge - Account"
 
+	cardSuggestions, err := database.GetUserCardSuggestions(basePageData.User.Id)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("failed to get card suggestions"))
+		return
+	}
+
+	err = database.SetCardSuggestionsSeen(basePageData.User.Id)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("failed to set card suggestions seen"))
+		return
+	}
+
 	tmpl, err := template.ParseFS(
 		static.StaticFiles,
 		"html/pages/base.html",
@@ -81,7 +97,15 @@ func Account(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	_ = tmpl.ExecuteTemplate(w, "base", basePageData)
+	type data struct {
+		api.BasePageData
+		CardSuggestions []database.CardSuggestion
+	}
+
+	_ = tmpl.ExecuteTemplate(w, "base", data{
+		BasePageData:    basePageData,
+		CardSuggestions: cardSuggestions,
+	})
 }
 
 func Users(w http.ResponseWriter, r *http.Request) {
@@ -155,16 +179,22 @@ func Review(w http.ResponseWriter, r *http.Request) {
 	basePageData := api.GetBasePageData(r)
 	basePageData.PageTitle = "Card Judge - Review"
 
+	var text string
 	var page int
+	var duplicates bool
 	params := r.URL.Query()
 	for key, val := range params {
 		switch key {
+		case "text":
+			text = val[0]
 		case "page":
 			page, _ = strconv.Atoi(val[0])
+		case "duplicates":
+			duplicates = val[0] == "true"
 		}
 	}
 
-	totalRowCount, err := database.CountCardsInReview()
+	totalRowCount, err := database.CountCardsInReview(text)
 	if err != nil {
 		totalRowCount = 0
 	}
@@ -178,13 +208,31 @@ func Review(w http.ResponseWriter, r *http.Request) {
 		page = totalPageCount
 	}
 
-	cards, err := database.SearchCardsInReview(page)
+	cards, err := database.SearchCardsInReview(text, page)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		_, _ = w.Write([]byte("failed to get table rows"))
 		return
 	}
 
+	cardReports, err := database.GetCardReports()
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("failed to get card reports"))
+		return
+	}
+
+	// comparing every card is slow, so the report only runs when asked for
+	nearDuplicates := make([]database.NearDuplicateCards, 0)
+	if duplicates {
+		nearDuplicates, err = database.GetNearDuplicateCards(100)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte("failed to get near duplicate cards"))
+			return
+		}
+	}
+
 	tmpl, err := template.ParseFS(
 		static.StaticFiles,
 		"html/pages/base.html",
@@ -198,18 +246,27 @@ func Review(w http.ResponseWriter, r *http.Request) {
 
 	type data struct {
 		api.BasePageData
-		Page     int
-		LastPage int
-		RowCount int
-		Cards    []database.DisplayCard
+		Text        string
+		Page        int
+		LastPage    int
+		RowCount    int
+		Cards       []database.DisplayCard
+		CardReports []database.CardReport
+
+		Duplicates     bool
+		NearDuplicates []database.NearDuplicateCards
 	}
 
 	_ = tmpl.ExecuteTemplate(w, "base", data{
-		BasePageData: basePageData,
-		Page:         page,
-		LastPage:     totalPageCount,
-		RowCount:     totalRowCount,
-		Cards:        cards,
+		BasePageData:   basePageData,
+		Text:           text,
+		Page:           page,
+		LastPage:       totalPageCount,
+		RowCount:       totalRowCount,
+		Cards:          cards,
+		CardReports:    cardReports,
+		Duplicates:     duplicates,
+		NearDuplicates: nearDuplicates,
 	})
 }
 
@@ -235,6 +292,13 @@ func StatsLeaderboard(w http.ResponseWriter, r *http.Request) {
 	basePageData := api.GetBasePageData(r)
 	basePageData.PageTitle = "Card Judge - Stats - Leaderboard"
 
+	currentSeason, err := database.GetCurrentSeason()
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("Failed to get current season."))
+		return
+	}
+
 	tmpl, err := template.ParseFS(
 		static.StaticFiles,
 		"html/pages/base.html",
@@ -246,7 +310,237 @@ func StatsLeaderboard(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	_ = tmpl.ExecuteTemplate(w, "base", basePageData)
+	type data struct {
+		api.BasePageData
+		CurrentSeason database.Season
+	}
+
+	_ = tmpl.ExecuteTemplate(w, "base", data{
+		BasePageData:  basePageData,
+		CurrentSeason: currentSeason,
+	})
+}
+
+func StatsSeasons(w http.ResponseWriter, r *http.Request) {
+	basePageData := api.GetBasePageData(r)
+	basePageData.PageTitle = "Card Judge - Stats - Seasons"
+
+	seasons, err := database.GetSeasons()
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("Failed to get seasons."))
+		return
+	}
+
+	tmpl, err := template.ParseFS(
+		static.StaticFiles,
+		"html/pages/base.html",
+		"html/pages/body/stats-seasons.html",
+	)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("failed to parse HTML"))
+		return
+	}
+
+	type data struct {
+		api.BasePageData
+		Seasons []database.Season
+	}
+
+	_ = tmpl.ExecuteTemplate(w, "base", data{
+		BasePageData: basePageData,
+		Seasons:      seasons,
+	})
+}
+
+func StatsSeason(w http.ResponseWriter, r *http.Request) {
+	basePageData := api.GetBasePageData(r)
+	basePageData.PageTitle = "Card Judge - Stats - Season"
+
+	seasonIdString := r.PathValue("seasonId")
+	seasonId, err := uuid.Parse(seasonIdString)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to parse id."))
+		return
+	}
+
+	season, err := database.GetSeason(seasonId)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("Failed to get season."))
+		return
+	}
+
+	if season.Id == uuid.Nil {
+		http.Redirect(w, r, "/stats/seasons", http.StatusSeeOther)
+		return
+	}
+
+	standings, err := database.GetSeasonStandings(season)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("Failed to get season standings."))
+		return
+	}
+
+	tmpl, err := template.ParseFS(
+		static.StaticFiles,
+		"html/pages/base.html",
+		"html/pages/body/stats-season.html",
+	)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("failed to parse HTML"))
+		return
+	}
+
+	type data struct {
+		api.BasePageData
+		Season    database.Season
+		Standings []database.SeasonStanding
+	}
+
+	_ = tmpl.ExecuteTemplate(w, "base", data{
+		BasePageData: basePageData,
+		Season:       season,
+		Standings:    standings,
+	})
+}
+
+func StatsHistory(w http.ResponseWriter, r *http.Request) {
+	basePageData := api.GetBasePageData(r)
+	basePageData.PageTitle = "Card Judge - Stats - Round History"
+
+	var filter database.HistoryFilter
+	var fromDate string
+	var toDate string
+	var page int
+	params := r.URL.Query()
+	for key, val := range params {
+		switch key {
+		case "lobbyName":
+			filter.LobbyName = strings.TrimSpace(val[0])
+		case "userName":
+			filter.UserName = strings.TrimSpace(val[0])
+		case "fromDate":
+			fromDate = val[0]
+		case "toDate":
+			toDate = val[0]
+		case "page":
+			page, _ = strconv.Atoi(val[0])
+		}
+	}
+
+	filter.ViewerUserId = basePageData.User.Id
+
+	if date, err := time.Parse("2006-01-02", fromDate); err == nil {
+		filter.FromDate = date
+	}
+
+	// the to date is inclusive on the page, so everything before the next day counts
+	if date, err := time.Parse("2006-01-02", toDate); err == nil {
+		filter.ToDate = date.AddDate(0, 0, 1)
+	}
+
+	totalRowCount, err := database.CountHistoryRounds(filter)
+	if err != nil {
+		totalRowCount = 0
+	}
+	totalPageCount := max((totalRowCount+9)/10, 1)
+
+	if page < 1 {
+		page = 1
+	}
+
+	if page > totalPageCount {
+		page = totalPageCount
+	}
+
+	rounds, err := database.GetHistoryRounds(filter, page)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("Failed to get rounds."))
+		return
+	}
+
+	tmpl, err := template.ParseFS(
+		static.StaticFiles,
+		"html/pages/base.html",
+		"html/pages/body/stats-history.html",
+		"html/pages/body/stats-history-round.html",
+	)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("failed to parse HTML"))
+		return
+	}
+
+	type data struct {
+		api.BasePageData
+		LobbyName string
+		UserName  string
+		FromDate  string
+		ToDate    string
+		Page      int
+		LastPage  int
+		RowCount  int
+		Rounds    []database.HistoryRound
+	}
+
+	_ = tmpl.ExecuteTemplate(w, "base", data{
+		BasePageData: basePageData,
+		LobbyName:    filter.LobbyName,
+		UserName:     filter.UserName,
+		FromDate:     fromDate,
+		ToDate:       toDate,
+		Page:         page,
+		LastPage:     totalPageCount,
+		RowCount:     totalRowCount,
+		Rounds:       rounds,
+	})
+}
+
+func StatsRound(w http.ResponseWriter, r *http.Request) {
+	basePageData := api.GetBasePageData(r)
+	basePageData.PageTitle = "Card Judge - Stats - Round"
+
+	roundIdString := r.PathValue("roundId")
+	roundId, err := uuid.Parse(roundIdString)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to parse id."))
+		return
+	}
+
+	round, err := database.GetHistoryRound(roundId, basePageData.User.Id)
+	if err != nil {
+		http.Redirect(w, r, "/stats/history", http.StatusSeeOther)
+		return
+	}
+
+	tmpl, err := template.ParseFS(
+		static.StaticFiles,
+		"html/pages/base.html",
+		"html/pages/body/stats-round.html",
+		"html/pages/body/stats-history-round.html",
+	)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("failed to parse HTML"))
+		return
+	}
+
+	type data struct {
+		api.BasePageData
+		Round database.HistoryRound
+	}
+
+	_ = tmpl.ExecuteTemplate(w, "base", data{
+		BasePageData: basePageData,
+		Round:        round,
+	})
 }
 
 func StatsUsers(w http.ResponseWriter, r *http.Request) {
@@ -342,6 +636,27 @@ func StatsUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	userBadges, err := database.GetAchievementBadgesUser(userId)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("Failed to get user badges."))
+		return
+	}
+
+	userRating, err := database.GetUserRating(userId)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("Failed to get user rating."))
+		return
+	}
+
+	opponents, err := database.GetStatsHeadToHeadOpponents(userId, 10)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("Failed to get user opponents."))
+		return
+	}
+
 	totalAchievementProgress := 0
 	for _, a := range userAchievements {
 		totalAchievementProgress += a.Progress
@@ -361,15 +676,82 @@ func StatsUser(w http.ResponseWriter, r *http.Request) {
 	type data struct {
 		api.BasePageData
 		database.StatUser
+		UserId              uuid.UUID
 		AchievementProgress float32
 		Achievements        []database.Achievement
+		Badges              []database.AchievementBadge
+		UserRating          database.UserRating
+		Opponents           []database.StatHeadToHeadOpponent
+
+		// the full data export is only for the user and admins
+		CanExportData bool
 	}
 
 	_ = tmpl.ExecuteTemplate(w, "base", data{
 		BasePageData:        basePageData,
 		StatUser:            userStats,
+		UserId:              userId,
 		AchievementProgress: float32(totalAchievementProgress) / float32(len(userAchievements)),
 		Achievements:        userAchievements,
+		Badges:              userBadges,
+		UserRating:          userRating,
+		Opponents:           opponents,
+		CanExportData:       basePageData.User.Id == userId || basePageData.User.IsAdmin,
+	})
+}
+
+func StatsHeadToHead(w http.ResponseWriter, r *http.Request) {
+	basePageData := api.GetBasePageData(r)
+	basePageData.PageTitle = "Card Judge - Stats - Head to Head"
+
+	userIdString := r.PathValue("userId")
+	userId, err := uuid.Parse(userIdString)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to parse id."))
+		return
+	}
+
+	otherUserIdString := r.PathValue("otherUserId")
+	otherUserId, err := uuid.Parse(otherUserIdString)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to parse other id."))
+		return
+	}
+
+	if userId == otherUserId {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Cannot compare a user with themselves."))
+		return
+	}
+
+	headToHeadStats, err := database.GetStatsHeadToHead(userId, otherUserId)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("Failed to get head to head stats."))
+		return
+	}
+
+	tmpl, err := template.ParseFS(
+		static.StaticFiles,
+		"html/pages/base.html",
+		"html/pages/body/stats-head-to-head.html",
+	)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("failed to parse HTML"))
+		return
+	}
+
+	type data struct {
+		api.BasePageData
+		database.StatHeadToHead
+	}
+
+	_ = tmpl.ExecuteTemplate(w, "base", data{
+		BasePageData:   basePageData,
+		StatHeadToHead: headToHeadStats,
 	})
 }
 
@@ -469,6 +851,13 @@ func StatsCard(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	combos, err := database.GetStatsCardCombos(basePageData.User.Id, cardId, 10)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("Failed to get card combos."))
+		return
+	}
+
 	tmpl, err := template.ParseFS(
 		static.StaticFiles,
 		"html/pages/base.html",
@@ -483,11 +872,233 @@ func StatsCard(w http.ResponseWriter, r *http.Request) {
 	type data struct {
 		api.BasePageData
 		database.StatCard
+		CardId uuid.UUID
+		Combos []database.StatCombo
 	}
 
 	_ = tmpl.ExecuteTemplate(w, "base", data{
 		BasePageData: basePageData,
 		StatCard:     cardStats,
+		CardId:       cardId,
+		Combos:       combos,
+	})
+}
+
+func StatsDeck(w http.ResponseWriter, r *http.Request) {
+	deckIdString := r.PathValue("deckId")
+	deckId, err := uuid.Parse(deckIdString)
+	if err != nil {
+		http.Redirect(w, r, "/decks", http.StatusSeeOther)
+		return
+	}
+
+	basePageData := api.GetBasePageData(r)
+	basePageData.PageTitle = "Card Judge - Stats - Deck"
+
+	hasDeckAccess, err := database.UserHasDeckAccess(basePageData.User.Id, deckId, "READ")
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("failed to check deck access"))
+		return
+	}
+
+	if !hasDeckAccess {
+		http.Redirect(w, r, fmt.Sprintf("/deck/%s/access", deckId), http.StatusSeeOther)
+		return
+	}
+
+	var page int
+	params := r.URL.Query()
+	for key, val := range params {
+		switch key {
+		case "page":
+			page, _ = strconv.Atoi(val[0])
+		}
+	}
+
+	deckStats, err := database.GetStatsDeck(deckId)
+	if err != nil {
+		http.Redirect(w, r, "/decks", http.StatusSeeOther)
+		return
+	}
+
+	totalRowCount, err := database.CountStatsDeckPlayedResponseCards(deckId)
+	if err != nil {
+		totalRowCount = 0
+	}
+	totalPageCount := max((totalRowCount+9)/10, 1)
+
+	if page < 1 {
+		page = 1
+	}
+
+	if page > totalPageCount {
+		page = totalPageCount
+	}
+
+	playedCards, err := database.GetStatsDeckPlayedResponseCards(deckId, page)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("Failed to get played cards."))
+		return
+	}
+
+	unplayedCards, err := database.GetStatsDeckUnplayedCards(deckId, 25)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("Failed to get unplayed cards."))
+		return
+	}
+
+	reviewCards, err := database.GetStatsDeckReviewCards(deckId)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("Failed to get review cards."))
+		return
+	}
+
+	lobbyMonths, err := database.GetStatsDeckLobbyMonths(deckId)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("Failed to get deck lobbies."))
+		return
+	}
+
+	tmpl, err := template.ParseFS(
+		static.StaticFiles,
+		"html/pages/base.html",
+		"html/pages/body/stats-deck.html",
+	)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("failed to parse HTML"))
+		return
+	}
+
+	type data struct {
+		api.BasePageData
+		database.StatDeck
+		Page          int
+		LastPage      int
+		PlayedCards   []database.StatDeckCard
+		UnplayedCards []database.StatDeckCard
+		ReviewCards   []database.StatDeckReviewCard
+		LobbyMonths   []database.StatDeckLobbyMonth
+	}
+
+	_ = tmpl.ExecuteTemplate(w, "base", data{
+		BasePageData:  basePageData,
+		StatDeck:      deckStats,
+		Page:          page,
+		LastPage:      totalPageCount,
+		PlayedCards:   playedCards,
+		UnplayedCards: unplayedCards,
+		ReviewCards:   reviewCards,
+		LobbyMonths:   lobbyMonths,
+	})
+}
+
+func StatsCombos(w http.ResponseWriter, r *http.Request) {
+	basePageData := api.GetBasePageData(r)
+	basePageData.PageTitle = "Card Judge - Stats - Combos"
+
+	combos, err := database.GetStatsComboHallOfFame(basePageData.User.Id, 25)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("Failed to get combos."))
+		return
+	}
+
+	tmpl, err := template.ParseFS(
+		static.StaticFiles,
+		"html/pages/base.html",
+		"html/pages/body/stats-combos.html",
+	)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("failed to parse HTML"))
+		return
+	}
+
+	type data struct {
+		api.BasePageData
+		Combos []database.StatCombo
+	}
+
+	_ = tmpl.ExecuteTemplate(w, "base", data{
+		BasePageData: basePageData,
+		Combos:       combos,
+	})
+}
+
+func StatsCombo(w http.ResponseWriter, r *http.Request) {
+	basePageData := api.GetBasePageData(r)
+	basePageData.PageTitle = "Card Judge - Stats - Combo"
+
+	promptCardIdString := r.PathValue("promptCardId")
+	promptCardId, err := uuid.Parse(promptCardIdString)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to parse prompt id."))
+		return
+	}
+
+	responseCardIdString := r.PathValue("responseCardId")
+	responseCardId, err := uuid.Parse(responseCardIdString)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to parse response id."))
+		return
+	}
+
+	// both cards have to be readable, the same as on the combo lists
+	for _, cardId := range []uuid.UUID{promptCardId, responseCardId} {
+		card, err := database.GetCard(cardId)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte("Failed to get card."))
+			return
+		}
+
+		hasDeckAccess, err := database.UserHasDeckAccess(basePageData.User.Id, card.DeckId, "READ")
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte("failed to check deck access"))
+			return
+		}
+
+		if !hasDeckAccess {
+			http.Redirect(w, r, "/stats/combos", http.StatusSeeOther)
+			return
+		}
+	}
+
+	comboStats, err := database.GetStatsCombo(basePageData.User.Id, promptCardId, responseCardId, 100)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("Failed to get combo stats."))
+		return
+	}
+
+	tmpl, err := template.ParseFS(
+		static.StaticFiles,
+		"html/pages/base.html",
+		"html/pages/body/stats-combo.html",
+	)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("failed to parse HTML"))
+		return
+	}
+
+	type data struct {
+		api.BasePageData
+		database.StatComboDetail
+	}
+
+	_ = tmpl.ExecuteTemplate(w, "base", data{
+		BasePageData:    basePageData,
+		StatComboDetail: comboStats,
 	})
 }
 
@@ -626,11 +1237,19 @@ func Lobby(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	cardFilters, err := database.GetLobbyCardFilters(lobbyId)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("failed to get lobby card filters"))
+		return
+	}
+
 	type data struct {
 		api.BasePageData
-		Lobby    database.Lobby
-		PlayerId uuid.UUID
-		Decks    []database.Deck
+		Lobby       database.Lobby
+		PlayerId    uuid.UUID
+		Decks       []database.Deck
+		CardFilters database.LobbyCardFilters
 	}
 
 	_ = tmpl.ExecuteTemplate(w, "base", data{
@@ -638,6 +1257,7 @@ func Lobby(w http.ResponseWriter, r *http.Request) {
 		Lobby:        lobby,
 		PlayerId:     playerId,
 		Decks:        decks,
+		CardFilters:  cardFilters,
 	})
 }
 
@@ -786,7 +1406,7 @@ func Deck(w http.ResponseWriter, r *http.Request) {
 	basePageData := api.GetBasePageData(r)
 	basePageData.PageTitle = "Card Judge - Deck"
 
-	hasDeckAccess, err := database.UserHasDeckAccess(basePageData.User.Id, deckId)
+	hasDeckAccess, err := database.UserHasDeckAccess(basePageData.User.Id, deckId, "READ")
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		_, _ = w.Write([]byte("failed to check deck access"))
@@ -800,6 +1420,7 @@ func Deck(w http.ResponseWriter, r *http.Request) {
 
 	var category string
 	var text string
+	var tag string
 	var page int
 	params := r.URL.Query()
 	for key, val := range params {
@@ -808,12 +1429,14 @@ func Deck(w http.ResponseWriter, r *http.Request) {
 			category = val[0]
 		case "text":
 			text = val[0]
+		case "tag":
+			tag = val[0]
 		case "page":
 			page, _ = strconv.Atoi(val[0])
 		}
 	}
 
-	totalRowCount, err := database.CountCardsInDeck(deckId, category, text)
+	totalRowCount, err := database.CountCardsInDeck(deckId, category, text, tag)
 	if err != nil {
 		totalRowCount = 0
 	}
@@ -827,13 +1450,126 @@ func Deck(w http.ResponseWriter, r *http.Request) {
 		page = totalPageCount
 	}
 
-	cards, err := database.SearchCardsInDeck(deckId, category, text, page)
+	cards, err := database.SearchCardsInDeck(deckId, category, text, tag, page)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		_, _ = w.Write([]byte("failed to get table rows"))
 		return
 	}
 
+	deckTags, err := database.GetDeckTags(deckId)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("failed to get deck tags"))
+		return
+	}
+
+	canEdit, err := database.UserHasDeckAccess(basePageData.User.Id, deckId, "EDIT")
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("failed to check deck access"))
+		return
+	}
+
+	canManage, err := database.UserHasDeckAccess(basePageData.User.Id, deckId, "MANAGE")
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("failed to check deck access"))
+		return
+	}
+
+	accessUsers := make([]database.DeckAccessUser, 0)
+	if canManage {
+		accessUsers, err = database.GetDeckAccessUsers(deckId)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte("failed to get deck access users"))
+			return
+		}
+	}
+
+	cardSuggestions := make([]database.CardSuggestion, 0)
+	if canEdit {
+		cardSuggestions, err = database.GetDeckCardSuggestions(deckId)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte("failed to get card suggestions"))
+			return
+		}
+	}
+
+	cardBatches := make([]database.CardBatch, 0)
+	if canEdit {
+		cardBatches, err = database.GetDeckCardBatches(deckId)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte("failed to get card batches"))
+			return
+		}
+	}
+
+	mergeDecks := make([]database.Deck, 0)
+	if canEdit {
+		readableDecks, err := database.GetReadableDecks(basePageData.User.Id)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte("failed to get readable decks"))
+			return
+		}
+
+		for _, readableDeck := range readableDecks {
+			if readableDeck.Id != deckId {
+				mergeDecks = append(mergeDecks, readableDeck)
+			}
+		}
+	}
+
+	var sourceDeck database.Deck
+	upstreamChanges := make([]database.UpstreamCardChange, 0)
+	if deck.SourceDeckId.Valid {
+		canReadSource, err := database.UserHasDeckAccess(basePageData.User.Id, deck.SourceDeckId.UUID, "READ")
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte("failed to check deck access"))
+			return
+		}
+
+		if canReadSource {
+			sourceDeck, err = database.GetDeck(deck.SourceDeckId.UUID)
+			if err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				_, _ = w.Write([]byte("failed to get source deck"))
+				return
+			}
+
+			upstreamChanges, err = database.GetDeckUpstreamChanges(deckId)
+			if err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				_, _ = w.Write([]byte("failed to get upstream changes"))
+				return
+			}
+		}
+	}
+
+	deckHistory, err := database.GetDeckHistory(deckId)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("failed to get deck history"))
+		return
+	}
+
+	cardIds := make([]uuid.UUID, 0, len(cards))
+	for _, card := range cards {
+		cardIds = append(cardIds, card.Id)
+	}
+
+	cardHistories, err := database.GetCardHistories(cardIds)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("failed to get card history"))
+		return
+	}
+
 	tmpl, err := template.ParseFS(
 		static.StaticFiles,
 		"html/pages/base.html",
@@ -850,21 +1586,54 @@ func Deck(w http.ResponseWriter, r *http.Request) {
 		Deck     database.Deck
 		Category string
 		Text     string
+		Tag      string
+		DeckTags []string
 		Page     int
 		LastPage int
 		RowCount int
 		Cards    []database.Card
+
+		CanEdit     bool
+		CanManage   bool
+		AccessUsers []database.DeckAccessUser
+		MergeDecks  []database.Deck
+		CardBatches []database.CardBatch
+
+		CardSuggestions   []database.CardSuggestion
+		NewSuggestionCard database.Card
+
+		SourceDeck      database.Deck
+		UpstreamChanges []database.UpstreamCardChange
+
+		DeckHistory   []database.CardVersion
+		CardHistories map[uuid.UUID][]database.CardVersion
 	}
 
 	_ = tmpl.ExecuteTemplate(w, "base", data{
-		BasePageData: basePageData,
-		Deck:         deck,
-		Category:     category,
-		Text:         text,
-		Page:         page,
-		LastPage:     totalPageCount,
-		RowCount:     totalRowCount,
-		Cards:        cards,
+		BasePageData:    basePageData,
+		Deck:            deck,
+		Category:        category,
+		Text:            text,
+		Tag:             tag,
+		DeckTags:        deckTags,
+		Page:            page,
+		LastPage:        totalPageCount,
+		RowCount:        totalRowCount,
+		Cards:           cards,
+		CanEdit:         canEdit,
+		CanManage:       canManage,
+		AccessUsers:     accessUsers,
+		MergeDecks:      mergeDecks,
+		CardBatches:     cardBatches,
+		CardSuggestions: cardSuggestions,
+		NewSuggestionCard: database.Card{
+			DeckId:   deckId,
+			Category: "RESPONSE",
+		},
+		SourceDeck:      sourceDeck,
+		UpstreamChanges: upstreamChanges,
+		DeckHistory:     deckHistory,
+		CardHistories:   cardHistories,
 	})
 }
 
@@ -890,7 +1659,7 @@ func DeckAccess(w http.ResponseWriter, r *http.Request) {
 	basePageData := api.GetBasePageData(r)
 	basePageData.PageTitle = "Card Judge - Deck"
 
-	hasDeckAccess, err := database.UserHasDeckAccess(basePageData.User.Id, deckId)
+	hasDeckAccess, err := database.UserHasDeckAccess(basePageData.User.Id, deckId, "READ")
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		_, _ = w.Write([]byte("failed to check deck access"))
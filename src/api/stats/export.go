@@ -0,0 +1,366 @@
+package apiStats
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/grantfbarnes/card-judge/api"
+	"github.com/grantfbarnes/card-judge/database"
+)
+
+var exportColumnInvalidRegExp = regexp.MustCompile(`[^a-z0-9]+`)
+
+func GetLeaderboardExport(w http.ResponseWriter, r *http.Request) {
+	userId := api.GetUserId(r)
+	if userId == uuid.Nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to get user id."))
+		return
+	}
+
+	err := r.ParseForm()
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to parse form."))
+		return
+	}
+
+	format, ok := getExportFormat(r)
+	if !ok {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Invalid export format."))
+		return
+	}
+
+	// the export is never paged
+	topic, subject, filter, err := getLeaderboardParameters(r)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("Failed to get current season."))
+		return
+	}
+	filter.Page = 0
+
+	headers, rows, _, err := database.GetStatsLeaderboard(userId, topic, subject, filter)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(err.Error()))
+		return
+	}
+
+	columns := make([]string, 0)
+	for _, header := range headers {
+		columns = append(columns, getExportColumnName(header))
+	}
+
+	values := make([]map[string]string, 0)
+	for _, row := range rows {
+		value := make(map[string]string)
+		for i, column := range columns {
+			value[column] = row[i]
+		}
+		values = append(values, value)
+	}
+
+	writeExport(w, format, fmt.Sprintf("card-judge-leaderboard-%s-%s", topic, subject), columns, rows, values)
+}
+
+func GetUserExport(w http.ResponseWriter, r *http.Request) {
+	userIdString := r.PathValue("userId")
+	userId, err := uuid.Parse(userIdString)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to get user id from path."))
+		return
+	}
+
+	requestUserId := api.GetUserId(r)
+	if requestUserId == uuid.Nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to get user id."))
+		return
+	}
+
+	format, ok := getExportFormat(r)
+	if !ok {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Invalid export format."))
+		return
+	}
+
+	userStats, err := database.GetStatsUser(userId)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("Failed to get user stats."))
+		return
+	}
+
+	columns := []string{
+		"user_name",
+		"game_play_count",
+		"game_win_count",
+		"round_play_count",
+		"round_win_count",
+		"response_card_play_count",
+		"response_card_discard_count",
+		"prompt_card_play_count",
+		"prompt_card_skip_count",
+		"credits_spent_count",
+		"credits_earned_count",
+		"lobby_kick_count",
+		"flip_table_count",
+	}
+	row := []string{
+		userStats.UserName,
+		strconv.Itoa(userStats.GamePlayCount),
+		strconv.Itoa(userStats.GameWinCount),
+		strconv.Itoa(userStats.RoundPlayCount),
+		strconv.Itoa(userStats.RoundWinCount),
+		strconv.Itoa(userStats.ResponseCardPlayCount),
+		strconv.Itoa(userStats.ResponseCardDiscardCount),
+		strconv.Itoa(userStats.PromptCardPlayCount),
+		strconv.Itoa(userStats.PromptCardSkipCount),
+		strconv.Itoa(userStats.CreditsSpentCount),
+		strconv.Itoa(userStats.CreditsEarnedCount),
+		strconv.Itoa(userStats.LobbyKickCount),
+		strconv.Itoa(userStats.FlipTableCount),
+	}
+	writeExport(w, format, "card-judge-user-stats", columns, [][]string{row}, userStats)
+}
+
+func GetUserAchievementsExport(w http.ResponseWriter, r *http.Request) {
+	userIdString := r.PathValue("userId")
+	userId, err := uuid.Parse(userIdString)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to get user id from path."))
+		return
+	}
+
+	requestUserId := api.GetUserId(r)
+	if requestUserId == uuid.Nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to get user id."))
+		return
+	}
+
+	format, ok := getExportFormat(r)
+	if !ok {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Invalid export format."))
+		return
+	}
+
+	userAchievements, err := database.GetAchievementsUser(userId)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("Failed to get user achievements."))
+		return
+	}
+
+	rows := make([][]string, 0)
+	for _, achievement := range userAchievements {
+		rows = append(rows, []string{achievement.Name, strconv.Itoa(achievement.Progress)})
+	}
+
+	writeExport(w, format, "card-judge-user-achievements", []string{"name", "progress"}, rows, userAchievements)
+}
+
+// GetUserDataExport dumps everything logged about a user. Only the user and
+// admins may download it.
+func GetUserDataExport(w http.ResponseWriter, r *http.Request) {
+	userIdString := r.PathValue("userId")
+	userId, err := uuid.Parse(userIdString)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to get user id from path."))
+		return
+	}
+
+	requestUserId := api.GetUserId(r)
+	if requestUserId == uuid.Nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to get user id."))
+		return
+	}
+
+	if requestUserId != userId {
+		isAdmin, err := database.GetUserIsAdmin(requestUserId)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte("Failed to check user access."))
+			return
+		}
+
+		if !isAdmin {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte("User does not have access."))
+			return
+		}
+	}
+
+	format, ok := getExportFormat(r)
+	if !ok {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Invalid export format."))
+		return
+	}
+
+	userData, err := database.GetUserDataExport(userId)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("Failed to get user data."))
+		return
+	}
+
+	// a CSV only has room for one table, so it holds the event log
+	rows := make([][]string, 0)
+	for _, event := range userData.Events {
+		roundId := ""
+		if event.RoundId.Valid {
+			roundId = event.RoundId.UUID.String()
+		}
+
+		rows = append(rows, []string{
+			event.CreatedOnDate.Format(time.RFC3339),
+			event.EventType,
+			event.LobbyId.String(),
+			event.LobbyName,
+			roundId,
+			event.CardText,
+			event.Category,
+			strconv.Itoa(event.Amount),
+		})
+	}
+
+	columns := []string{"created_on_date", "event_type", "lobby_id", "lobby_name", "round_id", "card_text", "category", "amount"}
+	writeExport(w, format, "card-judge-user-data", columns, rows, userData)
+}
+
+func GetCardExport(w http.ResponseWriter, r *http.Request) {
+	cardIdString := r.PathValue("cardId")
+	cardId, err := uuid.Parse(cardIdString)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to get card id from path."))
+		return
+	}
+
+	userId := api.GetUserId(r)
+	if userId == uuid.Nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to get user id."))
+		return
+	}
+
+	format, ok := getExportFormat(r)
+	if !ok {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Invalid export format."))
+		return
+	}
+
+	card, err := database.GetCard(cardId)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("Failed to get card."))
+		return
+	}
+
+	hasDeckAccess, err := database.UserHasDeckAccess(userId, card.DeckId, "READ")
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("Failed to check deck access."))
+		return
+	}
+
+	if !hasDeckAccess {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte("User does not have access."))
+		return
+	}
+
+	cardStats, err := database.GetStatsCard(cardId)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("Failed to get card stats."))
+		return
+	}
+
+	columns := []string{"deck_name", "category", "text", "play_count", "win_count", "discard_count", "skip_count"}
+	row := []string{
+		cardStats.DeckName,
+		cardStats.Category,
+		cardStats.Text,
+		strconv.Itoa(cardStats.PlayCount),
+		strconv.Itoa(cardStats.WinCount),
+		strconv.Itoa(cardStats.DiscardCount),
+		strconv.Itoa(cardStats.SkipCount),
+	}
+	writeExport(w, format, "card-judge-card-stats", columns, [][]string{row}, cardStats)
+}
+
+// getExportFormat defaults to CSV, which opens straight into a spreadsheet.
+func getExportFormat(r *http.Request) (string, bool) {
+	format := r.URL.Query().Get("format")
+	switch format {
+	case "", "csv":
+		return "csv", true
+	case "json":
+		return "json", true
+	}
+	return "", false
+}
+
+// getExportColumnName turns a display header like "Games Won" into the
+// stable column name games_won.
+func getExportColumnName(header string) string {
+	return strings.Trim(exportColumnInvalidRegExp.ReplaceAllString(strings.ToLower(header), "_"), "_")
+}
+
+// writeExport sends the rows under the columns as CSV, or the value as JSON.
+func writeExport(w http.ResponseWriter, format string, fileName string, columns []string, rows [][]string, value any) {
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fileName+"."+format))
+
+	if format == "json" {
+		w.Header().Set("Content-Type", "application/json")
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		_ = encoder.Encode(value)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv")
+	writer := csv.NewWriter(w)
+	defer writer.Flush()
+	_ = writer.Write(columns)
+	for _, row := range rows {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			cells[i] = getExportCsvCell(cell)
+		}
+		_ = writer.Write(cells)
+	}
+}
+
+// getExportCsvCell quotes text a spreadsheet would run as a formula, like a
+// lobby or card named =HYPERLINK(...). Numbers such as a negative rating
+// change are left as they are.
+func getExportCsvCell(cell string) string {
+	if cell == "" || !strings.ContainsRune("=+-@\t\r", rune(cell[0])) {
+		return cell
+	}
+
+	if _, err := strconv.ParseFloat(cell, 64); err == nil {
+		return cell
+	}
+
+	return "'" + cell
+}
@@ -0,0 +1,130 @@
+package apiStats
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/grantfbarnes/card-judge/api"
+	"github.com/grantfbarnes/card-judge/database"
+)
+
+func CreateSeason(w http.ResponseWriter, r *http.Request) {
+	userId := api.GetUserId(r)
+	if userId == uuid.Nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to get user id."))
+		return
+	}
+
+	isAdmin, err := database.GetUserIsAdmin(userId)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("Failed to check user access."))
+		return
+	}
+
+	if !isAdmin {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte("User does not have access."))
+		return
+	}
+
+	err = r.ParseForm()
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to parse form."))
+		return
+	}
+
+	name := strings.TrimSpace(r.Form.Get("name"))
+	if name == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("No name found."))
+		return
+	}
+
+	startDate, err := time.Parse("2006-01-02", r.Form.Get("startDate"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to parse start date."))
+		return
+	}
+
+	endDate, err := time.Parse("2006-01-02", r.Form.Get("endDate"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to parse end date."))
+		return
+	}
+
+	if endDate.Before(startDate) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("End date cannot be before start date."))
+		return
+	}
+
+	overlapCount, err := database.CountOverlappingSeasons(startDate, endDate)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("Failed to check existing seasons."))
+		return
+	}
+
+	if overlapCount > 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Season overlaps another season."))
+		return
+	}
+
+	_, err = database.CreateSeason(name, startDate, endDate)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("Failed to create season."))
+		return
+	}
+
+	w.Header().Add("HX-Refresh", "true")
+	w.WriteHeader(http.StatusCreated)
+}
+
+func DeleteSeason(w http.ResponseWriter, r *http.Request) {
+	seasonIdString := r.PathValue("seasonId")
+	seasonId, err := uuid.Parse(seasonIdString)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to get season id from path."))
+		return
+	}
+
+	userId := api.GetUserId(r)
+	if userId == uuid.Nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to get user id."))
+		return
+	}
+
+	isAdmin, err := database.GetUserIsAdmin(userId)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("Failed to check user access."))
+		return
+	}
+
+	if !isAdmin {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte("User does not have access."))
+		return
+	}
+
+	err = database.DeleteSeason(seasonId)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("Failed to delete season."))
+		return
+	}
+
+	w.Header().Add("HX-Redirect", "/stats/seasons")
+	w.WriteHeader(http.StatusOK)
+}
@@ -2,7 +2,10 @@ package apiStats
 
 import (
 	"net/http"
+	"strconv"
+	"strings"
 	"text/template"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/grantfbarnes/card-judge/api"
@@ -25,21 +28,18 @@ func GetLeaderboard(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var timeframe string
-	var topic string
-	var subject string
-	for key, val := range r.Form {
-		switch key {
-		case "timeframe":
-			timeframe = val[0]
-		case "topic":
-			topic = val[0]
-		case "subject":
-			subject = val[0]
-		}
+	topic, subject, filter, err := getLeaderboardParameters(r)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("Failed to get current season."))
+		return
+	}
+
+	if filter.Page < 1 {
+		filter.Page = 1
 	}
 
-	headers, rows, err := database.GetStatsLeaderboard(userId, timeframe, topic, subject)
+	headers, rows, rowCount, err := database.GetStatsLeaderboard(userId, topic, subject, filter)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		_, _ = w.Write([]byte(err.Error()))
@@ -56,13 +56,212 @@ func GetLeaderboard(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	type column struct {
+		Number int
+		Name   string
+		Sorted bool
+	}
+
 	type data struct {
-		Headers []string
-		Rows    [][]string
+		Columns        []column
+		SortDescending bool
+		Rows           [][]string
+		Page           int
+		LastPage       int
+	}
+
+	columns := make([]column, 0)
+	for i, header := range headers {
+		columns = append(columns, column{
+			Number: i + 1,
+			Name:   header,
+			Sorted: i+1 == filter.SortColumn,
+		})
 	}
 
 	_ = tmpl.ExecuteTemplate(w, "stats-table", data{
-		Headers: headers,
-		Rows:    rows,
+		Columns:        columns,
+		SortDescending: filter.SortDescending,
+		Rows:           rows,
+		Page:           filter.Page,
+		LastPage:       max((rowCount+9)/10, 1),
 	})
 }
+
+func RecomputeRatings(w http.ResponseWriter, r *http.Request) {
+	userId := api.GetUserId(r)
+	if userId == uuid.Nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to get user id."))
+		return
+	}
+
+	isAdmin, err := database.GetUserIsAdmin(userId)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("Failed to check user access."))
+		return
+	}
+
+	if !isAdmin {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte("User does not have access."))
+		return
+	}
+
+	err = database.RecomputeUserRatings()
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("Failed to recompute ratings."))
+		return
+	}
+
+	w.Header().Add("HX-Refresh", "true")
+	w.WriteHeader(http.StatusOK)
+}
+
+func RebuildStatRollups(w http.ResponseWriter, r *http.Request) {
+	userId := api.GetUserId(r)
+	if userId == uuid.Nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to get user id."))
+		return
+	}
+
+	isAdmin, err := database.GetUserIsAdmin(userId)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("Failed to check user access."))
+		return
+	}
+
+	if !isAdmin {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte("User does not have access."))
+		return
+	}
+
+	err = database.RebuildStatRollups()
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("Failed to rebuild stat rollups."))
+		return
+	}
+
+	w.Header().Add("HX-Refresh", "true")
+	w.WriteHeader(http.StatusOK)
+}
+
+func CheckStatRollups(w http.ResponseWriter, r *http.Request) {
+	userId := api.GetUserId(r)
+	if userId == uuid.Nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Failed to get user id."))
+		return
+	}
+
+	isAdmin, err := database.GetUserIsAdmin(userId)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("Failed to check user access."))
+		return
+	}
+
+	if !isAdmin {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte("User does not have access."))
+		return
+	}
+
+	statRollupChecks, err := database.CheckStatRollups()
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("Failed to check stat rollups."))
+		return
+	}
+
+	tmpl, err := template.ParseFS(
+		static.StaticFiles,
+		"html/components/tables/stat-rollup-table.html",
+	)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte("Failed to parse HTML."))
+		return
+	}
+
+	_ = tmpl.ExecuteTemplate(w, "stat-rollup-table", statRollupChecks)
+}
+
+// getLeaderboardParameters reads the leaderboard form, which the export uses
+// as well, so both always show the same rows.
+func getLeaderboardParameters(r *http.Request) (string, string, database.LeaderboardFilter, error) {
+	var topic string
+	var subject string
+	var filter database.LeaderboardFilter
+
+	// the stats are rolled up by day, so time frames start at midnight
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	for key, val := range r.Form {
+		switch key {
+		case "timeframe":
+			switch val[0] {
+			case "year":
+				filter.FromDate = today.AddDate(-1, 0, 0)
+			case "quarter":
+				filter.FromDate = today.AddDate(0, -3, 0)
+			case "month":
+				filter.FromDate = today.AddDate(0, -1, 0)
+			case "day":
+				filter.FromDate = today.AddDate(0, 0, -1)
+			case "season":
+				season, err := database.GetCurrentSeason()
+				if err != nil {
+					return topic, subject, filter, err
+				}
+
+				// the season may have ended since the page was loaded
+				if season.Id != uuid.Nil {
+					filter.FromDate = season.StartDate
+					filter.ToDate = season.ToDate()
+					filter.SeasonId = season.Id
+				}
+			}
+		case "topic":
+			topic = val[0]
+		case "subject":
+			subject = val[0]
+		case "lobbyName":
+			filter.LobbyName = strings.TrimSpace(val[0])
+		case "userNames":
+			for _, userName := range strings.Split(val[0], ",") {
+				userName = strings.TrimSpace(userName)
+				if userName != "" {
+					filter.UserNames = append(filter.UserNames, userName)
+				}
+			}
+		case "sortColumn":
+			filter.SortColumn, _ = strconv.Atoi(val[0])
+		case "sortDescending":
+			filter.SortDescending = val[0] == "true"
+		case "page":
+			filter.Page, _ = strconv.Atoi(val[0])
+		}
+	}
+
+	// a chosen date range replaces the time frame
+	fromDate, err := time.Parse("2006-01-02", r.Form.Get("fromDate"))
+	if err == nil {
+		filter.FromDate = fromDate
+	}
+
+	// the to date is inclusive on the page, so everything before the next day counts
+	toDate, err := time.Parse("2006-01-02", r.Form.Get("toDate"))
+	if err == nil {
+		filter.ToDate = toDate.AddDate(0, 0, 1)
+	}
+
+	return topic, subject, filter, nil
+}
@@ -3,10 +3,19 @@ package database
 import (
 	"errors"
 	"log"
+	"time"
 
 	"github.com/google/uuid"
 )
 
+type DeckAccessUser struct {
+	CreatedOnDate time.Time
+
+	UserId   uuid.UUID
+	UserName string
+	Role     string
+}
+
 func UserHasLobbyAccess(userId uuid.UUID, lobbyId uuid.UUID) (bool, error) {
 	sqlString := "SELECT FN_USER_HAS_LOBBY_ACCESS (?, ?)"
 	rows, err := query(sqlString, userId, lobbyId)
@@ -34,9 +43,11 @@ func AddUserLobbyAccess(userId uuid.UUID, lobbyId uuid.UUID) error {
 	return execute(sqlString, userId, lobbyId)
 }
 
-func UserHasDeckAccess(userId uuid.UUID, deckId uuid.UUID) (bool, error) {
-	sqlString := "SELECT FN_USER_HAS_DECK_ACCESS (?, ?)"
-	rows, err := query(sqlString, userId, deckId)
+// UserHasDeckAccess checks the user's role on the deck allows the capability:
+// READ for any role, EDIT for editors and owners, MANAGE for owners only.
+func UserHasDeckAccess(userId uuid.UUID, deckId uuid.UUID, capability string) (bool, error) {
+	sqlString := "SELECT FN_USER_HAS_DECK_ACCESS (?, ?, ?)"
+	rows, err := query(sqlString, userId, deckId, capability)
 	if err != nil {
 		return false, err
 	}
@@ -53,10 +64,131 @@ func UserHasDeckAccess(userId uuid.UUID, deckId uuid.UUID) (bool, error) {
 	return hasAccess, nil
 }
 
-func AddUserDeckAccess(userId uuid.UUID, deckId uuid.UUID) error {
+func AddUserDeckAccess(userId uuid.UUID, deckId uuid.UUID, role string) error {
 	sqlString := `
-		INSERT INTO USER_ACCESS_DECK(USER_ID, DECK_ID)
-		VALUES (?, ?)
+		INSERT INTO USER_ACCESS_DECK(USER_ID, DECK_ID, ROLE)
+		VALUES (?, ?, ?)
+	`
+	return execute(sqlString, userId, deckId, role)
+}
+
+func SetUserDeckAccess(userId uuid.UUID, deckId uuid.UUID, role string) error {
+	sqlString := `
+		INSERT INTO USER_ACCESS_DECK(USER_ID, DECK_ID, ROLE)
+		VALUES (?, ?, ?)
+		ON DUPLICATE KEY UPDATE
+			ROLE = VALUES(ROLE)
+	`
+	return execute(sqlString, userId, deckId, role)
+}
+
+// GetUserDeckRole returns an empty role when the user has no access row for the
+// deck.
+func GetUserDeckRole(userId uuid.UUID, deckId uuid.UUID) (string, error) {
+	var role string
+
+	sqlString := `
+		SELECT
+			ROLE
+		FROM USER_ACCESS_DECK
+		WHERE USER_ID = ?
+			AND DECK_ID = ?
+	`
+	rows, err := query(sqlString, userId, deckId)
+	if err != nil {
+		return role, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err := rows.Scan(&role); err != nil {
+			log.Println(err)
+			return role, errors.New("failed to scan row in query results")
+		}
+	}
+
+	return role, nil
+}
+
+func SetUserDeckRole(userId uuid.UUID, deckId uuid.UUID, role string) error {
+	sqlString := `
+		UPDATE USER_ACCESS_DECK
+		SET ROLE = ?
+		WHERE USER_ID = ?
+			AND DECK_ID = ?
+	`
+	return execute(sqlString, role, userId, deckId)
+}
+
+func RemoveUserDeckAccess(userId uuid.UUID, deckId uuid.UUID) error {
+	sqlString := `
+		DELETE
+		FROM USER_ACCESS_DECK
+		WHERE USER_ID = ?
+			AND DECK_ID = ?
 	`
 	return execute(sqlString, userId, deckId)
 }
+
+func GetDeckAccessUsers(deckId uuid.UUID) ([]DeckAccessUser, error) {
+	sqlString := `
+		SELECT
+			UAD.CREATED_ON_DATE,
+			U.ID AS USER_ID,
+			U.NAME AS USER_NAME,
+			UAD.ROLE
+		FROM USER_ACCESS_DECK AS UAD
+			INNER JOIN USER AS U ON U.ID = UAD.USER_ID
+		WHERE UAD.DECK_ID = ?
+		ORDER BY FIELD(UAD.ROLE, 'OWNER', 'EDITOR', 'VIEWER'),
+			U.NAME
+	`
+	rows, err := query(sqlString, deckId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]DeckAccessUser, 0)
+	for rows.Next() {
+		var deckAccessUser DeckAccessUser
+		if err := rows.Scan(
+			&deckAccessUser.CreatedOnDate,
+			&deckAccessUser.UserId,
+			&deckAccessUser.UserName,
+			&deckAccessUser.Role,
+		); err != nil {
+			log.Println(err)
+			return nil, errors.New("failed to scan row in query results")
+		}
+		result = append(result, deckAccessUser)
+	}
+	return result, nil
+}
+
+// GetDeckOtherOwnerCount counts the deck's owners besides the given user.
+func GetDeckOtherOwnerCount(deckId uuid.UUID, userId uuid.UUID) (int, error) {
+	sqlString := `
+		SELECT
+			COUNT(*)
+		FROM USER_ACCESS_DECK
+		WHERE DECK_ID = ?
+			AND USER_ID <> ?
+			AND ROLE = 'OWNER'
+	`
+	rows, err := query(sqlString, deckId, userId)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	var count int
+	for rows.Next() {
+		if err := rows.Scan(&count); err != nil {
+			log.Println(err)
+			return 0, errors.New("failed to scan row in query results")
+		}
+	}
+
+	return count, nil
+}
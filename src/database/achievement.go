@@ -0,0 +1,442 @@
+package database
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Achievement struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Count       int    `json:"count"`
+
+	// percent of the way to the last tier
+	Progress int `json:"progress"`
+
+	// highest tier reached, empty before the first
+	TierName string `json:"tier_name"`
+}
+
+// AchievementDefinition is one counter from the achievement counts query,
+// with the counts that unlock each tier.
+type AchievementDefinition struct {
+	Key         string
+	Name        string
+	Description string
+	Tiers       []AchievementTier
+
+	// hidden achievements are only listed once unlocked
+	Hidden bool
+}
+
+type AchievementTier struct {
+	Name      string
+	Threshold int
+}
+
+// AchievementBadge is an unlocked tier of an achievement.
+type AchievementBadge struct {
+	UserId        uuid.UUID
+	UserName      string
+	Name          string
+	Description   string
+	Tier          int
+	TierName      string
+	CreatedOnDate time.Time
+}
+
+func (achievementBadge AchievementBadge) FullName() string {
+	if achievementBadge.TierName == "" {
+		return achievementBadge.Name
+	}
+	return achievementBadge.TierName + " " + achievementBadge.Name
+}
+
+var achievementTierNames = []string{"Bronze", "Silver", "Gold"}
+
+func achievementTiers(thresholds ...int) []AchievementTier {
+	if len(thresholds) == 1 {
+		return []AchievementTier{{Threshold: thresholds[0]}}
+	}
+
+	result := make([]AchievementTier, 0)
+	for i, threshold := range thresholds {
+		result = append(result, AchievementTier{
+			Name:      achievementTierNames[i],
+			Threshold: threshold,
+		})
+	}
+	return result
+}
+
+// achievementDefinitions is listed in the order achievements are shown. Keys
+// are stored with unlocks, so they must never change.
+var achievementDefinitions = []AchievementDefinition{
+	{Key: "GAME-WIN", Name: "Games Won", Description: "Win the most rounds in a game.", Tiers: achievementTiers(1, 10, 100)},
+	{Key: "GAME-PLAY", Name: "Games Played", Description: "Play a card in a game.", Tiers: achievementTiers(1, 10, 100)},
+	{Key: "WINNING-STREAK", Name: "Winning Streaks", Description: "Win enough rounds in a row to pay the winning streak.", Tiers: achievementTiers(1, 10, 100)},
+	{Key: "LOSING-STREAK", Name: "Losing Streaks", Description: "Lose enough rounds in a row to earn the losing streak.", Tiers: achievementTiers(1, 10, 100)},
+	{Key: "SKIP-JUDGE", Name: "Skipped Being Judge", Description: "Pay to skip a turn as judge.", Tiers: achievementTiers(1, 10, 100)},
+	{Key: "ALERT", Name: "Lobby Alerts", Description: "Pay to alert the lobby.", Tiers: achievementTiers(1, 10, 100)},
+	{Key: "GAMBLE", Name: "Gambles Made", Description: "Gamble credits.", Tiers: achievementTiers(1, 10, 100)},
+	{Key: "GAMBLE-WIN", Name: "Gambles Won", Description: "Win a gamble.", Tiers: achievementTiers(1, 10, 100)},
+	{Key: "BET", Name: "Bets Placed", Description: "Bet credits on winning the round.", Tiers: achievementTiers(1, 10, 100)},
+	{Key: "BET-WIN", Name: "Bets Won", Description: "Win a round you bet on.", Tiers: achievementTiers(1, 10, 100)},
+	{Key: "EXTRA-RESPONSE", Name: "Extra Responses", Description: "Pay for an extra response.", Tiers: achievementTiers(1, 10, 100)},
+	{Key: "BLOCK-RESPONSE", Name: "Blocked Responses", Description: "Pay to block another player's response.", Tiers: achievementTiers(1, 10, 100)},
+	{Key: "STEAL", Name: "Stolen Cards Played", Description: "Play a card stolen from another player.", Tiers: achievementTiers(1, 10, 100)},
+	{Key: "SURPRISE", Name: "Surprise Cards Played", Description: "Play a surprise card.", Tiers: achievementTiers(1, 10, 100)},
+	{Key: "FIND", Name: "Find Cards Played", Description: "Find a card in the deck and play it.", Tiers: achievementTiers(1, 10, 100)},
+	{Key: "WILD", Name: "Wild Cards Played", Description: "Write a wild card and play it.", Tiers: achievementTiers(1, 10, 100)},
+	{Key: "PERK", Name: "Perks Purchased", Description: "Buy a perk.", Tiers: achievementTiers(1, 10, 100)},
+	{Key: "DISCARD", Name: "Cards Discarded", Description: "Discard a card from your hand.", Tiers: achievementTiers(1, 10, 100)},
+	{Key: "SKIP-PROMPT", Name: "Prompts Skipped", Description: "Skip a prompt card as judge.", Tiers: achievementTiers(1, 10, 100)},
+	{Key: "KICK", Name: "Kicked From Lobby", Description: "Get voted out of a lobby.", Tiers: achievementTiers(1, 10, 100)},
+	{Key: "FLIP-TABLE", Name: "Flipped Tables", Description: "Flip the table and storm out.", Tiers: achievementTiers(1, 10, 100)},
+	{Key: "PURCHASE", Name: "Shameless", Description: "Try to buy credits with real money.", Tiers: achievementTiers(1), Hidden: true},
+}
+
+func getAchievementDefinition(key string) (AchievementDefinition, bool) {
+	for _, definition := range achievementDefinitions {
+		if definition.Key == key {
+			return definition, true
+		}
+	}
+	return AchievementDefinition{}, false
+}
+
+// getAchievementCounts counts everything achievements are unlocked by, keyed
+// by achievement key.
+func getAchievementCounts(userId uuid.UUID) (map[string]int, error) {
+	result := make(map[string]int)
+
+	sqlString := `
+		WITH CREDITS_SPENT AS (
+				SELECT
+					CATEGORY
+				FROM LOG_CREDITS_SPENT
+				WHERE USER_ID = ?
+			)
+		SELECT
+			'GAME-WIN',
+			(SELECT COUNT(DISTINCT LOBBY_ID) FROM V_GAME_WINNER WHERE USER_ID = ?)
+		UNION
+		SELECT
+			'GAME-PLAY',
+			(SELECT COUNT(DISTINCT LOBBY_ID) FROM LOG_RESPONSE_CARD WHERE PLAYER_USER_ID = ?)
+		UNION
+		SELECT
+			CATEGORY,
+			COUNT(*)
+		FROM CREDITS_SPENT
+		GROUP BY CATEGORY
+		UNION
+		SELECT
+			'DISCARD',
+			(SELECT COUNT(*) FROM LOG_DISCARD WHERE USER_ID = ?)
+		UNION
+		SELECT
+			'SKIP-PROMPT',
+			(SELECT COUNT(*) FROM LOG_SKIP WHERE USER_ID = ?)
+		UNION
+		SELECT
+			'KICK',
+			(SELECT COUNT(*) FROM LOG_KICK WHERE USER_ID = ?)
+		UNION
+		SELECT
+			'FLIP-TABLE',
+			(SELECT COUNT(*) FROM LOG_FLIP_TABLE WHERE USER_ID = ?)
+	`
+	rows, err := query(sqlString, userId, userId, userId, userId, userId, userId, userId)
+	if err != nil {
+		return result, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var key string
+		var count int
+		if err := rows.Scan(&key, &count); err != nil {
+			log.Println(err)
+			return result, errors.New("failed to scan row in query results")
+		}
+		result[key] = count
+	}
+
+	return result, nil
+}
+
+func GetAchievementsUser(userId uuid.UUID) ([]Achievement, error) {
+	result := make([]Achievement, 0)
+
+	counts, err := getAchievementCounts(userId)
+	if err != nil {
+		return result, err
+	}
+
+	for _, definition := range achievementDefinitions {
+		count := counts[definition.Key]
+
+		achievement := Achievement{
+			Name:        definition.Name,
+			Description: definition.Description,
+			Count:       count,
+		}
+
+		for _, tier := range definition.Tiers {
+			if count >= tier.Threshold {
+				achievement.TierName = tier.Name
+				if achievement.TierName == "" {
+					achievement.TierName = "Unlocked"
+				}
+			}
+		}
+
+		if definition.Hidden && achievement.TierName == "" {
+			continue
+		}
+
+		lastThreshold := definition.Tiers[len(definition.Tiers)-1].Threshold
+		achievement.Progress = min(getPercent(count, lastThreshold), 100)
+
+		result = append(result, achievement)
+	}
+
+	return result, nil
+}
+
+// GetAchievementBadgesUser lists the highest tier unlocked of each achievement,
+// newest first.
+func GetAchievementBadgesUser(userId uuid.UUID) ([]AchievementBadge, error) {
+	sqlString := `
+		SELECT
+			UA.USER_ID,
+			U.NAME,
+			UA.ACHIEVEMENT_KEY,
+			UA.TIER,
+			UA.CREATED_ON_DATE
+		FROM USER_ACHIEVEMENT AS UA
+			INNER JOIN USER AS U ON U.ID = UA.USER_ID
+		WHERE UA.USER_ID = ?
+			AND UA.TIER = (
+				SELECT
+					MAX(TIER)
+				FROM USER_ACHIEVEMENT
+				WHERE USER_ID = UA.USER_ID
+					AND ACHIEVEMENT_KEY = UA.ACHIEVEMENT_KEY
+			)
+		ORDER BY UA.CREATED_ON_DATE DESC
+	`
+	rows, err := query(sqlString, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]AchievementBadge, 0)
+	for rows.Next() {
+		var badge AchievementBadge
+		var key string
+		if err := rows.Scan(
+			&badge.UserId,
+			&badge.UserName,
+			&key,
+			&badge.Tier,
+			&badge.CreatedOnDate,
+		); err != nil {
+			log.Println(err)
+			return nil, errors.New("failed to scan row in query results")
+		}
+
+		// unlocks of removed achievements are kept but not shown
+		definition, ok := getAchievementDefinition(key)
+		if !ok || badge.Tier < 1 || badge.Tier > len(definition.Tiers) {
+			continue
+		}
+
+		badge.Name = definition.Name
+		badge.Description = definition.Description
+		badge.TierName = definition.Tiers[badge.Tier-1].Name
+		result = append(result, badge)
+	}
+	return result, nil
+}
+
+// UnlockUserAchievements records every tier the user has reached but not yet
+// unlocked, and returns the highest new tier of each achievement. The lobby
+// is where it happened, or nil when it is unlocked outside of a game.
+func UnlockUserAchievements(userId uuid.UUID, lobbyId uuid.UUID) ([]AchievementBadge, error) {
+	result := make([]AchievementBadge, 0)
+
+	counts, err := getAchievementCounts(userId)
+	if err != nil {
+		return result, err
+	}
+
+	sqlString := `
+		SELECT
+			ACHIEVEMENT_KEY,
+			MAX(TIER)
+		FROM USER_ACHIEVEMENT
+		WHERE USER_ID = ?
+		GROUP BY ACHIEVEMENT_KEY
+	`
+	rows, err := query(sqlString, userId)
+	if err != nil {
+		return result, err
+	}
+	defer rows.Close()
+
+	unlockedTiers := make(map[string]int)
+	for rows.Next() {
+		var key string
+		var tier int
+		if err := rows.Scan(&key, &tier); err != nil {
+			log.Println(err)
+			return result, errors.New("failed to scan row in query results")
+		}
+		unlockedTiers[key] = tier
+	}
+
+	for _, definition := range achievementDefinitions {
+		var badge AchievementBadge
+		for i, tier := range definition.Tiers {
+			if i+1 <= unlockedTiers[definition.Key] || counts[definition.Key] < tier.Threshold {
+				continue
+			}
+
+			// another request may have unlocked the same tier first, in which
+			// case nothing is returned and it is not announced twice
+			sqlString := `
+				INSERT IGNORE INTO USER_ACHIEVEMENT (USER_ID, ACHIEVEMENT_KEY, TIER, LOBBY_ID)
+				VALUES (?, ?, ?, ?)
+				RETURNING CREATED_ON_DATE
+			`
+			rows, err := query(sqlString, userId, definition.Key, i+1, uuid.NullUUID{UUID: lobbyId, Valid: lobbyId != uuid.Nil})
+			if err != nil {
+				return result, err
+			}
+
+			for rows.Next() {
+				badge = AchievementBadge{
+					UserId:      userId,
+					Name:        definition.Name,
+					Description: definition.Description,
+					Tier:        i + 1,
+					TierName:    tier.Name,
+				}
+				if err := rows.Scan(&badge.CreatedOnDate); err != nil {
+					rows.Close()
+					log.Println(err)
+					return result, errors.New("failed to scan row in query results")
+				}
+			}
+			rows.Close()
+		}
+
+		if badge.Tier > 0 {
+			result = append(result, badge)
+		}
+	}
+
+	if len(result) == 0 {
+		return result, nil
+	}
+
+	user, err := GetUser(userId)
+	if err != nil {
+		return result, err
+	}
+
+	if user.Id == uuid.Nil {
+		return result, errors.New("failed to find user")
+	}
+
+	for i := range result {
+		result[i].UserName = user.Name
+	}
+
+	return result, nil
+}
+
+// SeedUserAchievements unlocks the tiers every user reached before unlocks
+// were kept, without a lobby, so the first round played afterwards does not
+// announce them all. It only runs once.
+func SeedUserAchievements() error {
+	sqlString := `
+		SELECT
+			ID
+		FROM USER
+		WHERE NOT EXISTS (
+				SELECT
+					ID
+				FROM SETTING
+				WHERE NAME = 'USER_ACHIEVEMENT_SEEDED'
+			)
+	`
+	rows, err := query(sqlString)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	userIds := make([]uuid.UUID, 0)
+	for rows.Next() {
+		var userId uuid.UUID
+		if err := rows.Scan(&userId); err != nil {
+			log.Println(err)
+			return errors.New("failed to scan row in query results")
+		}
+		userIds = append(userIds, userId)
+	}
+
+	for _, userId := range userIds {
+		_, err = UnlockUserAchievements(userId, uuid.Nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	sqlString = `
+		INSERT IGNORE INTO SETTING(NAME, VALUE)
+		VALUES ('USER_ACHIEVEMENT_SEEDED', 'TRUE')
+	`
+	return execute(sqlString)
+}
+
+// GetLobbyRoundUserIds finds the judge and every user with a response in the
+// current round, who are the only users ending the round can unlock anything
+// for. It has to be called before the round ends.
+func GetLobbyRoundUserIds(lobbyId uuid.UUID) ([]uuid.UUID, error) {
+	sqlString := `
+		SELECT
+			P.USER_ID
+		FROM RESPONSE AS R
+			INNER JOIN PLAYER AS P ON P.ID = R.PLAYER_ID
+		WHERE P.LOBBY_ID = ?
+		UNION
+		SELECT
+			USER_ID
+		FROM PLAYER
+		WHERE ID = FN_GET_LOBBY_JUDGE_PLAYER_ID(?)
+	`
+	rows, err := query(sqlString, lobbyId, lobbyId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]uuid.UUID, 0)
+	for rows.Next() {
+		var userId uuid.UUID
+		if err := rows.Scan(&userId); err != nil {
+			log.Println(err)
+			return nil, errors.New("failed to scan row in query results")
+		}
+		result = append(result, userId)
+	}
+	return result, nil
+}
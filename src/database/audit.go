@@ -0,0 +1,299 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CardVersion struct {
+	Id            uuid.UUID
+	CreatedOnDate time.Time
+
+	AuditType string
+	CardId    uuid.UUID
+	DeckId    uuid.UUID
+	Category  string
+	Text      string
+	YouTube   sql.NullString
+	Image     sql.NullString
+
+	// state of the card after this version, from the next audit row or the card itself
+	NextText     sql.NullString
+	NextYouTube  sql.NullString
+	ImageChanged bool
+	CardExists   bool
+}
+
+type DiffSegment struct {
+	Text    string
+	Added   bool
+	Removed bool
+}
+
+// GetCardHistories reads the history of every card in one query, keyed by
+// card id.
+func GetCardHistories(cardIds []uuid.UUID) (map[uuid.UUID][]CardVersion, error) {
+	result := make(map[uuid.UUID][]CardVersion)
+	if len(cardIds) == 0 {
+		return result, nil
+	}
+
+	params := make([]any, 0, len(cardIds))
+	for _, cardId := range cardIds {
+		result[cardId] = make([]CardVersion, 0)
+		params = append(params, cardId)
+	}
+
+	where := "AC.CARD_ID IN (" + strings.Repeat("?, ", len(cardIds)-1) + "?)"
+	cardVersions, err := getCardVersions(where, 100, 100*len(cardIds), params...)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, cardVersion := range cardVersions {
+		result[cardVersion.CardId] = append(result[cardVersion.CardId], cardVersion)
+	}
+
+	return result, nil
+}
+
+func GetDeckHistory(deckId uuid.UUID) ([]CardVersion, error) {
+	return getCardVersions("AC.DECK_ID = ?", 50, 50, deckId)
+}
+
+// getCardVersions returns the newest versions first, at most cardLimit of them
+// for any one card.
+func getCardVersions(where string, cardLimit int, limit int, params ...any) ([]CardVersion, error) {
+	sqlString := `
+		SELECT
+			V.ID,
+			V.CREATED_ON_DATE,
+			V.AUDIT_TYPE,
+			V.CARD_ID,
+			V.DECK_ID,
+			V.CATEGORY,
+			V.TEXT,
+			V.YOUTUBE,
+			V.IMAGE,
+			V.NEXT_TEXT,
+			V.NEXT_YOUTUBE,
+			IF(V.IMAGE <=> V.NEXT_IMAGE, 0, 1) AS IMAGE_CHANGED,
+			V.CARD_EXISTS
+		FROM (
+				SELECT
+					AC.ID,
+					AC.CREATED_ON_DATE,
+					AC.AUDIT_TYPE,
+					AC.CARD_ID,
+					AC.DECK_ID,
+					AC.CATEGORY,
+					AC.TEXT,
+					AC.YOUTUBE,
+					AC.IMAGE,
+					IF(
+						LEAD(AC.ID) OVER (PARTITION BY AC.CARD_ID ORDER BY AC.CREATED_ON_DATE) IS NULL,
+						C.TEXT,
+						LEAD(AC.TEXT) OVER (PARTITION BY AC.CARD_ID ORDER BY AC.CREATED_ON_DATE)
+					) AS NEXT_TEXT,
+					IF(
+						LEAD(AC.ID) OVER (PARTITION BY AC.CARD_ID ORDER BY AC.CREATED_ON_DATE) IS NULL,
+						C.YOUTUBE,
+						LEAD(AC.YOUTUBE) OVER (PARTITION BY AC.CARD_ID ORDER BY AC.CREATED_ON_DATE)
+					) AS NEXT_YOUTUBE,
+					IF(
+						LEAD(AC.ID) OVER (PARTITION BY AC.CARD_ID ORDER BY AC.CREATED_ON_DATE) IS NULL,
+						C.IMAGE,
+						LEAD(AC.IMAGE) OVER (PARTITION BY AC.CARD_ID ORDER BY AC.CREATED_ON_DATE)
+					) AS NEXT_IMAGE,
+					IF(C.ID IS NULL, 0, 1) AS CARD_EXISTS,
+					ROW_NUMBER() OVER (PARTITION BY AC.CARD_ID ORDER BY AC.CREATED_ON_DATE DESC) AS CARD_VERSION_NUMBER
+				FROM AUDIT_CARD AS AC
+					LEFT JOIN CARD AS C ON C.ID = AC.CARD_ID
+				WHERE ` + where + `
+			) AS V
+		WHERE V.CARD_VERSION_NUMBER <= ?
+		ORDER BY V.CREATED_ON_DATE DESC
+		LIMIT ?
+	`
+	rows, err := query(sqlString, append(params, cardLimit, limit)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]CardVersion, 0)
+	for rows.Next() {
+		var cardVersion CardVersion
+		var imageBytes []byte
+		if err := rows.Scan(
+			&cardVersion.Id,
+			&cardVersion.CreatedOnDate,
+			&cardVersion.AuditType,
+			&cardVersion.CardId,
+			&cardVersion.DeckId,
+			&cardVersion.Category,
+			&cardVersion.Text,
+			&cardVersion.YouTube,
+			&imageBytes,
+			&cardVersion.NextText,
+			&cardVersion.NextYouTube,
+			&cardVersion.ImageChanged,
+			&cardVersion.CardExists,
+		); err != nil {
+			log.Println(err)
+			return nil, errors.New("failed to scan row in query results")
+		}
+
+		cardVersion.Image.Valid = imageBytes != nil
+		if cardVersion.Image.Valid {
+			cardVersion.Image.String = base64.StdEncoding.EncodeToString(imageBytes)
+		}
+
+		result = append(result, cardVersion)
+	}
+	return result, nil
+}
+
+// GetCardVersionDeckId returns the deck the card is in now, or the deck it
+// was deleted from if a revert would restore it.
+func GetCardVersionDeckId(auditCardId uuid.UUID) (uuid.UUID, error) {
+	var deckId uuid.UUID
+
+	sqlString := `
+		SELECT
+			COALESCE(C.DECK_ID, AC.DECK_ID) AS DECK_ID
+		FROM AUDIT_CARD AS AC
+			LEFT JOIN CARD AS C ON C.ID = AC.CARD_ID
+		WHERE AC.ID = ?
+	`
+	rows, err := query(sqlString, auditCardId)
+	if err != nil {
+		return deckId, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err := rows.Scan(&deckId); err != nil {
+			log.Println(err)
+			return deckId, errors.New("failed to scan row in query results")
+		}
+	}
+
+	if deckId == uuid.Nil {
+		return deckId, errors.New("card version not found")
+	}
+
+	return deckId, nil
+}
+
+// RevertCard sets the card back to the audited version, restoring it if it
+// has since been deleted. It returns false when another card in the deck
+// already has the text.
+func RevertCard(auditCardId uuid.UUID) (bool, error) {
+	var isReverted bool
+	sqlString := "CALL SP_REVERT_CARD (?)"
+	rows, err := query(sqlString, auditCardId)
+	if err != nil {
+		return isReverted, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err := rows.Scan(&isReverted); err != nil {
+			log.Println(err)
+			return isReverted, errors.New("failed to scan row in query results")
+		}
+	}
+
+	return isReverted, nil
+}
+
+func SetAuditRetentionDays(days int) error {
+	sqlString := `
+		INSERT INTO SETTING(NAME, VALUE)
+		VALUES ('AUDIT_RETENTION_DAYS', ?)
+		ON DUPLICATE KEY UPDATE
+			VALUE = VALUES(VALUE)
+	`
+	return execute(sqlString, strconv.Itoa(days))
+}
+
+// TextDiff compares the version's text to the text that replaced it, word by
+// word.
+func (cardVersion CardVersion) TextDiff() []DiffSegment {
+	if !cardVersion.NextText.Valid {
+		return []DiffSegment{{Text: cardVersion.Text}}
+	}
+
+	return diffWords(strings.Fields(cardVersion.Text), strings.Fields(cardVersion.NextText.String))
+}
+
+func (cardVersion CardVersion) TextChanged() bool {
+	return cardVersion.NextText.Valid && cardVersion.Text != cardVersion.NextText.String
+}
+
+func (cardVersion CardVersion) YouTubeChanged() bool {
+	return cardVersion.YouTube != cardVersion.NextYouTube
+}
+
+// diffWords builds the longest common subsequence of the words and marks the
+// rest as removed from before or added in after.
+func diffWords(before []string, after []string) []DiffSegment {
+	lengths := make([][]int, len(before)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(after)+1)
+	}
+
+	for i := len(before) - 1; i >= 0; i-- {
+		for j := len(after) - 1; j >= 0; j-- {
+			if before[i] == after[j] {
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			} else {
+				lengths[i][j] = max(lengths[i+1][j], lengths[i][j+1])
+			}
+		}
+	}
+
+	result := make([]DiffSegment, 0)
+	appendWord := func(word string, added bool, removed bool) {
+		last := len(result) - 1
+		if last >= 0 && result[last].Added == added && result[last].Removed == removed {
+			result[last].Text += " " + word
+			return
+		}
+		result = append(result, DiffSegment{Text: word, Added: added, Removed: removed})
+	}
+
+	i, j := 0, 0
+	for i < len(before) && j < len(after) {
+		switch {
+		case before[i] == after[j]:
+			appendWord(before[i], false, false)
+			i++
+			j++
+		case lengths[i+1][j] >= lengths[i][j+1]:
+			appendWord(before[i], false, true)
+			i++
+		default:
+			appendWord(after[j], true, false)
+			j++
+		}
+	}
+
+	for ; i < len(before); i++ {
+		appendWord(before[i], false, true)
+	}
+
+	for ; j < len(after); j++ {
+		appendWord(after[j], true, false)
+	}
+
+	return result
+}
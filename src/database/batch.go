@@ -0,0 +1,548 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+type CardBatch struct {
+	Id            uuid.UUID
+	CreatedOnDate time.Time
+
+	DeckId         uuid.UUID
+	TargetDeckId   uuid.NullUUID
+	TargetDeckName sql.NullString
+	UserName       sql.NullString
+	Operation      string
+	Detail         sql.NullString
+	CardCount      int
+	UndoneOnDate   sql.NullTime
+}
+
+// CardSelection is either the checked cards or every card matching the
+// deck page search.
+type CardSelection struct {
+	CardIds   []uuid.UUID
+	UseSearch bool
+	Category  string
+	Text      string
+	Tag       string
+}
+
+type CardReplacement struct {
+	CardId     uuid.UUID
+	Text       string
+	NewText    string
+	SkipReason string
+}
+
+func GetDeckCardBatches(deckId uuid.UUID) ([]CardBatch, error) {
+	sqlString := `
+		SELECT
+			CB.ID,
+			CB.CREATED_ON_DATE,
+			CB.DECK_ID,
+			CB.TARGET_DECK_ID,
+			TD.NAME AS TARGET_DECK_NAME,
+			U.NAME AS USER_NAME,
+			CB.OPERATION,
+			CB.DETAIL,
+			CB.CARD_COUNT,
+			CB.UNDONE_ON_DATE
+		FROM CARD_BATCH AS CB
+			LEFT JOIN DECK AS TD ON TD.ID = CB.TARGET_DECK_ID
+			LEFT JOIN USER AS U ON U.ID = CB.USER_ID
+		WHERE CB.DECK_ID = ?
+		ORDER BY CB.CREATED_ON_DATE DESC
+		LIMIT 20
+	`
+	rows, err := query(sqlString, deckId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]CardBatch, 0)
+	for rows.Next() {
+		var cardBatch CardBatch
+		if err := rows.Scan(
+			&cardBatch.Id,
+			&cardBatch.CreatedOnDate,
+			&cardBatch.DeckId,
+			&cardBatch.TargetDeckId,
+			&cardBatch.TargetDeckName,
+			&cardBatch.UserName,
+			&cardBatch.Operation,
+			&cardBatch.Detail,
+			&cardBatch.CardCount,
+			&cardBatch.UndoneOnDate,
+		); err != nil {
+			log.Println(err)
+			return nil, errors.New("failed to scan row in query results")
+		}
+		result = append(result, cardBatch)
+	}
+	return result, nil
+}
+
+func GetCardBatch(id uuid.UUID) (CardBatch, error) {
+	var cardBatch CardBatch
+
+	sqlString := `
+		SELECT
+			ID,
+			CREATED_ON_DATE,
+			DECK_ID,
+			TARGET_DECK_ID,
+			OPERATION,
+			DETAIL,
+			CARD_COUNT,
+			UNDONE_ON_DATE
+		FROM CARD_BATCH
+		WHERE ID = ?
+	`
+	rows, err := query(sqlString, id)
+	if err != nil {
+		return cardBatch, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err := rows.Scan(
+			&cardBatch.Id,
+			&cardBatch.CreatedOnDate,
+			&cardBatch.DeckId,
+			&cardBatch.TargetDeckId,
+			&cardBatch.Operation,
+			&cardBatch.Detail,
+			&cardBatch.CardCount,
+			&cardBatch.UndoneOnDate,
+		); err != nil {
+			log.Println(err)
+			return cardBatch, errors.New("failed to scan row in query results")
+		}
+	}
+
+	if cardBatch.Id == uuid.Nil {
+		return cardBatch, errors.New("card batch not found")
+	}
+
+	return cardBatch, nil
+}
+
+// UndoCardBatch puts every card in the batch back the way it was before the
+// batch, recreating deleted cards.
+func UndoCardBatch(id uuid.UUID) error {
+	sqlString := "CALL SP_UNDO_CARD_BATCH (?)"
+	return execute(sqlString, id)
+}
+
+// BulkMoveCards skips cards whose text already exists in the target deck.
+func BulkMoveCards(deckId uuid.UUID, userId uuid.UUID, selection CardSelection, targetDeckId uuid.UUID, targetDeckName string) (int, error) {
+	cards, err := getSelectedCards(deckId, selection, "NOT EXISTS (SELECT 1 FROM CARD WHERE DECK_ID = ? AND TEXT = C.TEXT)", targetDeckId)
+	if err != nil {
+		return 0, err
+	}
+
+	return applyCardBatch(deckId, targetDeckId, userId, "MOVE", "to "+targetDeckName, cards, `
+		UPDATE CARD
+		SET DECK_ID = ?
+		WHERE ID IN (%s)
+	`, targetDeckId)
+}
+
+func BulkSetCardCategory(deckId uuid.UUID, userId uuid.UUID, selection CardSelection, category string) (int, error) {
+	cards, err := getSelectedCards(deckId, selection, "C.CATEGORY <> ?", category)
+	if err != nil {
+		return 0, err
+	}
+
+	return applyCardBatch(deckId, uuid.Nil, userId, "CATEGORY", "to "+strings.ToLower(category), cards, `
+		UPDATE CARD
+		SET CATEGORY = ?
+		WHERE ID IN (%s)
+	`, category)
+}
+
+func BulkDeleteCards(deckId uuid.UUID, userId uuid.UUID, selection CardSelection) (int, error) {
+	cards, err := getSelectedCards(deckId, selection, "")
+	if err != nil {
+		return 0, err
+	}
+
+	return applyCardBatch(deckId, uuid.Nil, userId, "DELETE", "", cards, `
+		DELETE
+		FROM CARD
+		WHERE ID IN (%s)
+	`)
+}
+
+func BulkClearCardImages(deckId uuid.UUID, userId uuid.UUID, selection CardSelection) (int, error) {
+	cards, err := getSelectedCards(deckId, selection, "C.IMAGE IS NOT NULL")
+	if err != nil {
+		return 0, err
+	}
+
+	return applyCardBatch(deckId, uuid.Nil, userId, "CLEAR_IMAGES", "", cards, `
+		UPDATE CARD
+		SET IMAGE = NULL
+		WHERE ID IN (%s)
+	`)
+}
+
+func BulkAddCardTags(deckId uuid.UUID, userId uuid.UUID, selection CardSelection, tags []string) (int, error) {
+	if len(tags) == 0 {
+		return 0, nil
+	}
+
+	cards, err := getSelectedCards(deckId, selection, "")
+	if err != nil {
+		return 0, err
+	}
+
+	// one row per tag keeps this a single statement
+	tagRows := strings.Repeat("SELECT ? AS TAG UNION ALL ", len(tags)-1) + "SELECT ? AS TAG"
+	args := make([]any, 0)
+	for _, tag := range tags {
+		args = append(args, tag)
+	}
+
+	return applyCardBatch(deckId, uuid.Nil, userId, "ADD_TAGS", "#"+strings.Join(tags, " #"), cards, `
+		INSERT IGNORE INTO CARD_TAG(CARD_ID, TAG)
+		SELECT
+			C.ID,
+			T.TAG
+		FROM CARD AS C
+			CROSS JOIN (`+tagRows+`) AS T
+		WHERE C.ID IN (%s)
+	`, args...)
+}
+
+func BulkRemoveCardTags(deckId uuid.UUID, userId uuid.UUID, selection CardSelection, tags []string) (int, error) {
+	if len(tags) == 0 {
+		return 0, nil
+	}
+
+	tagParams := strings.Repeat("?, ", len(tags)-1) + "?"
+	args := make([]any, 0)
+	for _, tag := range tags {
+		args = append(args, tag)
+	}
+
+	cards, err := getSelectedCards(deckId, selection, "EXISTS (SELECT 1 FROM CARD_TAG WHERE CARD_ID = C.ID AND TAG IN ("+tagParams+"))", args...)
+	if err != nil {
+		return 0, err
+	}
+
+	return applyCardBatch(deckId, uuid.Nil, userId, "REMOVE_TAGS", "#"+strings.Join(tags, " #"), cards, `
+		DELETE
+		FROM CARD_TAG
+		WHERE TAG IN (`+tagParams+`)
+			AND CARD_ID IN (%s)
+	`, args...)
+}
+
+// GetCardReplacements finds the selected cards whose text changes with the
+// find and replace, noting the ones that cannot be saved.
+func GetCardReplacements(deckId uuid.UUID, selection CardSelection, find *regexp.Regexp, replace string) ([]CardReplacement, error) {
+	cards, err := getSelectedCards(deckId, selection, "")
+	if err != nil {
+		return nil, err
+	}
+
+	sqlString := `
+		SELECT
+			TEXT
+		FROM CARD
+		WHERE DECK_ID = ?
+	`
+	rows, err := query(sqlString, deckId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// the unique text constraint ignores case
+	deckTexts := make(map[string]bool)
+	for rows.Next() {
+		var text string
+		if err := rows.Scan(&text); err != nil {
+			log.Println(err)
+			return nil, errors.New("failed to scan row in query results")
+		}
+		deckTexts[strings.ToLower(text)] = true
+	}
+
+	result := make([]CardReplacement, 0)
+	for _, card := range cards {
+		newText, err := ProcessCardText(find.ReplaceAllString(card.Text, replace))
+		if err != nil {
+			return nil, err
+		}
+
+		if newText == card.Text {
+			continue
+		}
+
+		replacement := CardReplacement{
+			CardId:  card.Id,
+			Text:    card.Text,
+			NewText: newText,
+		}
+
+		switch {
+		case newText == "":
+			replacement.SkipReason = "No text left."
+		case utf8.RuneCountInString(newText) > 510:
+			replacement.SkipReason = "Text is too long."
+		case deckTexts[strings.ToLower(newText)]:
+			replacement.SkipReason = "Card text already exists."
+		}
+
+		if replacement.SkipReason == "" {
+			deckTexts[strings.ToLower(newText)] = true
+		}
+
+		result = append(result, replacement)
+	}
+	return result, nil
+}
+
+func BulkReplaceCardText(deckId uuid.UUID, userId uuid.UUID, selection CardSelection, find *regexp.Regexp, replace string) (int, error) {
+	replacements, err := GetCardReplacements(deckId, selection, find, replace)
+	if err != nil {
+		return 0, err
+	}
+
+	cards := make([]Card, 0)
+	for _, replacement := range replacements {
+		if replacement.SkipReason == "" {
+			cards = append(cards, Card{Id: replacement.CardId})
+		}
+	}
+
+	if len(cards) == 0 {
+		return 0, nil
+	}
+
+	err = transaction(func(tx *sql.Tx) error {
+		err := createCardBatch(tx, deckId, uuid.Nil, userId, "REPLACE", fmt.Sprintf("%q with %q", find.String(), replace), cards)
+		if err != nil {
+			return err
+		}
+
+		sqlString := `
+			UPDATE CARD
+			SET TEXT = ?
+			WHERE ID = ?
+		`
+		for _, replacement := range replacements {
+			if replacement.SkipReason != "" {
+				continue
+			}
+
+			_, err = executeTx(tx, sqlString, replacement.NewText, replacement.CardId)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return len(cards), nil
+}
+
+func getSelectedCards(deckId uuid.UUID, selection CardSelection, extraWhere string, extraArgs ...any) ([]Card, error) {
+	var where string
+	var args []any
+	if selection.UseSearch {
+		where, args = cardSearchWhere(deckId, selection.Category, selection.Text, selection.Tag)
+	} else {
+		if len(selection.CardIds) == 0 {
+			return make([]Card, 0), nil
+		}
+
+		where = "C.DECK_ID = ? AND C.ID IN (" + strings.Repeat("?, ", len(selection.CardIds)-1) + "?)"
+		args = []any{deckId}
+		for _, cardId := range selection.CardIds {
+			args = append(args, cardId)
+		}
+	}
+
+	if extraWhere != "" {
+		where += " AND " + extraWhere
+		args = append(args, extraArgs...)
+	}
+
+	sqlString := `
+		SELECT
+			C.ID,
+			C.CATEGORY,
+			C.TEXT
+		FROM CARD AS C
+		WHERE ` + where + `
+		ORDER BY C.CATEGORY,
+			C.TEXT
+	`
+	rows, err := query(sqlString, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]Card, 0)
+	for rows.Next() {
+		var card Card
+		if err := rows.Scan(
+			&card.Id,
+			&card.Category,
+			&card.Text,
+		); err != nil {
+			log.Println(err)
+			return nil, errors.New("failed to scan row in query results")
+		}
+		result = append(result, card)
+	}
+	return result, nil
+}
+
+// applyCardBatch records the cards then runs the change in one transaction,
+// where the %s in the statement is filled with the card ids. Nothing is
+// recorded when there are no cards.
+func applyCardBatch(deckId uuid.UUID, targetDeckId uuid.UUID, userId uuid.UUID, operation string, detail string, cards []Card, sqlString string, args ...any) (int, error) {
+	if len(cards) == 0 {
+		return 0, nil
+	}
+
+	err := transaction(func(tx *sql.Tx) error {
+		err := createCardBatch(tx, deckId, targetDeckId, userId, operation, detail, cards)
+		if err != nil {
+			return err
+		}
+
+		cardParams, cardArgs := getCardIdParams(cards)
+		_, err = executeTx(tx, fmt.Sprintf(sqlString, cardParams), append(args, cardArgs...)...)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return len(cards), nil
+}
+
+// createCardBatch snapshots the cards and their tags so the batch can be
+// undone.
+func createCardBatch(tx *sql.Tx, deckId uuid.UUID, targetDeckId uuid.UUID, userId uuid.UUID, operation string, detail string, cards []Card) error {
+	batchId, err := uuid.NewUUID()
+	if err != nil {
+		log.Println(err)
+		return errors.New("failed to generate new id")
+	}
+
+	var targetDeckIdParam any
+	if targetDeckId != uuid.Nil {
+		targetDeckIdParam = targetDeckId
+	}
+
+	var detailParam any
+	if detail != "" {
+		detailRunes := []rune(detail)
+		detailParam = string(detailRunes[:min(len(detailRunes), 510)])
+	}
+
+	sqlString := `
+		INSERT INTO CARD_BATCH(ID, DECK_ID, TARGET_DECK_ID, USER_ID, OPERATION, DETAIL, CARD_COUNT)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err = executeTx(tx, sqlString, batchId, deckId, targetDeckIdParam, userId, operation, detailParam, len(cards))
+	if err != nil {
+		return err
+	}
+
+	cardParams, cardArgs := getCardIdParams(cards)
+
+	sqlString = fmt.Sprintf(`
+		INSERT INTO CARD_BATCH_CARD(
+			BATCH_ID,
+			CARD_ID,
+			CARD_CREATED_ON_DATE,
+			DECK_ID,
+			CATEGORY,
+			TEXT,
+			YOUTUBE,
+			IMAGE,
+			SOURCE_CARD_ID,
+			CONTENT_RATING
+		)
+		SELECT
+			?,
+			ID,
+			CREATED_ON_DATE,
+			DECK_ID,
+			CATEGORY,
+			TEXT,
+			YOUTUBE,
+			IMAGE,
+			SOURCE_CARD_ID,
+			CONTENT_RATING
+		FROM CARD
+		WHERE ID IN (%s)
+	`, cardParams)
+	_, err = executeTx(tx, sqlString, append([]any{batchId}, cardArgs...)...)
+	if err != nil {
+		return err
+	}
+
+	sqlString = fmt.Sprintf(`
+		INSERT INTO CARD_BATCH_TAG(BATCH_ID, CARD_ID, TAG)
+		SELECT
+			?,
+			CARD_ID,
+			TAG
+		FROM CARD_TAG
+		WHERE CARD_ID IN (%s)
+	`, cardParams)
+	_, err = executeTx(tx, sqlString, append([]any{batchId}, cardArgs...)...)
+	return err
+}
+
+func getCardIdParams(cards []Card) (string, []any) {
+	args := make([]any, 0)
+	for _, card := range cards {
+		args = append(args, card.Id)
+	}
+	return strings.Repeat("?, ", len(cards)-1) + "?", args
+}
+
+func (cardBatch CardBatch) OperationName() string {
+	switch cardBatch.Operation {
+	case "MOVE":
+		return "Moved"
+	case "CATEGORY":
+		return "Changed category"
+	case "REPLACE":
+		return "Replaced"
+	case "DELETE":
+		return "Deleted"
+	case "ADD_TAGS":
+		return "Added tags"
+	case "REMOVE_TAGS":
+		return "Removed tags"
+	case "CLEAR_IMAGES":
+		return "Cleared images"
+	}
+	return cardBatch.Operation
+}
@@ -2,10 +2,13 @@ package database
 
 import (
 	"database/sql"
-	"encoding/base64"
 	"errors"
 	"log"
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 )
@@ -19,7 +22,12 @@ type Card struct {
 	Category string
 	Text     string
 	YouTube  sql.NullString
-	Image    sql.NullString
+
+	// SHA1 of the image, which is served from /card/{id}/image
+	Image sql.NullString
+
+	ContentRating sql.NullString
+	Tags          []string
 }
 
 type DisplayCard struct {
@@ -33,22 +41,43 @@ type DisplayCard struct {
 	Image    sql.NullString
 }
 
+type CardReport struct {
+	CardId           uuid.UUID
+	LastReportedDate time.Time
+
+	DeckName         string
+	Category         string
+	Text             string
+	IsInReview       bool
+	ReporterCount    int
+	OffensiveCount   int
+	BrokenBlankCount int
+	DuplicateCount   int
+	TypoCount        int
+}
+
 type LobbyCard struct {
 	LobbyId uuid.UUID
 	Card
 }
 
-func SearchCardsInDeck(deckId uuid.UUID, category string, text string, page int) ([]Card, error) {
-	if category == "" {
-		category = "%"
-	}
+type PromptSegment struct {
+	Text       string
+	IsResponse bool
+}
 
-	text = "%" + text + "%"
+// blanks in card text are normalized to this when the card is saved
+const promptBlank = "_____"
 
+func SearchCardsInDeck(deckId uuid.UUID, category string, text string, tag string, page int) ([]Card, error) {
 	if page < 1 {
 		page = 1
 	}
 
+	where, args := cardSearchWhere(deckId, category, text, tag)
+	relevance, relevanceArgs := parseCardQuery(text).relevance(cardColumns)
+	args = append(args, relevanceArgs...)
+
 	sqlString := `
 		SELECT
 			C.ID,
@@ -58,16 +87,22 @@ func SearchCardsInDeck(deckId uuid.UUID, category string, text string, page int)
 			C.CATEGORY,
 			C.TEXT,
 			C.YOUTUBE,
-			C.IMAGE
+			SHA1(C.IMAGE) AS IMAGE,
+			C.CONTENT_RATING,
+			(
+				SELECT
+					GROUP_CONCAT(CT.TAG ORDER BY CT.TAG SEPARATOR ',')
+				FROM CARD_TAG AS CT
+				WHERE CT.CARD_ID = C.ID
+			) AS TAGS
 		FROM CARD AS C
-		WHERE C.DECK_ID = ?
-			AND C.CATEGORY LIKE ?
-			AND C.TEXT LIKE ?
-		ORDER BY C.CHANGED_ON_DATE DESC,
+		WHERE ` + where + `
+		ORDER BY ` + relevance + ` DESC,
+			C.CHANGED_ON_DATE DESC,
 			C.TEXT ASC
 		LIMIT 10 OFFSET ?
 	`
-	rows, err := query(sqlString, deckId, category, text, (page-1)*10)
+	rows, err := query(sqlString, append(args, (page-1)*10)...)
 	if err != nil {
 		return nil, err
 	}
@@ -76,7 +111,7 @@ func SearchCardsInDeck(deckId uuid.UUID, category string, text string, page int)
 	result := make([]Card, 0)
 	for rows.Next() {
 		var card Card
-		var imageBytes []byte
+		var tags sql.NullString
 		if err := rows.Scan(
 			&card.Id,
 			&card.CreatedOnDate,
@@ -85,15 +120,17 @@ func SearchCardsInDeck(deckId uuid.UUID, category string, text string, page int)
 			&card.Category,
 			&card.Text,
 			&card.YouTube,
-			&imageBytes,
+			&card.Image,
+			&card.ContentRating,
+			&tags,
 		); err != nil {
 			log.Println(err)
 			return nil, errors.New("failed to scan row in query results")
 		}
 
-		card.Image.Valid = imageBytes != nil
-		if card.Image.Valid {
-			card.Image.String = base64.StdEncoding.EncodeToString(imageBytes)
+		card.Tags = make([]string, 0)
+		if tags.Valid {
+			card.Tags = strings.Split(tags.String, ",")
 		}
 
 		result = append(result, card)
@@ -101,22 +138,15 @@ func SearchCardsInDeck(deckId uuid.UUID, category string, text string, page int)
 	return result, nil
 }
 
-func CountCardsInDeck(deckId uuid.UUID, category string, text string) (int, error) {
-	if category == "" {
-		category = "%"
-	}
-
-	text = "%" + text + "%"
+func CountCardsInDeck(deckId uuid.UUID, category string, text string, tag string) (int, error) {
+	where, args := cardSearchWhere(deckId, category, text, tag)
 
 	sqlString := `
 		SELECT
 			COUNT(*)
 		FROM CARD AS C
-		WHERE C.DECK_ID = ?
-			AND C.CATEGORY LIKE ?
-			AND C.TEXT LIKE ?
-	`
-	rows, err := query(sqlString, deckId, category, text)
+		WHERE ` + where
+	rows, err := query(sqlString, args...)
 	if err != nil {
 		return 0, err
 	}
@@ -133,11 +163,13 @@ func CountCardsInDeck(deckId uuid.UUID, category string, text string) (int, erro
 	return count, nil
 }
 
-func SearchCardsInReview(page int) ([]DisplayCard, error) {
+func SearchCardsInReview(text string, page int) ([]DisplayCard, error) {
 	if page < 1 {
 		page = 1
 	}
 
+	where, args := parseCardQuery(text).where(reviewCardColumns)
+
 	sqlString := `
 		SELECT
 			RC.ID,
@@ -146,13 +178,14 @@ func SearchCardsInReview(page int) ([]DisplayCard, error) {
 			RC.CATEGORY,
 			RC.TEXT,
 			RC.YOUTUBE,
-			RC.IMAGE
+			SHA1(RC.IMAGE) AS IMAGE
 		FROM REVIEW_CARD AS RC
 			INNER JOIN DECK AS D ON D.ID = RC.DECK_ID
+		WHERE ` + where + `
 		ORDER BY RC.CREATED_ON_DATE
 		LIMIT 10 OFFSET ?
 	`
-	rows, err := query(sqlString, (page-1)*10)
+	rows, err := query(sqlString, append(args, (page-1)*10)...)
 	if err != nil {
 		return nil, err
 	}
@@ -161,7 +194,6 @@ func SearchCardsInReview(page int) ([]DisplayCard, error) {
 	result := make([]DisplayCard, 0)
 	for rows.Next() {
 		var card DisplayCard
-		var imageBytes []byte
 		if err := rows.Scan(
 			&card.Id,
 			&card.CreatedOnDate,
@@ -169,29 +201,26 @@ func SearchCardsInReview(page int) ([]DisplayCard, error) {
 			&card.Category,
 			&card.Text,
 			&card.YouTube,
-			&imageBytes,
+			&card.Image,
 		); err != nil {
 			log.Println(err)
 			return nil, errors.New("failed to scan row in query results")
 		}
 
-		card.Image.Valid = imageBytes != nil
-		if card.Image.Valid {
-			card.Image.String = base64.StdEncoding.EncodeToString(imageBytes)
-		}
-
 		result = append(result, card)
 	}
 	return result, nil
 }
 
-func CountCardsInReview() (int, error) {
+func CountCardsInReview(text string) (int, error) {
+	where, args := parseCardQuery(text).where(reviewCardColumns)
+
 	sqlString := `
 		SELECT
 			COUNT(*)
 		FROM REVIEW_CARD AS RC
-	`
-	rows, err := query(sqlString)
+		WHERE ` + where
+	rows, err := query(sqlString, args...)
 	if err != nil {
 		return 0, err
 	}
@@ -213,12 +242,17 @@ func SearchCardsWithAccess(userId uuid.UUID, deckName string, category string, t
 	if category == "" {
 		category = "%"
 	}
-	text = "%" + text + "%"
 
 	if page < 1 {
 		page = 1
 	}
 
+	cardQuery := parseCardQuery(text)
+	where, args := cardQuery.where(cardColumns)
+	relevance, relevanceArgs := cardQuery.relevance(cardColumns)
+	args = append([]any{userId, deckName, category}, args...)
+	args = append(args, relevanceArgs...)
+
 	sqlString := `
 		SELECT
 			C.ID,
@@ -227,18 +261,19 @@ func SearchCardsWithAccess(userId uuid.UUID, deckName string, category string, t
 			C.CATEGORY,
 			C.TEXT,
 			C.YOUTUBE,
-			C.IMAGE
+			SHA1(C.IMAGE) AS IMAGE
 		FROM CARD AS C
 			INNER JOIN DECK AS D ON D.ID = C.DECK_ID
-		WHERE FN_USER_HAS_DECK_ACCESS(?, C.DECK_ID)
+		WHERE FN_USER_HAS_DECK_ACCESS(?, C.DECK_ID, 'READ')
 			AND D.NAME LIKE ?
 			AND C.CATEGORY LIKE ?
-			AND C.TEXT LIKE ?
-		ORDER BY C.CHANGED_ON_DATE DESC,
+			AND ` + where + `
+		ORDER BY ` + relevance + ` DESC,
+			C.CHANGED_ON_DATE DESC,
 			C.TEXT ASC
 		LIMIT 10 OFFSET ?
 	`
-	rows, err := query(sqlString, userId, deckName, category, text, (page-1)*10)
+	rows, err := query(sqlString, append(args, (page-1)*10)...)
 	if err != nil {
 		return nil, err
 	}
@@ -247,7 +282,6 @@ func SearchCardsWithAccess(userId uuid.UUID, deckName string, category string, t
 	result := make([]DisplayCard, 0)
 	for rows.Next() {
 		var card DisplayCard
-		var imageBytes []byte
 		if err := rows.Scan(
 			&card.Id,
 			&card.CreatedOnDate,
@@ -255,17 +289,12 @@ func SearchCardsWithAccess(userId uuid.UUID, deckName string, category string, t
 			&card.Category,
 			&card.Text,
 			&card.YouTube,
-			&imageBytes,
+			&card.Image,
 		); err != nil {
 			log.Println(err)
 			return nil, errors.New("failed to scan row in query results")
 		}
 
-		card.Image.Valid = imageBytes != nil
-		if card.Image.Valid {
-			card.Image.String = base64.StdEncoding.EncodeToString(imageBytes)
-		}
-
 		result = append(result, card)
 	}
 	return result, nil
@@ -276,19 +305,20 @@ func CountCardsWithAccess(userId uuid.UUID, deckName string, category string, te
 	if category == "" {
 		category = "%"
 	}
-	text = "%" + text + "%"
+
+	where, args := parseCardQuery(text).where(cardColumns)
+	args = append([]any{userId, deckName, category}, args...)
 
 	sqlString := `
 		SELECT
 			COUNT(*)
 		FROM CARD AS C
 			INNER JOIN DECK AS D ON D.ID = C.DECK_ID
-		WHERE FN_USER_HAS_DECK_ACCESS(?, C.DECK_ID)
+		WHERE FN_USER_HAS_DECK_ACCESS(?, C.DECK_ID, 'READ')
 			AND D.NAME LIKE ?
 			AND C.CATEGORY LIKE ?
-			AND C.TEXT LIKE ?
-	`
-	rows, err := query(sqlString, userId, deckName, category, text)
+			AND ` + where
+	rows, err := query(sqlString, args...)
 	if err != nil {
 		return 0, err
 	}
@@ -306,6 +336,22 @@ func CountCardsWithAccess(userId uuid.UUID, deckName string, category string, te
 }
 
 func FindDrawPileCard(lobbyId uuid.UUID, text string) ([]LobbyCard, error) {
+	cardQuery := parseCardQuery(text)
+	if cardQuery.isEmpty() {
+		return make([]LobbyCard, 0), nil
+	}
+
+	where, whereArgs := cardQuery.where(cardColumns)
+	relevance, args := cardQuery.relevance(cardColumns)
+	if cardQuery.isPlain() {
+		// plain words are matched by the full-text index, as they always were
+		where = "MATCH (C.TEXT) AGAINST (? IN NATURAL LANGUAGE MODE)"
+		whereArgs = []any{text}
+		args = []any{text}
+	}
+	args = append(args, lobbyId)
+	args = append(args, whereArgs...)
+
 	sqlString := `
 		SELECT
 			LOBBY_ID,
@@ -319,7 +365,7 @@ func FindDrawPileCard(lobbyId uuid.UUID, text string) ([]LobbyCard, error) {
 			IMAGE
 		FROM (
 				SELECT
-					MATCH (TEXT) AGAINST(? IN NATURAL LANGUAGE MODE) AS SCORE,
+					` + relevance + ` AS SCORE,
 					DP.LOBBY_ID,
 					C.ID,
 					C.CREATED_ON_DATE,
@@ -328,17 +374,18 @@ func FindDrawPileCard(lobbyId uuid.UUID, text string) ([]LobbyCard, error) {
 					C.CATEGORY,
 					C.TEXT,
 					C.YOUTUBE,
-					C.IMAGE
+					SHA1(C.IMAGE) AS IMAGE
 				FROM CARD AS C
 					INNER JOIN DRAW_PILE AS DP ON DP.CARD_ID = C.ID
 				WHERE DP.LOBBY_ID = ?
 					AND C.CATEGORY = 'RESPONSE'
-					AND MATCH (TEXT) AGAINST(? IN NATURAL LANGUAGE MODE)
+					AND ` + where + `
 			) AS T
-		ORDER BY SCORE DESC
+		ORDER BY SCORE DESC,
+			TEXT ASC
 		LIMIT 10
 	`
-	rows, err := query(sqlString, text, lobbyId, text)
+	rows, err := query(sqlString, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -347,7 +394,6 @@ func FindDrawPileCard(lobbyId uuid.UUID, text string) ([]LobbyCard, error) {
 	result := make([]LobbyCard, 0)
 	for rows.Next() {
 		var card LobbyCard
-		var imageBytes []byte
 		if err := rows.Scan(
 			&card.LobbyId,
 			&card.Id,
@@ -357,16 +403,11 @@ func FindDrawPileCard(lobbyId uuid.UUID, text string) ([]LobbyCard, error) {
 			&card.Category,
 			&card.Text,
 			&card.YouTube,
-			&imageBytes); err != nil {
+			&card.Image); err != nil {
 			log.Println(err)
 			return result, errors.New("failed to scan row in query results")
 		}
 
-		card.Image.Valid = imageBytes != nil
-		if card.Image.Valid {
-			card.Image.String = base64.StdEncoding.EncodeToString(imageBytes)
-		}
-
 		result = append(result, card)
 	}
 	return result, nil
@@ -412,7 +453,7 @@ func GetCard(id uuid.UUID) (Card, error) {
 			CATEGORY,
 			TEXT,
 			YOUTUBE,
-			IMAGE
+			SHA1(IMAGE) AS IMAGE
 		FROM CARD
 		WHERE ID = ?
 	`
@@ -423,7 +464,6 @@ func GetCard(id uuid.UUID) (Card, error) {
 	defer rows.Close()
 
 	for rows.Next() {
-		var imageBytes []byte
 		if err := rows.Scan(
 			&card.Id,
 			&card.CreatedOnDate,
@@ -432,15 +472,10 @@ func GetCard(id uuid.UUID) (Card, error) {
 			&card.Category,
 			&card.Text,
 			&card.YouTube,
-			&imageBytes); err != nil {
+			&card.Image); err != nil {
 			log.Println(err)
 			return card, errors.New("failed to scan row in query results")
 		}
-
-		card.Image.Valid = imageBytes != nil
-		if card.Image.Valid {
-			card.Image.String = base64.StdEncoding.EncodeToString(imageBytes)
-		}
 	}
 
 	return card, nil
@@ -494,13 +529,16 @@ func GetResponseCardTextStart(responseId uuid.UUID) (string, error) {
 
 	sqlString := `
 		SELECT
+			(SELECT TEXT FROM CARD WHERE ID = J.CARD_ID) AS JUDGE_CARD_TEXT,
 			C.TEXT
 		FROM RESPONSE AS R
+			INNER JOIN PLAYER AS P ON P.ID = R.PLAYER_ID
+			INNER JOIN JUDGE AS J ON J.LOBBY_ID = P.LOBBY_ID
 			INNER JOIN RESPONSE_CARD AS RC ON RC.RESPONSE_ID = R.ID
 			INNER JOIN CARD AS C ON C.ID = RC.CARD_ID
 		WHERE R.ID = ?
-		ORDER BY RC.CREATED_ON_DATE
-		LIMIT 1
+		ORDER BY RC.SLOT,
+			RC.CREATED_ON_DATE
 	`
 	rows, err := query(sqlString, responseId)
 	if err != nil {
@@ -508,20 +546,82 @@ func GetResponseCardTextStart(responseId uuid.UUID) (string, error) {
 	}
 	defer rows.Close()
 
+	var promptText sql.NullString
+	responseTexts := make([]string, 0)
 	for rows.Next() {
-		if err := rows.Scan(&text); err != nil {
+		var responseText string
+		if err := rows.Scan(&promptText, &responseText); err != nil {
 			log.Println(err)
 			return text, errors.New("failed to scan row in query results")
 		}
+		responseTexts = append(responseTexts, responseText)
 	}
 
-	if len(text) > 100 {
-		text = text[:100] + "..."
+	for _, segment := range fillPromptBlanks(promptText.String, responseTexts) {
+		text += segment.Text
+	}
+
+	if utf8.RuneCountInString(text) > 200 {
+		text = string([]rune(text)[:200]) + "..."
 	}
 
 	return text, nil
 }
 
+// ProcessCardText normalizes any run of underscores to a prompt blank.
+func ProcessCardText(text string) (string, error) {
+	normalizedText := text
+
+	blankRegExp, err := regexp.Compile(`__+`)
+	if err != nil {
+		return normalizedText, err
+	}
+
+	normalizedText = blankRegExp.ReplaceAllString(text, "_____")
+	normalizedText = strings.TrimSpace(normalizedText)
+
+	return normalizedText, err
+}
+
+// fillPromptBlanks puts the response texts into the prompt's blanks in order.
+// Responses without a blank, e.g. for a prompt that is a question, are added
+// to the end.
+func fillPromptBlanks(prompt string, responses []string) []PromptSegment {
+	result := make([]PromptSegment, 0)
+
+	parts := strings.Split(prompt, promptBlank)
+	for i, part := range parts {
+		if len(part) > 0 {
+			result = append(result, PromptSegment{Text: part})
+		}
+
+		if i == len(parts)-1 {
+			break
+		}
+
+		if i >= len(responses) {
+			result = append(result, PromptSegment{Text: promptBlank})
+			continue
+		}
+
+		response := strings.TrimSpace(responses[i])
+		// the sentence carries on in the prompt
+		if len(parts[i+1]) > 0 {
+			response = strings.TrimSuffix(response, ".")
+		}
+		result = append(result, PromptSegment{Text: response, IsResponse: true})
+	}
+
+	for i := len(parts) - 1; i < len(responses); i++ {
+		if len(result) > 0 {
+			result = append(result, PromptSegment{Text: " "})
+		}
+		result = append(result, PromptSegment{Text: strings.TrimSpace(responses[i]), IsResponse: true})
+	}
+
+	return result
+}
+
 func UpdateCard(id uuid.UUID, category string, text string, youtube string) error {
 	sqlString := `
 		UPDATE CARD
@@ -545,6 +645,31 @@ func SetCardImage(id uuid.UUID, imageBytes []byte) error {
 	return execute(sqlString, imageBytes, id)
 }
 
+func GetCardImage(id uuid.UUID) ([]byte, error) {
+	var imageBytes []byte
+
+	sqlString := `
+		SELECT
+			IMAGE
+		FROM CARD
+		WHERE ID = ?
+	`
+	rows, err := query(sqlString, id)
+	if err != nil {
+		return imageBytes, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err := rows.Scan(&imageBytes); err != nil {
+			log.Println(err)
+			return imageBytes, errors.New("failed to scan row in query results")
+		}
+	}
+
+	return imageBytes, nil
+}
+
 func DeleteCard(id uuid.UUID) error {
 	sqlString := `
 		DELETE
@@ -576,9 +701,215 @@ func RecoverCard(id uuid.UUID) error {
 
 func PermanentlyDeleteCard(id uuid.UUID) error {
 	sqlString := `
+		DELETE CR
+		FROM CARD_REPORT AS CR
+			INNER JOIN REVIEW_CARD AS RC ON RC.CARD_ID = CR.CARD_ID
+		WHERE RC.ID = ?
+	`
+	err := execute(sqlString, id)
+	if err != nil {
+		return err
+	}
+
+	sqlString = `
 		DELETE
 		FROM REVIEW_CARD
 		WHERE ID = ?
 	`
 	return execute(sqlString, id)
 }
+
+// PlayerCanSeeCard reports whether the card is in play where the player can
+// see it: the lobby's judge card, a response on the board, or the player's hand.
+func PlayerCanSeeCard(playerId uuid.UUID, cardId uuid.UUID) (bool, error) {
+	var canSee bool
+	sqlString := `
+		SELECT
+			EXISTS (
+				SELECT
+					J.ID
+				FROM JUDGE AS J
+					INNER JOIN PLAYER AS P ON P.LOBBY_ID = J.LOBBY_ID
+				WHERE P.ID = ?
+					AND J.CARD_ID = ?
+			)
+			OR EXISTS (
+				SELECT
+					RC.ID
+				FROM RESPONSE_CARD AS RC
+					INNER JOIN RESPONSE AS R ON R.ID = RC.RESPONSE_ID
+					INNER JOIN PLAYER AS RP ON RP.ID = R.PLAYER_ID
+					INNER JOIN PLAYER AS P ON P.LOBBY_ID = RP.LOBBY_ID
+				WHERE P.ID = ?
+					AND RC.CARD_ID = ?
+			)
+			OR EXISTS (
+				SELECT
+					ID
+				FROM HAND
+				WHERE PLAYER_ID = ?
+					AND CARD_ID = ?
+			)
+	`
+	rows, err := query(sqlString, playerId, cardId, playerId, cardId, playerId, cardId)
+	if err != nil {
+		return canSee, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err := rows.Scan(&canSee); err != nil {
+			log.Println(err)
+			return canSee, errors.New("failed to scan row in query results")
+		}
+	}
+
+	return canSee, nil
+}
+
+// UserCanSeeCardImage reports whether the user can read the card's deck, or
+// can see the card in play in one of their lobbies.
+func UserCanSeeCardImage(userId uuid.UUID, cardId uuid.UUID) (bool, error) {
+	var canSee bool
+	sqlString := `
+		SELECT
+			FN_USER_HAS_DECK_ACCESS(?, C.DECK_ID, 'READ')
+			OR EXISTS (
+				SELECT
+					J.ID
+				FROM JUDGE AS J
+					INNER JOIN PLAYER AS P ON P.LOBBY_ID = J.LOBBY_ID
+				WHERE P.USER_ID = ?
+					AND J.CARD_ID = C.ID
+			)
+			OR EXISTS (
+				SELECT
+					RC.ID
+				FROM RESPONSE_CARD AS RC
+					INNER JOIN RESPONSE AS R ON R.ID = RC.RESPONSE_ID
+					INNER JOIN PLAYER AS RP ON RP.ID = R.PLAYER_ID
+					INNER JOIN PLAYER AS P ON P.LOBBY_ID = RP.LOBBY_ID
+				WHERE P.USER_ID = ?
+					AND RC.CARD_ID = C.ID
+			)
+			OR EXISTS (
+				SELECT
+					H.ID
+				FROM HAND AS H
+					INNER JOIN PLAYER AS P ON P.ID = H.PLAYER_ID
+				WHERE P.USER_ID = ?
+					AND H.CARD_ID = C.ID
+			)
+		FROM CARD AS C
+		WHERE C.ID = ?
+	`
+	rows, err := query(sqlString, userId, userId, userId, userId, cardId)
+	if err != nil {
+		return canSee, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err := rows.Scan(&canSee); err != nil {
+			log.Println(err)
+			return canSee, errors.New("failed to scan row in query results")
+		}
+	}
+
+	return canSee, nil
+}
+
+// ReportCard records the player's report and returns true if the card reached
+// the report threshold and was moved to review.
+func ReportCard(cardId uuid.UUID, playerId uuid.UUID, reason string) (bool, error) {
+	var isInReview bool
+	sqlString := "CALL SP_REPORT_CARD (?, ?, ?)"
+	rows, err := query(sqlString, cardId, playerId, reason)
+	if err != nil {
+		return isInReview, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err := rows.Scan(&isInReview); err != nil {
+			log.Println(err)
+			return isInReview, errors.New("failed to scan row in query results")
+		}
+	}
+
+	return isInReview, nil
+}
+
+func GetCardReports() ([]CardReport, error) {
+	sqlString := `
+		SELECT
+			R.CARD_ID,
+			R.LAST_REPORTED_DATE,
+			D.NAME AS DECK_NAME,
+			COALESCE(C.CATEGORY, RC.CATEGORY) AS CATEGORY,
+			COALESCE(C.TEXT, RC.TEXT) AS TEXT,
+			IF(RC.ID IS NULL, 0, 1) AS IS_IN_REVIEW,
+			R.REPORTER_COUNT,
+			R.OFFENSIVE_COUNT,
+			R.BROKEN_BLANK_COUNT,
+			R.DUPLICATE_COUNT,
+			R.TYPO_COUNT
+		FROM (
+				SELECT
+					CARD_ID,
+					MAX(CREATED_ON_DATE) AS LAST_REPORTED_DATE,
+					COUNT(DISTINCT USER_ID) AS REPORTER_COUNT,
+					SUM(IF(REASON = 'OFFENSIVE', 1, 0)) AS OFFENSIVE_COUNT,
+					SUM(IF(REASON = 'BROKEN-BLANK', 1, 0)) AS BROKEN_BLANK_COUNT,
+					SUM(IF(REASON = 'DUPLICATE', 1, 0)) AS DUPLICATE_COUNT,
+					SUM(IF(REASON = 'TYPO', 1, 0)) AS TYPO_COUNT
+				FROM CARD_REPORT
+				GROUP BY CARD_ID
+			) AS R
+			LEFT JOIN CARD AS C ON C.ID = R.CARD_ID
+			LEFT JOIN REVIEW_CARD AS RC ON RC.CARD_ID = R.CARD_ID
+			INNER JOIN DECK AS D ON D.ID = COALESCE(C.DECK_ID, RC.DECK_ID)
+		ORDER BY R.LAST_REPORTED_DATE DESC
+		LIMIT 50
+	`
+	rows, err := query(sqlString)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]CardReport, 0)
+	for rows.Next() {
+		var cardReport CardReport
+		if err := rows.Scan(
+			&cardReport.CardId,
+			&cardReport.LastReportedDate,
+			&cardReport.DeckName,
+			&cardReport.Category,
+			&cardReport.Text,
+			&cardReport.IsInReview,
+			&cardReport.ReporterCount,
+			&cardReport.OffensiveCount,
+			&cardReport.BrokenBlankCount,
+			&cardReport.DuplicateCount,
+			&cardReport.TypoCount,
+		); err != nil {
+			log.Println(err)
+			return nil, errors.New("failed to scan row in query results")
+		}
+		result = append(result, cardReport)
+	}
+	return result, nil
+}
+
+// SetCardReportThreshold sets the number of distinct reporters needed to move
+// a card to review.
+func SetCardReportThreshold(threshold int) error {
+	sqlString := `
+		INSERT INTO SETTING(NAME, VALUE)
+		VALUES ('CARD_REPORT_THRESHOLD', ?)
+		ON DUPLICATE KEY UPDATE
+			VALUE = VALUES(VALUE)
+	`
+	return execute(sqlString, strconv.Itoa(threshold))
+}
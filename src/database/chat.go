@@ -0,0 +1,206 @@
+package database
+
+import (
+	"errors"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+type ChatMessage struct {
+	Id            uuid.UUID
+	CreatedOnDate time.Time
+
+	LobbyId  uuid.UUID
+	UserId   uuid.NullUUID
+	UserName string
+	Message  string
+}
+
+func GetLobbyChatMessages(lobbyId uuid.UUID, before time.Time, limit int) ([]ChatMessage, error) {
+	sqlString := `
+		SELECT
+			LCM.ID,
+			LCM.CREATED_ON_DATE,
+			LCM.LOBBY_ID,
+			LCM.USER_ID,
+			COALESCE(U.NAME, '') AS USER_NAME,
+			LCM.MESSAGE
+		FROM LOBBY_CHAT_MESSAGE AS LCM
+			LEFT JOIN USER AS U ON U.ID = LCM.USER_ID
+		WHERE LCM.LOBBY_ID = ?
+			AND LCM.CREATED_ON_DATE < ?
+		ORDER BY LCM.CREATED_ON_DATE DESC
+		LIMIT ?
+	`
+	rows, err := query(sqlString, lobbyId, before, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]ChatMessage, 0)
+	for rows.Next() {
+		var chatMessage ChatMessage
+		if err := rows.Scan(
+			&chatMessage.Id,
+			&chatMessage.CreatedOnDate,
+			&chatMessage.LobbyId,
+			&chatMessage.UserId,
+			&chatMessage.UserName,
+			&chatMessage.Message,
+		); err != nil {
+			log.Println(err)
+			return nil, errors.New("failed to scan row in query results")
+		}
+
+		// prepend so the result is in chronological order
+		result = append([]ChatMessage{chatMessage}, result...)
+	}
+	return result, nil
+}
+
+// AddLobbyChatMessage stores what a user typed as is, it is escaped when sent.
+// Messages without a user are already chat markup.
+func AddLobbyChatMessage(lobbyId uuid.UUID, userId uuid.UUID, message string) error {
+	sqlString := "CALL SP_ADD_LOBBY_CHAT_MESSAGE (?, ?, ?)"
+	if userId == uuid.Nil {
+		return execute(sqlString, lobbyId, nil, message)
+	} else {
+		return execute(sqlString, lobbyId, userId, message)
+	}
+}
+
+func UserIsMutedInLobby(lobbyId uuid.UUID, userId uuid.UUID) (bool, error) {
+	sqlString := `
+		SELECT
+			ID
+		FROM LOBBY_MUTE
+		WHERE LOBBY_ID = ?
+			AND USER_ID = ?
+	`
+	rows, err := query(sqlString, lobbyId, userId)
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+
+	return rows.Next(), nil
+}
+
+func MuteUserInLobby(lobbyId uuid.UUID, userId uuid.UUID) error {
+	sqlString := `
+		INSERT IGNORE INTO LOBBY_MUTE(LOBBY_ID, USER_ID)
+		VALUES (?, ?)
+	`
+	return execute(sqlString, lobbyId, userId)
+}
+
+func UnmuteUserInLobby(lobbyId uuid.UUID, userId uuid.UUID) error {
+	sqlString := `
+		DELETE
+		FROM LOBBY_MUTE
+		WHERE LOBBY_ID = ?
+			AND USER_ID = ?
+	`
+	return execute(sqlString, lobbyId, userId)
+}
+
+func GetLobbyFilterWords(lobbyId uuid.UUID) ([]string, error) {
+	sqlString := `
+		SELECT
+			WORD
+		FROM LOBBY_FILTER_WORD
+		WHERE LOBBY_ID = ?
+		ORDER BY WORD
+	`
+	rows, err := query(sqlString, lobbyId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]string, 0)
+	for rows.Next() {
+		var word string
+		if err := rows.Scan(&word); err != nil {
+			log.Println(err)
+			return nil, errors.New("failed to scan row in query results")
+		}
+		result = append(result, word)
+	}
+	return result, nil
+}
+
+func SetLobbyFilterWords(lobbyId uuid.UUID, words []string) error {
+	sqlString := `
+		DELETE
+		FROM LOBBY_FILTER_WORD
+		WHERE LOBBY_ID = ?
+	`
+	err := execute(sqlString, lobbyId)
+	if err != nil {
+		return err
+	}
+
+	for _, word := range words {
+		sqlString = `
+			INSERT IGNORE INTO LOBBY_FILTER_WORD(LOBBY_ID, WORD)
+			VALUES (?, ?)
+		`
+		err = execute(sqlString, lobbyId, word)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// FilterLobbyText masks any of the lobby's filter words found in the text.
+func FilterLobbyText(lobbyId uuid.UUID, text string) (string, error) {
+	words, err := GetLobbyFilterWords(lobbyId)
+	if err != nil {
+		return text, err
+	}
+
+	for _, word := range words {
+		re, err := regexp.Compile(`(?i)` + regexp.QuoteMeta(word))
+		if err != nil {
+			continue
+		}
+		text = maskWholeWords(re, text)
+	}
+
+	return text, nil
+}
+
+// maskWholeWords masks the matches that are not part of a longer word. \b only
+// knows ASCII, so it would find words inside accented ones and miss words
+// that start or end with an accented letter.
+func maskWholeWords(re *regexp.Regexp, text string) string {
+	var masked strings.Builder
+	last := 0
+	for _, match := range re.FindAllStringIndex(text, -1) {
+		before, _ := utf8.DecodeLastRuneInString(text[:match[0]])
+		after, _ := utf8.DecodeRuneInString(text[match[1]:])
+		if isWordRune(before) || isWordRune(after) {
+			continue
+		}
+
+		masked.WriteString(text[last:match[0]])
+		masked.WriteString(strings.Repeat("*", utf8.RuneCountInString(text[match[0]:match[1]])))
+		last = match[1]
+	}
+	masked.WriteString(text[last:])
+	return masked.String()
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsNumber(r)
+}
@@ -0,0 +1,219 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type StatCombo struct {
+	PromptCardId   uuid.UUID
+	PromptText     string
+	ResponseCardId uuid.UUID
+	ResponseText   string
+	WinCount       int
+}
+
+type StatComboDetail struct {
+	StatCombo
+	PlayCount   int
+	Occurrences []StatComboOccurrence
+}
+
+// StatComboOccurrence is one round the response card was played on the prompt
+// card, won or not.
+type StatComboOccurrence struct {
+	CreatedOnDate  time.Time
+	LobbyId        uuid.UUID
+	LobbyName      string
+	JudgeUserName  string
+	PlayerUserName string
+	Won            bool
+}
+
+func (statComboDetail StatComboDetail) WinPercent() int {
+	return getPercent(statComboDetail.WinCount, statComboDetail.PlayCount)
+}
+
+// GetStatsCardCombos finds the cards that won most often together with the
+// card, which are responses for a prompt and prompts for a response.
+func GetStatsCardCombos(userId uuid.UUID, cardId uuid.UUID, limit int) ([]StatCombo, error) {
+	return getStatsCombos("(LRC.JUDGE_CARD_ID = ? OR LRC.PLAYER_CARD_ID = ?)", 1, userId, limit, cardId, cardId)
+}
+
+// GetStatsComboHallOfFame finds the combinations that won most often across
+// all games. A combination has to win more than once to count as a classic.
+func GetStatsComboHallOfFame(userId uuid.UUID, limit int) ([]StatCombo, error) {
+	return getStatsCombos("1 = 1", 2, userId, limit)
+}
+
+func getStatsCombos(where string, minWinCount int, userId uuid.UUID, limit int, params ...any) ([]StatCombo, error) {
+	sqlString := fmt.Sprintf(`
+		SELECT
+			PC.ID,
+			PC.TEXT AS PROMPT_TEXT,
+			RC.ID,
+			RC.TEXT AS RESPONSE_TEXT,
+			COUNT(DISTINCT LRC.ROUND_ID) AS WIN_COUNT
+		FROM LOG_RESPONSE_CARD AS LRC
+			INNER JOIN LOG_WIN AS LW ON LW.RESPONSE_ID = LRC.RESPONSE_ID
+			INNER JOIN CARD AS PC ON PC.ID = LRC.JUDGE_CARD_ID
+			INNER JOIN CARD AS RC ON RC.ID = LRC.PLAYER_CARD_ID
+		WHERE %s
+			AND FN_USER_HAS_DECK_ACCESS(?, PC.DECK_ID, 'READ')
+			AND FN_USER_HAS_DECK_ACCESS(?, RC.DECK_ID, 'READ')
+		GROUP BY PC.ID,
+			RC.ID
+		HAVING WIN_COUNT >= ?
+		ORDER BY WIN_COUNT DESC,
+			PROMPT_TEXT ASC,
+			RESPONSE_TEXT ASC
+		LIMIT ?
+	`, where)
+	params = append(params, userId, userId, minWinCount, limit)
+	rows, err := query(sqlString, params...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]StatCombo, 0)
+	for rows.Next() {
+		var combo StatCombo
+		if err := rows.Scan(
+			&combo.PromptCardId,
+			&combo.PromptText,
+			&combo.ResponseCardId,
+			&combo.ResponseText,
+			&combo.WinCount,
+		); err != nil {
+			log.Println(err)
+			return nil, errors.New("failed to scan row in query results")
+		}
+		result = append(result, combo)
+	}
+	return result, nil
+}
+
+// GetStatsCombo counts every play of the combination, but only lists the most
+// recent ones the user could have seen, the same as the round history.
+func GetStatsCombo(userId uuid.UUID, promptCardId uuid.UUID, responseCardId uuid.UUID, limit int) (StatComboDetail, error) {
+	result := StatComboDetail{
+		StatCombo: StatCombo{
+			PromptCardId:   promptCardId,
+			ResponseCardId: responseCardId,
+		},
+		Occurrences: make([]StatComboOccurrence, 0),
+	}
+
+	sqlString := `
+		SELECT
+			PC.TEXT,
+			RC.TEXT
+		FROM CARD AS PC
+			INNER JOIN CARD AS RC ON RC.ID = ?
+		WHERE PC.ID = ?
+	`
+	rows, err := query(sqlString, responseCardId, promptCardId)
+	if err != nil {
+		return result, err
+	}
+	defer rows.Close()
+
+	found := false
+	for rows.Next() {
+		found = true
+		if err := rows.Scan(&result.PromptText, &result.ResponseText); err != nil {
+			log.Println(err)
+			return result, errors.New("failed to scan row in query results")
+		}
+	}
+
+	if !found {
+		return result, errors.New("failed to find cards")
+	}
+
+	sqlString = `
+		SELECT
+			COUNT(LRC.ID) AS PLAY_COUNT,
+			COUNT(LW.ID) AS WIN_COUNT
+		FROM LOG_RESPONSE_CARD AS LRC
+			LEFT JOIN LOG_WIN AS LW ON LW.RESPONSE_ID = LRC.RESPONSE_ID
+		WHERE LRC.JUDGE_CARD_ID = ?
+			AND LRC.PLAYER_CARD_ID = ?
+	`
+	rows, err = query(sqlString, promptCardId, responseCardId)
+	if err != nil {
+		return result, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err := rows.Scan(&result.PlayCount, &result.WinCount); err != nil {
+			log.Println(err)
+			return result, errors.New("failed to scan row in query results")
+		}
+	}
+
+	// rows logged before the password was kept fall back to the lobby, and
+	// are hidden once it is gone
+	// lobbies are deleted once empty, so names come from the lobby log
+	sqlString = `
+		SELECT
+			LRC.CREATED_ON_DATE,
+			LRC.LOBBY_ID,
+			COALESCE(LL.NAME, '') AS LOBBY_NAME,
+			COALESCE(JU.NAME, 'Unknown') AS JUDGE_USER_NAME,
+			COALESCE(PU.NAME, 'Unknown') AS PLAYER_USER_NAME,
+			LW.ID IS NOT NULL AS WON
+		FROM LOG_RESPONSE_CARD AS LRC
+			LEFT JOIN LOG_WIN AS LW ON LW.RESPONSE_ID = LRC.RESPONSE_ID
+			LEFT JOIN V_LOBBY_NAME AS LL ON LL.ID = LRC.LOBBY_ID
+			LEFT JOIN USER AS JU ON JU.ID = LRC.JUDGE_USER_ID
+			LEFT JOIN USER AS PU ON PU.ID = LRC.PLAYER_USER_ID
+		WHERE LRC.JUDGE_CARD_ID = ?
+			AND LRC.PLAYER_CARD_ID = ?
+			AND (
+				LRC.LOBBY_HAS_PASSWORD = FALSE
+				OR (
+					LRC.LOBBY_HAS_PASSWORD IS NULL
+					AND LRC.LOBBY_ID IN (SELECT ID FROM LOBBY WHERE PASSWORD_HASH IS NULL)
+				)
+				OR LRC.JUDGE_USER_ID = ?
+				OR LRC.ROUND_ID IN (
+					SELECT
+						ROUND_ID
+					FROM LOG_RESPONSE_CARD
+					WHERE PLAYER_USER_ID = ?
+				)
+			)
+		ORDER BY LRC.CREATED_ON_DATE DESC
+		LIMIT ?
+	`
+	rows, err = query(sqlString, promptCardId, responseCardId, userId, userId, limit)
+	if err != nil {
+		return result, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var occurrence StatComboOccurrence
+		if err := rows.Scan(
+			&occurrence.CreatedOnDate,
+			&occurrence.LobbyId,
+			&occurrence.LobbyName,
+			&occurrence.JudgeUserName,
+			&occurrence.PlayerUserName,
+			&occurrence.Won,
+		); err != nil {
+			log.Println(err)
+			return result, errors.New("failed to scan row in query results")
+		}
+		result.Occurrences = append(result.Occurrences, occurrence)
+	}
+
+	return result, nil
+}
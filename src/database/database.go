@@ -90,3 +90,54 @@ func execute(sqlString string, params ...any) error {
 
 	return nil
 }
+
+// transaction runs the function in a database transaction, which is committed
+// only if the function returns no error.
+func transaction(run func(tx *sql.Tx) error) error {
+	tx, err := database.Begin()
+	if err != nil {
+		log.Println(err)
+		return errors.New("failed to begin database transaction")
+	}
+
+	err = run(tx)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		log.Println(err)
+		return errors.New("failed to commit database transaction")
+	}
+
+	return nil
+}
+
+func queryTx(tx *sql.Tx, sqlString string, params ...any) (*sql.Rows, error) {
+	rows, err := tx.Query(sqlString, params...)
+	if err != nil {
+		log.Println(err)
+		return nil, errors.New("failed to query statement in database")
+	}
+
+	return rows, nil
+}
+
+// executeTx returns the number of rows the statement changed.
+func executeTx(tx *sql.Tx, sqlString string, params ...any) (int64, error) {
+	result, err := tx.Exec(sqlString, params...)
+	if err != nil {
+		log.Println(err)
+		return 0, errors.New("failed to execute statement in database")
+	}
+
+	rowCount, err := result.RowsAffected()
+	if err != nil {
+		log.Println(err)
+		return 0, errors.New("failed to count changed rows")
+	}
+
+	return rowCount, nil
+}
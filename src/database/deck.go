@@ -1,8 +1,10 @@
 package database
 
 import (
+	"database/sql"
 	"errors"
 	"log"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -17,6 +19,19 @@ type Deck struct {
 	Name             string
 	PasswordHash     string
 	IsPublicReadOnly bool
+	PasswordRole     string
+
+	SourceDeckId uuid.NullUUID
+	ForkedOnDate sql.NullTime
+
+	ContentRating sql.NullString
+}
+
+type UpstreamCardChange struct {
+	Card
+	ChangeType string
+	ForkCardId uuid.NullUUID
+	ForkText   sql.NullString
 }
 
 type DeckDetails struct {
@@ -127,7 +142,11 @@ func GetDeck(id uuid.UUID) (Deck, error) {
 			CHANGED_ON_DATE,
 			NAME,
 			PASSWORD_HASH,
-			IS_PUBLIC_READONLY
+			IS_PUBLIC_READONLY,
+			PASSWORD_ROLE,
+			SOURCE_DECK_ID,
+			FORKED_ON_DATE,
+			CONTENT_RATING
 		FROM DECK
 		WHERE ID = ?
 	`
@@ -144,7 +163,11 @@ func GetDeck(id uuid.UUID) (Deck, error) {
 			&deck.ChangedOnDate,
 			&deck.Name,
 			&deck.PasswordHash,
-			&deck.IsPublicReadOnly); err != nil {
+			&deck.IsPublicReadOnly,
+			&deck.PasswordRole,
+			&deck.SourceDeckId,
+			&deck.ForkedOnDate,
+			&deck.ContentRating); err != nil {
 			log.Println(err)
 			return deck, errors.New("failed to scan row in query results")
 		}
@@ -198,6 +221,232 @@ func CreateDeck(name string, password string, isPublicReadOnly bool) (uuid.UUID,
 	return id, execute(sqlString, id, name, passwordHash, isPublicReadOnly)
 }
 
+// CloneDeck creates a new deck with a copy of every card in the source deck,
+// remembering the source so upstream changes can be found later. The user
+// cloning it owns the new deck.
+func CloneDeck(userId uuid.UUID, sourceDeckId uuid.UUID, name string, password string, isPublicReadOnly bool) (uuid.UUID, error) {
+	id, err := uuid.NewUUID()
+	if err != nil {
+		log.Println(err)
+		return id, errors.New("failed to generate new id")
+	}
+
+	passwordHash, err := auth.GetPasswordHash(password)
+	if err != nil {
+		log.Println(err)
+		return id, errors.New("failed to hash password")
+	}
+
+	sqlString := `
+		INSERT INTO DECK(ID, NAME, PASSWORD_HASH, IS_PUBLIC_READONLY, SOURCE_DECK_ID, FORKED_ON_DATE, CONTENT_RATING)
+		SELECT
+			?,
+			?,
+			?,
+			?,
+			ID,
+			CURRENT_TIMESTAMP(6),
+			CONTENT_RATING
+		FROM DECK
+		WHERE ID = ?
+	`
+	err = transaction(func(tx *sql.Tx) error {
+		_, err := executeTx(tx, sqlString, id, name, passwordHash, isPublicReadOnly, sourceDeckId)
+		if err != nil {
+			return err
+		}
+
+		sqlString := `
+			INSERT INTO USER_ACCESS_DECK(USER_ID, DECK_ID, ROLE)
+			VALUES (?, ?, 'OWNER')
+		`
+		_, err = executeTx(tx, sqlString, userId, id)
+		if err != nil {
+			return err
+		}
+
+		sqlString = `
+			INSERT INTO CARD(DECK_ID, CATEGORY, TEXT, YOUTUBE, IMAGE, SOURCE_CARD_ID, CONTENT_RATING)
+			SELECT
+				?,
+				CATEGORY,
+				TEXT,
+				YOUTUBE,
+				IMAGE,
+				ID,
+				CONTENT_RATING
+			FROM CARD
+			WHERE DECK_ID = ?
+		`
+		_, err = executeTx(tx, sqlString, id, sourceDeckId)
+		if err != nil {
+			return err
+		}
+
+		return copyCardTags(tx, sourceDeckId, id)
+	})
+	return id, err
+}
+
+// GetDeckMergeConflicts finds the source deck cards whose text already exists
+// in the target deck and so cannot be merged into it.
+func GetDeckMergeConflicts(sourceDeckId uuid.UUID, targetDeckId uuid.UUID) ([]Card, error) {
+	sqlString := `
+		SELECT
+			S.ID,
+			S.CATEGORY,
+			S.TEXT
+		FROM CARD AS S
+			INNER JOIN CARD AS T ON T.DECK_ID = ? AND T.TEXT = S.TEXT
+		WHERE S.DECK_ID = ?
+		ORDER BY S.CATEGORY, S.TEXT
+	`
+	rows, err := query(sqlString, targetDeckId, sourceDeckId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]Card, 0)
+	for rows.Next() {
+		var card Card
+		if err := rows.Scan(
+			&card.Id,
+			&card.Category,
+			&card.Text,
+		); err != nil {
+			log.Println(err)
+			return nil, errors.New("failed to scan row in query results")
+		}
+		result = append(result, card)
+	}
+	return result, nil
+}
+
+// MergeDeck moves or copies every source deck card that does not conflict
+// with a card in the target deck.
+func MergeDeck(sourceDeckId uuid.UUID, targetDeckId uuid.UUID, move bool) error {
+	sqlString := `
+		INSERT INTO CARD(DECK_ID, CATEGORY, TEXT, YOUTUBE, IMAGE, SOURCE_CARD_ID, CONTENT_RATING)
+		SELECT
+			?,
+			S.CATEGORY,
+			S.TEXT,
+			S.YOUTUBE,
+			S.IMAGE,
+			S.ID,
+			S.CONTENT_RATING
+		FROM CARD AS S
+			LEFT JOIN CARD AS T ON T.DECK_ID = ? AND T.TEXT = S.TEXT
+		WHERE S.DECK_ID = ?
+			AND T.ID IS NULL
+	`
+	if move {
+		sqlString = `
+			UPDATE CARD AS S
+				LEFT JOIN CARD AS T ON T.DECK_ID = ? AND T.TEXT = S.TEXT
+			SET S.DECK_ID = ?
+			WHERE S.DECK_ID = ?
+				AND T.ID IS NULL
+		`
+	}
+	return transaction(func(tx *sql.Tx) error {
+		_, err := executeTx(tx, sqlString, targetDeckId, targetDeckId, sourceDeckId)
+		if err != nil {
+			return err
+		}
+
+		if move {
+			return nil
+		}
+
+		return copyCardTags(tx, sourceDeckId, targetDeckId)
+	})
+}
+
+// GetDeckUpstreamChanges finds the cards added or edited in the deck's source
+// since it was forked.
+func GetDeckUpstreamChanges(deckId uuid.UUID) ([]UpstreamCardChange, error) {
+	sqlString := `
+		SELECT
+			S.ID,
+			S.CREATED_ON_DATE,
+			S.CHANGED_ON_DATE,
+			S.DECK_ID,
+			S.CATEGORY,
+			S.TEXT,
+			S.YOUTUBE,
+			IF(F.ID IS NULL, 'ADDED', 'EDITED') AS CHANGE_TYPE,
+			F.ID AS FORK_CARD_ID,
+			F.TEXT AS FORK_TEXT
+		FROM DECK AS D
+			INNER JOIN CARD AS S ON S.DECK_ID = D.SOURCE_DECK_ID
+			LEFT JOIN CARD AS F ON F.DECK_ID = D.ID AND F.SOURCE_CARD_ID = S.ID
+		WHERE D.ID = ?
+			AND (
+				(
+					F.ID IS NULL
+					AND S.CREATED_ON_DATE > D.FORKED_ON_DATE
+					AND NOT EXISTS (
+						SELECT
+							ID
+						FROM CARD
+						WHERE DECK_ID = D.ID
+							AND TEXT = S.TEXT
+					)
+				)
+				OR (
+					F.ID IS NOT NULL
+					AND S.CHANGED_ON_DATE > D.FORKED_ON_DATE
+					AND (
+						F.TEXT <> S.TEXT
+						OR NOT F.YOUTUBE <=> S.YOUTUBE
+						OR NOT F.IMAGE <=> S.IMAGE
+					)
+				)
+			)
+		ORDER BY S.CHANGED_ON_DATE DESC
+		LIMIT 100
+	`
+	rows, err := query(sqlString, deckId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]UpstreamCardChange, 0)
+	for rows.Next() {
+		var upstreamCardChange UpstreamCardChange
+		if err := rows.Scan(
+			&upstreamCardChange.Id,
+			&upstreamCardChange.CreatedOnDate,
+			&upstreamCardChange.ChangedOnDate,
+			&upstreamCardChange.DeckId,
+			&upstreamCardChange.Category,
+			&upstreamCardChange.Text,
+			&upstreamCardChange.YouTube,
+			&upstreamCardChange.ChangeType,
+			&upstreamCardChange.ForkCardId,
+			&upstreamCardChange.ForkText,
+		); err != nil {
+			log.Println(err)
+			return nil, errors.New("failed to scan row in query results")
+		}
+		result = append(result, upstreamCardChange)
+	}
+	return result, nil
+}
+
+// TextDiff compares the fork's text to the source's current text, word by
+// word.
+func (upstreamCardChange UpstreamCardChange) TextDiff() []DiffSegment {
+	if !upstreamCardChange.ForkText.Valid {
+		return []DiffSegment{{Text: upstreamCardChange.Text, Added: true}}
+	}
+
+	return diffWords(strings.Fields(upstreamCardChange.ForkText.String), strings.Fields(upstreamCardChange.Text))
+}
+
 func GetDeckId(name string) (uuid.UUID, error) {
 	var id uuid.UUID
 
@@ -241,6 +490,15 @@ func SetIsPublicReadOnly(id uuid.UUID, isPublicReadOnly bool) error {
 	return execute(sqlString, isPublicReadOnly, id)
 }
 
+func SetDeckPasswordRole(id uuid.UUID, passwordRole string) error {
+	sqlString := `
+		UPDATE DECK
+		SET PASSWORD_ROLE = ?
+		WHERE ID = ?
+	`
+	return execute(sqlString, passwordRole, id)
+}
+
 func SetDeckPassword(id uuid.UUID, password string) error {
 	passwordHash, err := auth.GetPasswordHash(password)
 	if err != nil {
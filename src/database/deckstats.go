@@ -0,0 +1,384 @@
+package database
+
+import (
+	"errors"
+	"log"
+
+	"github.com/google/uuid"
+)
+
+// badCardCleanCount matches the EVT_CLEAN_BAD_* events, which move a card to
+// review once it has been skipped or discarded more than this many times
+// since it was last played.
+const badCardCleanCount = 10
+
+type StatDeck struct {
+	DeckId   uuid.UUID
+	DeckName string
+
+	PromptCardCount   int
+	ResponseCardCount int
+	UnplayedCardCount int
+
+	// prompts count once per round, responses once per card played
+	PromptPlayCount   int
+	ResponsePlayCount int
+	WinCount          int
+	DiscardCount      int
+	SkipCount         int
+	LobbyCount        int
+
+	// the limits a card is moved to review at
+	BadCardCleanCount int
+	ReportThreshold   int
+}
+
+type StatDeckCard struct {
+	CardId       uuid.UUID
+	Category     string
+	Text         string
+	PlayCount    int
+	WinCount     int
+	DiscardCount int
+	SkipCount    int
+}
+
+// StatDeckReviewCard is a card getting close to being moved to review, either
+// by the clean up events or by player reports.
+type StatDeckReviewCard struct {
+	CardId      uuid.UUID
+	Category    string
+	Text        string
+	UnusedCount int
+	ReportCount int
+}
+
+type StatDeckLobbyMonth struct {
+	Month      string
+	LobbyCount int
+	Percent    int
+}
+
+func (statDeck StatDeck) WinPercent() int {
+	return getPercent(statDeck.WinCount, statDeck.ResponsePlayCount)
+}
+
+func (statDeckCard StatDeckCard) WinPercent() int {
+	return getPercent(statDeckCard.WinCount, statDeckCard.PlayCount)
+}
+
+// GetStatsDeck totals the deck's cards from the daily stat rollups.
+func GetStatsDeck(deckId uuid.UUID) (StatDeck, error) {
+	result := StatDeck{
+		DeckId:            deckId,
+		BadCardCleanCount: badCardCleanCount,
+	}
+
+	sqlString := `
+		SELECT
+			D.NAME,
+			(SELECT COUNT(*) FROM CARD WHERE DECK_ID = D.ID AND CATEGORY = 'PROMPT') AS PROMPT_CARD_COUNT,
+			(SELECT COUNT(*) FROM CARD WHERE DECK_ID = D.ID AND CATEGORY = 'RESPONSE') AS RESPONSE_CARD_COUNT,
+			(
+				SELECT
+					COUNT(*)
+				FROM CARD AS C
+				WHERE C.DECK_ID = D.ID
+					AND NOT EXISTS (
+						SELECT
+							1
+						FROM V_STAT_CARD_DAY AS SCD
+						WHERE SCD.CARD_ID = C.ID
+							AND SCD.RESPONSE_PLAY_COUNT + SCD.PROMPT_PLAY_COUNT > 0
+					)
+			) AS UNPLAYED_CARD_COUNT,
+			COALESCE(SUM(SCD.PROMPT_PLAY_COUNT), 0) AS PROMPT_PLAY_COUNT,
+			COALESCE(SUM(SCD.RESPONSE_PLAY_COUNT), 0) AS RESPONSE_PLAY_COUNT,
+			COALESCE(SUM(SCD.ROUND_WIN_COUNT), 0) AS WIN_COUNT,
+			COALESCE(SUM(SCD.DISCARD_COUNT), 0) AS DISCARD_COUNT,
+			COALESCE(SUM(SCD.SKIP_COUNT), 0) AS SKIP_COUNT,
+			COUNT(DISTINCT CASE WHEN SCD.RESPONSE_PLAY_COUNT + SCD.PROMPT_PLAY_COUNT > 0 THEN SCD.LOBBY_ID END) AS LOBBY_COUNT,
+			COALESCE((SELECT VALUE FROM SETTING WHERE NAME = 'CARD_REPORT_THRESHOLD'), 3) AS REPORT_THRESHOLD
+		FROM DECK AS D
+			LEFT JOIN CARD AS C ON C.DECK_ID = D.ID
+			LEFT JOIN V_STAT_CARD_DAY AS SCD ON SCD.CARD_ID = C.ID
+		WHERE D.ID = ?
+		GROUP BY D.ID
+	`
+	rows, err := query(sqlString, deckId)
+	if err != nil {
+		return result, err
+	}
+	defer rows.Close()
+
+	found := false
+	for rows.Next() {
+		found = true
+		if err := rows.Scan(
+			&result.DeckName,
+			&result.PromptCardCount,
+			&result.ResponseCardCount,
+			&result.UnplayedCardCount,
+			&result.PromptPlayCount,
+			&result.ResponsePlayCount,
+			&result.WinCount,
+			&result.DiscardCount,
+			&result.SkipCount,
+			&result.LobbyCount,
+			&result.ReportThreshold,
+		); err != nil {
+			log.Println(err)
+			return result, errors.New("failed to scan row in query results")
+		}
+	}
+
+	if !found {
+		return result, errors.New("failed to find deck")
+	}
+
+	return result, nil
+}
+
+func CountStatsDeckPlayedResponseCards(deckId uuid.UUID) (int, error) {
+	sqlString := `
+		SELECT
+			COUNT(DISTINCT C.ID)
+		FROM CARD AS C
+			INNER JOIN V_STAT_CARD_DAY AS SCD ON SCD.CARD_ID = C.ID
+		WHERE C.DECK_ID = ?
+			AND C.CATEGORY = 'RESPONSE'
+			AND SCD.RESPONSE_PLAY_COUNT > 0
+	`
+	rows, err := query(sqlString, deckId)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	var count int
+	for rows.Next() {
+		if err := rows.Scan(&count); err != nil {
+			log.Println(err)
+			return 0, errors.New("failed to scan row in query results")
+		}
+	}
+
+	return count, nil
+}
+
+// GetStatsDeckPlayedResponseCards pages through the deck's played response
+// cards, best win rate first.
+func GetStatsDeckPlayedResponseCards(deckId uuid.UUID, page int) ([]StatDeckCard, error) {
+	if page < 1 {
+		page = 1
+	}
+
+	sqlString := `
+		SELECT
+			C.ID,
+			C.CATEGORY,
+			C.TEXT,
+			SUM(SCD.RESPONSE_PLAY_COUNT) AS PLAY_COUNT,
+			SUM(SCD.ROUND_WIN_COUNT) AS WIN_COUNT,
+			SUM(SCD.DISCARD_COUNT) AS DISCARD_COUNT,
+			SUM(SCD.SKIP_COUNT) AS SKIP_COUNT
+		FROM CARD AS C
+			INNER JOIN V_STAT_CARD_DAY AS SCD ON SCD.CARD_ID = C.ID
+		WHERE C.DECK_ID = ?
+			AND C.CATEGORY = 'RESPONSE'
+		GROUP BY C.ID
+		HAVING PLAY_COUNT > 0
+		ORDER BY SUM(SCD.ROUND_WIN_COUNT) / SUM(SCD.RESPONSE_PLAY_COUNT) DESC,
+			PLAY_COUNT DESC,
+			C.TEXT ASC
+		LIMIT 10 OFFSET ?
+	`
+	return getStatsDeckCards(sqlString, deckId, (page-1)*10)
+}
+
+// GetStatsDeckUnplayedCards finds the deck's cards that have never been
+// played, with the cards discarded or skipped most often first.
+func GetStatsDeckUnplayedCards(deckId uuid.UUID, limit int) ([]StatDeckCard, error) {
+	sqlString := `
+		SELECT
+			C.ID,
+			C.CATEGORY,
+			C.TEXT,
+			COALESCE(SUM(SCD.RESPONSE_PLAY_COUNT + SCD.PROMPT_PLAY_COUNT), 0) AS PLAY_COUNT,
+			COALESCE(SUM(SCD.ROUND_WIN_COUNT), 0) AS WIN_COUNT,
+			COALESCE(SUM(SCD.DISCARD_COUNT), 0) AS DISCARD_COUNT,
+			COALESCE(SUM(SCD.SKIP_COUNT), 0) AS SKIP_COUNT
+		FROM CARD AS C
+			LEFT JOIN V_STAT_CARD_DAY AS SCD ON SCD.CARD_ID = C.ID
+		WHERE C.DECK_ID = ?
+		GROUP BY C.ID
+		HAVING PLAY_COUNT = 0
+		ORDER BY COALESCE(SUM(SCD.DISCARD_COUNT + SCD.SKIP_COUNT), 0) DESC,
+			C.CATEGORY ASC,
+			C.TEXT ASC
+		LIMIT ?
+	`
+	return getStatsDeckCards(sqlString, deckId, limit)
+}
+
+func getStatsDeckCards(sqlString string, params ...any) ([]StatDeckCard, error) {
+	rows, err := query(sqlString, params...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]StatDeckCard, 0)
+	for rows.Next() {
+		var card StatDeckCard
+		if err := rows.Scan(
+			&card.CardId,
+			&card.Category,
+			&card.Text,
+			&card.PlayCount,
+			&card.WinCount,
+			&card.DiscardCount,
+			&card.SkipCount,
+		); err != nil {
+			log.Println(err)
+			return nil, errors.New("failed to scan row in query results")
+		}
+		result = append(result, card)
+	}
+	return result, nil
+}
+
+// GetStatsDeckReviewCards finds the deck's cards that are at least half way to
+// being moved to review. Skips and discards count the same way as in the
+// EVT_CLEAN_BAD_* events, only since the card was last played.
+func GetStatsDeckReviewCards(deckId uuid.UUID) ([]StatDeckReviewCard, error) {
+	sqlString := `
+		WITH LAST_PLAYED AS (
+				SELECT
+					CARD_ID,
+					MAX(CREATED_ON_DATE) AS LAST_PLAYED_DATE
+				FROM (
+						SELECT
+							JUDGE_CARD_ID AS CARD_ID,
+							CREATED_ON_DATE
+						FROM LOG_RESPONSE_CARD
+						WHERE JUDGE_CARD_ID IN (SELECT ID FROM CARD WHERE DECK_ID = ?)
+						UNION ALL
+						SELECT
+							PLAYER_CARD_ID AS CARD_ID,
+							CREATED_ON_DATE
+						FROM LOG_RESPONSE_CARD
+						WHERE PLAYER_CARD_ID IN (SELECT ID FROM CARD WHERE DECK_ID = ?)
+					) AS PLAYS
+				GROUP BY CARD_ID
+			),
+			UNUSED AS (
+				SELECT
+					U.CARD_ID,
+					COUNT(*) AS UNUSED_COUNT
+				FROM (
+						SELECT
+							CARD_ID,
+							CREATED_ON_DATE
+						FROM LOG_SKIP
+						WHERE CARD_ID IN (SELECT ID FROM CARD WHERE DECK_ID = ?)
+						UNION ALL
+						SELECT
+							CARD_ID,
+							CREATED_ON_DATE
+						FROM LOG_DISCARD
+						WHERE CARD_ID IN (SELECT ID FROM CARD WHERE DECK_ID = ?)
+					) AS U
+					LEFT JOIN LAST_PLAYED AS LP ON LP.CARD_ID = U.CARD_ID
+				WHERE LP.LAST_PLAYED_DATE IS NULL
+					OR LP.LAST_PLAYED_DATE < U.CREATED_ON_DATE
+				GROUP BY U.CARD_ID
+			),
+			REPORTS AS (
+				SELECT
+					CARD_ID,
+					COUNT(DISTINCT USER_ID) AS REPORT_COUNT
+				FROM CARD_REPORT
+				GROUP BY CARD_ID
+			)
+		SELECT
+			C.ID,
+			C.CATEGORY,
+			C.TEXT,
+			COALESCE(UN.UNUSED_COUNT, 0) AS UNUSED_COUNT,
+			COALESCE(R.REPORT_COUNT, 0) AS REPORT_COUNT
+		FROM CARD AS C
+			LEFT JOIN UNUSED AS UN ON UN.CARD_ID = C.ID
+			LEFT JOIN REPORTS AS R ON R.CARD_ID = C.ID
+		WHERE C.DECK_ID = ?
+			AND (
+				COALESCE(UN.UNUSED_COUNT, 0) * 2 >= ?
+				OR COALESCE(R.REPORT_COUNT, 0) > 0
+			)
+		ORDER BY UNUSED_COUNT DESC,
+			REPORT_COUNT DESC,
+			C.TEXT ASC
+	`
+	rows, err := query(sqlString, deckId, deckId, deckId, deckId, deckId, badCardCleanCount)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]StatDeckReviewCard, 0)
+	for rows.Next() {
+		var card StatDeckReviewCard
+		if err := rows.Scan(
+			&card.CardId,
+			&card.Category,
+			&card.Text,
+			&card.UnusedCount,
+			&card.ReportCount,
+		); err != nil {
+			log.Println(err)
+			return nil, errors.New("failed to scan row in query results")
+		}
+		result = append(result, card)
+	}
+	return result, nil
+}
+
+// GetStatsDeckLobbyMonths counts the lobbies that played a card from the deck
+// in each of the last twelve months.
+func GetStatsDeckLobbyMonths(deckId uuid.UUID) ([]StatDeckLobbyMonth, error) {
+	sqlString := `
+		SELECT
+			DATE_FORMAT(SCD.DAY, '%Y-%m') AS MONTH,
+			COUNT(DISTINCT SCD.LOBBY_ID) AS LOBBY_COUNT
+		FROM V_STAT_CARD_DAY AS SCD
+			INNER JOIN CARD AS C ON C.ID = SCD.CARD_ID
+		WHERE C.DECK_ID = ?
+			AND SCD.RESPONSE_PLAY_COUNT + SCD.PROMPT_PLAY_COUNT > 0
+			AND SCD.DAY >= DATE_FORMAT(DATE_SUB(CURRENT_DATE(), INTERVAL 11 MONTH), '%Y-%m-01')
+		GROUP BY MONTH
+		ORDER BY MONTH ASC
+	`
+	rows, err := query(sqlString, deckId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]StatDeckLobbyMonth, 0)
+	maxLobbyCount := 0
+	for rows.Next() {
+		var lobbyMonth StatDeckLobbyMonth
+		if err := rows.Scan(&lobbyMonth.Month, &lobbyMonth.LobbyCount); err != nil {
+			log.Println(err)
+			return nil, errors.New("failed to scan row in query results")
+		}
+		maxLobbyCount = max(maxLobbyCount, lobbyMonth.LobbyCount)
+		result = append(result, lobbyMonth)
+	}
+
+	for i := range result {
+		result[i].Percent = getPercent(result[i].LobbyCount, maxLobbyCount)
+	}
+
+	return result, nil
+}
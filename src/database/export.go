@@ -0,0 +1,204 @@
+package database
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserDataExport is everything recorded about one user's games, for them to
+// keep outside of the site.
+type UserDataExport struct {
+	UserId        uuid.UUID       `json:"user_id"`
+	UserName      string          `json:"user_name"`
+	CreatedOnDate time.Time       `json:"created_on_date"`
+	Stats         StatUser        `json:"stats"`
+	Achievements  []Achievement   `json:"achievements"`
+	Rating        UserRating      `json:"rating"`
+	Events        []UserDataEvent `json:"events"`
+}
+
+// UserDataEvent is one logged action, with the columns that do not apply to
+// the event type left empty.
+type UserDataEvent struct {
+	CreatedOnDate time.Time     `json:"created_on_date"`
+	EventType     string        `json:"event_type"`
+	LobbyId       uuid.UUID     `json:"lobby_id"`
+	LobbyName     string        `json:"lobby_name"`
+	RoundId       uuid.NullUUID `json:"round_id"`
+	CardText      string        `json:"card_text"`
+	Category      string        `json:"category"`
+	Amount        int           `json:"amount"`
+}
+
+func GetUserDataExport(userId uuid.UUID) (UserDataExport, error) {
+	result := UserDataExport{
+		UserId: userId,
+		Events: make([]UserDataEvent, 0),
+	}
+
+	user, err := GetUser(userId)
+	if err != nil {
+		return result, err
+	}
+
+	if user.Id == uuid.Nil {
+		return result, errors.New("failed to find user")
+	}
+
+	result.UserName = user.Name
+	result.CreatedOnDate = user.CreatedOnDate
+
+	result.Stats, err = GetStatsUser(userId)
+	if err != nil {
+		return result, err
+	}
+
+	result.Achievements, err = GetAchievementsUser(userId)
+	if err != nil {
+		return result, err
+	}
+
+	result.Rating, err = GetUserRating(userId)
+	if err != nil {
+		return result, err
+	}
+
+	// lobbies are deleted once empty, so names come from the lobby log
+	sqlString := `
+		SELECT
+			E.CREATED_ON_DATE,
+			E.EVENT_TYPE,
+			E.LOBBY_ID,
+			COALESCE(LL.NAME, '') AS LOBBY_NAME,
+			E.ROUND_ID,
+			COALESCE(E.CARD_TEXT, '') AS CARD_TEXT,
+			COALESCE(E.CATEGORY, '') AS CATEGORY,
+			E.AMOUNT
+		FROM (
+				SELECT
+					LRC.CREATED_ON_DATE,
+					'PLAYED-CARD' AS EVENT_TYPE,
+					LRC.LOBBY_ID,
+					LRC.ROUND_ID,
+					COALESCE(C.TEXT, LRC.SPECIAL_CATEGORY) AS CARD_TEXT,
+					LRC.SPECIAL_CATEGORY AS CATEGORY,
+					0 AS AMOUNT
+				FROM LOG_RESPONSE_CARD AS LRC
+					LEFT JOIN CARD AS C ON C.ID = LRC.PLAYER_CARD_ID
+				WHERE LRC.PLAYER_USER_ID = ?
+				UNION ALL
+				SELECT DISTINCT
+					RW.TIMESTAMP,
+					'WON-ROUND',
+					RW.LOBBY_ID,
+					RW.ROUND_ID,
+					NULL,
+					NULL,
+					0
+				FROM V_ROUND_WINNER AS RW
+				WHERE RW.USER_ID = ?
+				UNION ALL
+				SELECT
+					MIN(LRC.CREATED_ON_DATE),
+					'JUDGED-ROUND',
+					LRC.LOBBY_ID,
+					LRC.ROUND_ID,
+					MIN(C.TEXT),
+					NULL,
+					0
+				FROM LOG_RESPONSE_CARD AS LRC
+					LEFT JOIN CARD AS C ON C.ID = LRC.JUDGE_CARD_ID
+				WHERE LRC.JUDGE_USER_ID = ?
+				GROUP BY LRC.LOBBY_ID,
+					LRC.ROUND_ID
+				UNION ALL
+				SELECT
+					LD.CREATED_ON_DATE,
+					'DISCARDED-CARD',
+					LD.LOBBY_ID,
+					NULL,
+					C.TEXT,
+					NULL,
+					0
+				FROM LOG_DISCARD AS LD
+					LEFT JOIN CARD AS C ON C.ID = LD.CARD_ID
+				WHERE LD.USER_ID = ?
+				UNION ALL
+				SELECT
+					LS.CREATED_ON_DATE,
+					'SKIPPED-PROMPT',
+					LS.LOBBY_ID,
+					NULL,
+					C.TEXT,
+					NULL,
+					0
+				FROM LOG_SKIP AS LS
+					LEFT JOIN CARD AS C ON C.ID = LS.CARD_ID
+				WHERE LS.USER_ID = ?
+				UNION ALL
+				SELECT
+					LCS.CREATED_ON_DATE,
+					'CREDITS',
+					LCS.LOBBY_ID,
+					NULL,
+					NULL,
+					LCS.CATEGORY,
+					LCS.AMOUNT
+				FROM LOG_CREDITS_SPENT AS LCS
+				WHERE LCS.USER_ID = ?
+				UNION ALL
+				SELECT
+					LK.CREATED_ON_DATE,
+					'KICKED',
+					LK.LOBBY_ID,
+					NULL,
+					NULL,
+					NULL,
+					0
+				FROM LOG_KICK AS LK
+				WHERE LK.USER_ID = ?
+				UNION ALL
+				SELECT
+					LFT.CREATED_ON_DATE,
+					'FLIPPED-TABLE',
+					LFT.LOBBY_ID,
+					NULL,
+					NULL,
+					NULL,
+					0
+				FROM LOG_FLIP_TABLE AS LFT
+				WHERE LFT.USER_ID = ?
+			) AS E
+			LEFT JOIN V_LOBBY_NAME AS LL ON LL.ID = E.LOBBY_ID
+		ORDER BY E.CREATED_ON_DATE,
+			E.EVENT_TYPE
+	`
+	rows, err := query(sqlString, userId, userId, userId, userId, userId, userId, userId, userId)
+	if err != nil {
+		return result, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var event UserDataEvent
+		if err := rows.Scan(
+			&event.CreatedOnDate,
+			&event.EventType,
+			&event.LobbyId,
+			&event.LobbyName,
+			&event.RoundId,
+			&event.CardText,
+			&event.Category,
+			&event.Amount,
+		); err != nil {
+			log.Println(err)
+			return result, errors.New("failed to scan row in query results")
+		}
+		result.Events = append(result.Events, event)
+	}
+
+	return result, nil
+}
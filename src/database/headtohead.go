@@ -0,0 +1,328 @@
+package database
+
+import (
+	"errors"
+	"log"
+
+	"github.com/google/uuid"
+)
+
+type StatHeadToHead struct {
+	// lobbies both users played or judged in
+	SharedGameCount int
+
+	// rounds both users responded in
+	SharedRoundCount int
+
+	Player      StatHeadToHeadPlayer
+	OtherPlayer StatHeadToHeadPlayer
+}
+
+type StatHeadToHeadPlayer struct {
+	UserId   uuid.UUID
+	UserName string
+
+	// rounds and games in lobbies shared with the other user
+	RoundPlayCount int
+	RoundWinCount  int
+	GameWinCount   int
+
+	// rounds both users responded in that this user won
+	SharedRoundWinCount int
+
+	// rounds this user judged with the other user responding, and how many of
+	// those the other user won
+	JudgedRoundCount int
+	PickedOtherCount int
+
+	// this user's cards that won most when the other user judged
+	FavouriteCards []StatHeadToHeadCard
+}
+
+type StatHeadToHeadCard struct {
+	CardId   uuid.NullUUID
+	Text     string
+	WinCount int
+}
+
+type StatHeadToHeadOpponent struct {
+	UserId          uuid.UUID
+	UserName        string
+	SharedGameCount int
+
+	// rounds the user judged and picked the opponent, and the other way round
+	PickedCount   int
+	PickedByCount int
+}
+
+// lobbies both users took part in, as a player or as the judge
+const headToHeadSharedLobbiesSql = `
+	WITH LOBBY_USER AS (
+			SELECT
+				LOBBY_ID,
+				PLAYER_USER_ID AS USER_ID
+			FROM LOG_RESPONSE_CARD
+			UNION
+			SELECT
+				LOBBY_ID,
+				JUDGE_USER_ID AS USER_ID
+			FROM LOG_RESPONSE_CARD
+		),
+		SHARED_LOBBY AS (
+			SELECT
+				A.LOBBY_ID
+			FROM LOBBY_USER AS A
+				INNER JOIN LOBBY_USER AS B ON B.LOBBY_ID = A.LOBBY_ID
+			WHERE A.USER_ID = ?
+				AND B.USER_ID = ?
+		)
+`
+
+func (statHeadToHeadPlayer StatHeadToHeadPlayer) RoundWinPercent() int {
+	return getPercent(statHeadToHeadPlayer.RoundWinCount, statHeadToHeadPlayer.RoundPlayCount)
+}
+
+func (statHeadToHeadPlayer StatHeadToHeadPlayer) PickedOtherPercent() int {
+	return getPercent(statHeadToHeadPlayer.PickedOtherCount, statHeadToHeadPlayer.JudgedRoundCount)
+}
+
+func (statHeadToHead StatHeadToHead) PlayerSharedRoundWinPercent() int {
+	return getPercent(statHeadToHead.Player.SharedRoundWinCount, statHeadToHead.SharedRoundCount)
+}
+
+func (statHeadToHead StatHeadToHead) OtherPlayerSharedRoundWinPercent() int {
+	return getPercent(statHeadToHead.OtherPlayer.SharedRoundWinCount, statHeadToHead.SharedRoundCount)
+}
+
+func GetStatsHeadToHead(userId uuid.UUID, otherUserId uuid.UUID) (StatHeadToHead, error) {
+	var result StatHeadToHead
+
+	sqlString := headToHeadSharedLobbiesSql + `
+		SELECT
+			(SELECT COUNT(*) FROM SHARED_LOBBY) AS SHARED_GAME_COUNT,
+			(
+				SELECT
+					COUNT(DISTINCT A.ROUND_ID)
+				FROM LOG_RESPONSE_CARD AS A
+					INNER JOIN LOG_RESPONSE_CARD AS B ON B.ROUND_ID = A.ROUND_ID
+				WHERE A.PLAYER_USER_ID = ?
+					AND B.PLAYER_USER_ID = ?
+			) AS SHARED_ROUND_COUNT
+	`
+	rows, err := query(sqlString, userId, otherUserId, userId, otherUserId)
+	if err != nil {
+		return result, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err := rows.Scan(&result.SharedGameCount, &result.SharedRoundCount); err != nil {
+			log.Println(err)
+			return result, errors.New("failed to scan row in query results")
+		}
+	}
+
+	result.Player, err = getStatsHeadToHeadPlayer(userId, otherUserId)
+	if err != nil {
+		return result, err
+	}
+
+	result.OtherPlayer, err = getStatsHeadToHeadPlayer(otherUserId, userId)
+	if err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+// GetStatsHeadToHeadOpponents finds the users who shared the most games with
+// the user.
+func GetStatsHeadToHeadOpponents(userId uuid.UUID, limit int) ([]StatHeadToHeadOpponent, error) {
+	sqlString := `
+		WITH LOBBY_USER AS (
+				SELECT
+					LOBBY_ID,
+					PLAYER_USER_ID AS USER_ID
+				FROM LOG_RESPONSE_CARD
+				UNION
+				SELECT
+					LOBBY_ID,
+					JUDGE_USER_ID AS USER_ID
+				FROM LOG_RESPONSE_CARD
+			)
+		SELECT
+			U.ID,
+			U.NAME,
+			COUNT(DISTINCT B.LOBBY_ID) AS SHARED_GAME_COUNT,
+			(
+				SELECT
+					COUNT(DISTINCT RW.ROUND_ID)
+				FROM V_ROUND_WINNER AS RW
+					INNER JOIN LOG_RESPONSE_CARD AS LRC ON LRC.ROUND_ID = RW.ROUND_ID
+				WHERE RW.USER_ID = U.ID
+					AND LRC.JUDGE_USER_ID = ?
+			) AS PICKED_COUNT,
+			(
+				SELECT
+					COUNT(DISTINCT RW.ROUND_ID)
+				FROM V_ROUND_WINNER AS RW
+					INNER JOIN LOG_RESPONSE_CARD AS LRC ON LRC.ROUND_ID = RW.ROUND_ID
+				WHERE RW.USER_ID = ?
+					AND LRC.JUDGE_USER_ID = U.ID
+			) AS PICKED_BY_COUNT
+		FROM LOBBY_USER AS A
+			INNER JOIN LOBBY_USER AS B ON B.LOBBY_ID = A.LOBBY_ID
+			INNER JOIN USER AS U ON U.ID = B.USER_ID
+		WHERE A.USER_ID = ?
+			AND B.USER_ID <> A.USER_ID
+		GROUP BY U.ID
+		ORDER BY SHARED_GAME_COUNT DESC,
+			U.NAME ASC
+		LIMIT ?
+	`
+	rows, err := query(sqlString, userId, userId, userId, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]StatHeadToHeadOpponent, 0)
+	for rows.Next() {
+		var opponent StatHeadToHeadOpponent
+		if err := rows.Scan(
+			&opponent.UserId,
+			&opponent.UserName,
+			&opponent.SharedGameCount,
+			&opponent.PickedCount,
+			&opponent.PickedByCount,
+		); err != nil {
+			log.Println(err)
+			return nil, errors.New("failed to scan row in query results")
+		}
+		result = append(result, opponent)
+	}
+	return result, nil
+}
+
+func getStatsHeadToHeadPlayer(userId uuid.UUID, otherUserId uuid.UUID) (StatHeadToHeadPlayer, error) {
+	result := StatHeadToHeadPlayer{
+		UserId:         userId,
+		FavouriteCards: make([]StatHeadToHeadCard, 0),
+	}
+
+	sqlString := headToHeadSharedLobbiesSql + `
+		SELECT
+			U.NAME,
+			(
+				SELECT
+					COUNT(DISTINCT ROUND_ID)
+				FROM LOG_RESPONSE_CARD
+				WHERE PLAYER_USER_ID = U.ID
+					AND LOBBY_ID IN (SELECT LOBBY_ID FROM SHARED_LOBBY)
+			) AS ROUND_PLAY_COUNT,
+			(
+				SELECT
+					COUNT(DISTINCT ROUND_ID)
+				FROM V_ROUND_WINNER
+				WHERE USER_ID = U.ID
+					AND LOBBY_ID IN (SELECT LOBBY_ID FROM SHARED_LOBBY)
+			) AS ROUND_WIN_COUNT,
+			(
+				SELECT
+					COUNT(DISTINCT LOBBY_ID)
+				FROM V_GAME_WINNER
+				WHERE USER_ID = U.ID
+					AND LOBBY_ID IN (SELECT LOBBY_ID FROM SHARED_LOBBY)
+			) AS GAME_WIN_COUNT,
+			(
+				SELECT
+					COUNT(DISTINCT RW.ROUND_ID)
+				FROM V_ROUND_WINNER AS RW
+					INNER JOIN LOG_RESPONSE_CARD AS LRC ON LRC.ROUND_ID = RW.ROUND_ID
+				WHERE RW.USER_ID = U.ID
+					AND LRC.PLAYER_USER_ID = ?
+			) AS SHARED_ROUND_WIN_COUNT,
+			(
+				SELECT
+					COUNT(DISTINCT ROUND_ID)
+				FROM LOG_RESPONSE_CARD
+				WHERE JUDGE_USER_ID = U.ID
+					AND PLAYER_USER_ID = ?
+			) AS JUDGED_ROUND_COUNT,
+			(
+				SELECT
+					COUNT(DISTINCT RW.ROUND_ID)
+				FROM V_ROUND_WINNER AS RW
+					INNER JOIN LOG_RESPONSE_CARD AS LRC ON LRC.ROUND_ID = RW.ROUND_ID
+				WHERE RW.USER_ID = ?
+					AND LRC.JUDGE_USER_ID = U.ID
+			) AS PICKED_OTHER_COUNT
+		FROM USER AS U
+		WHERE U.ID = ?
+	`
+	rows, err := query(sqlString, userId, otherUserId, otherUserId, otherUserId, otherUserId, userId)
+	if err != nil {
+		return result, err
+	}
+	defer rows.Close()
+
+	found := false
+	for rows.Next() {
+		found = true
+		if err := rows.Scan(
+			&result.UserName,
+			&result.RoundPlayCount,
+			&result.RoundWinCount,
+			&result.GameWinCount,
+			&result.SharedRoundWinCount,
+			&result.JudgedRoundCount,
+			&result.PickedOtherCount,
+		); err != nil {
+			log.Println(err)
+			return result, errors.New("failed to scan row in query results")
+		}
+	}
+
+	if !found {
+		return result, errors.New("failed to find user")
+	}
+
+	sqlString = `
+		SELECT
+			C.ID,
+			COALESCE(C.TEXT, LRC.SPECIAL_CATEGORY, 'Unknown') AS TEXT,
+			COUNT(DISTINCT LRC.ROUND_ID) AS WIN_COUNT
+		FROM LOG_RESPONSE_CARD AS LRC
+			INNER JOIN LOG_WIN AS LW ON LW.RESPONSE_ID = LRC.RESPONSE_ID
+			LEFT JOIN CARD AS C ON C.ID = LRC.PLAYER_CARD_ID
+		WHERE LRC.PLAYER_USER_ID = ?
+			AND LRC.JUDGE_USER_ID = ?
+		GROUP BY LRC.PLAYER_CARD_ID
+		ORDER BY WIN_COUNT DESC,
+			TEXT ASC
+		LIMIT 5
+	`
+	rows, err = query(sqlString, userId, otherUserId)
+	if err != nil {
+		return result, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var card StatHeadToHeadCard
+		if err := rows.Scan(&card.CardId, &card.Text, &card.WinCount); err != nil {
+			log.Println(err)
+			return result, errors.New("failed to scan row in query results")
+		}
+		result.FavouriteCards = append(result.FavouriteCards, card)
+	}
+
+	return result, nil
+}
+
+func getPercent(count int, total int) int {
+	if total == 0 {
+		return 0
+	}
+	return count * 100 / total
+}
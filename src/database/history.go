@@ -0,0 +1,332 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// HistoryFilter narrows the rounds shown in the round history. Every value is
+// passed to the database as a parameter.
+type HistoryFilter struct {
+	// lobbies are matched by name, so every lobby ever given the name counts
+	LobbyName string
+
+	// rounds the user played or judged
+	UserName string
+
+	// zero dates are unbounded, ToDate is exclusive
+	FromDate time.Time
+	ToDate   time.Time
+
+	// the user viewing, who also sees the rounds they were in when the lobby
+	// had a password
+	ViewerUserId uuid.UUID
+}
+
+type HistoryRound struct {
+	RoundId       uuid.UUID
+	LobbyId       uuid.UUID
+	LobbyName     string
+	CreatedOnDate time.Time
+	JudgeUserName string
+	PromptText    string
+	Responses     []HistoryResponse
+}
+
+type HistoryResponse struct {
+	PlayerUserName string
+	Cards          []HistoryResponseCard
+	Won            bool
+}
+
+type HistoryResponseCard struct {
+	Text            string
+	SpecialCategory sql.NullString
+}
+
+func (historyRound HistoryRound) WinnerUserName() string {
+	for _, response := range historyRound.Responses {
+		if response.Won {
+			return response.PlayerUserName
+		}
+	}
+	return ""
+}
+
+func (filter HistoryFilter) where(params *[]any) string {
+	conditions := make([]string, 0)
+
+	// rows logged before the password was kept fall back to the lobby, and
+	// are hidden once it is gone
+	conditions = append(conditions, `(
+				LRC.LOBBY_HAS_PASSWORD = FALSE
+				OR (
+					LRC.LOBBY_HAS_PASSWORD IS NULL
+					AND LRC.LOBBY_ID IN (SELECT ID FROM LOBBY WHERE PASSWORD_HASH IS NULL)
+				)
+				OR LRC.JUDGE_USER_ID = ?
+				OR LRC.ROUND_ID IN (
+					SELECT
+						ROUND_ID
+					FROM LOG_RESPONSE_CARD
+					WHERE PLAYER_USER_ID = ?
+				)
+			)`)
+	*params = append(*params, filter.ViewerUserId, filter.ViewerUserId)
+
+	if filter.LobbyName != "" {
+		conditions = append(conditions, "LRC.LOBBY_ID IN (SELECT ID FROM LOG_LOBBY WHERE NAME LIKE ?)")
+		*params = append(*params, "%"+escapeLike(filter.LobbyName)+"%")
+	}
+
+	// the judge is the same on every row of a round, but players are not
+	if filter.UserName != "" {
+		conditions = append(conditions, `(
+				LRC.JUDGE_USER_ID IN (SELECT ID FROM USER WHERE NAME = ?)
+				OR LRC.ROUND_ID IN (
+					SELECT
+						ROUND_ID
+					FROM LOG_RESPONSE_CARD
+					WHERE PLAYER_USER_ID IN (SELECT ID FROM USER WHERE NAME = ?)
+				)
+			)`)
+		*params = append(*params, filter.UserName, filter.UserName)
+	}
+
+	if !filter.FromDate.IsZero() {
+		conditions = append(conditions, "LRC.CREATED_ON_DATE >= ?")
+		*params = append(*params, filter.FromDate)
+	}
+
+	if !filter.ToDate.IsZero() {
+		conditions = append(conditions, "LRC.CREATED_ON_DATE < ?")
+		*params = append(*params, filter.ToDate)
+	}
+
+	return strings.Join(conditions, "\n\t\t\tAND ")
+}
+
+func CountHistoryRounds(filter HistoryFilter) (int, error) {
+	params := make([]any, 0)
+	sqlString := fmt.Sprintf(`
+		SELECT
+			COUNT(DISTINCT LRC.ROUND_ID)
+		FROM LOG_RESPONSE_CARD AS LRC
+		WHERE %s
+	`, filter.where(&params))
+	rows, err := query(sqlString, params...)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	var count int
+	for rows.Next() {
+		if err := rows.Scan(&count); err != nil {
+			log.Println(err)
+			return 0, errors.New("failed to scan row in query results")
+		}
+	}
+
+	return count, nil
+}
+
+// GetHistoryRounds returns one page of rounds, newest first.
+func GetHistoryRounds(filter HistoryFilter, page int) ([]HistoryRound, error) {
+	if page < 1 {
+		page = 1
+	}
+
+	params := make([]any, 0)
+	where := filter.where(&params)
+	params = append(params, (page-1)*10)
+	return getHistoryRounds(where, "LIMIT 10 OFFSET ?", params...)
+}
+
+// GetHistoryRound finds the round only if the user is allowed to see it.
+func GetHistoryRound(roundId uuid.UUID, userId uuid.UUID) (HistoryRound, error) {
+	params := make([]any, 0)
+	where := HistoryFilter{ViewerUserId: userId}.where(&params)
+	params = append(params, roundId)
+	rounds, err := getHistoryRounds(where+"\n\t\t\tAND LRC.ROUND_ID = ?", "", params...)
+	if err != nil {
+		return HistoryRound{}, err
+	}
+
+	if len(rounds) == 0 {
+		return HistoryRound{}, errors.New("failed to find round")
+	}
+
+	return rounds[0], nil
+}
+
+// getHistoryRounds reads the rounds first and then every response in them,
+// so a page is always whole rounds however many cards were played. Lobbies
+// are deleted once empty, so names come from the lobby log. Card text is kept
+// as played, and rows logged before that read the text from before the
+// card's first change since, or from its review once it was cleaned.
+func getHistoryRounds(where string, limit string, params ...any) ([]HistoryRound, error) {
+	sqlString := fmt.Sprintf(`
+		SELECT
+			LRC.ROUND_ID,
+			LRC.LOBBY_ID,
+			COALESCE(LL.NAME, '') AS LOBBY_NAME,
+			MIN(LRC.CREATED_ON_DATE) AS CREATED_ON_DATE,
+			COALESCE(JU.NAME, 'Unknown') AS JUDGE_USER_NAME,
+			COALESCE(
+				LRC.PROMPT_TEXT,
+				(
+					SELECT
+						TEXT
+					FROM AUDIT_CARD
+					WHERE CARD_ID = LRC.JUDGE_CARD_ID
+						AND CREATED_ON_DATE > LRC.CREATED_ON_DATE
+					ORDER BY CREATED_ON_DATE
+					LIMIT 1
+				),
+				JC.TEXT,
+				(
+					SELECT
+						TEXT
+					FROM REVIEW_CARD
+					WHERE CARD_ID = LRC.JUDGE_CARD_ID
+					ORDER BY CREATED_ON_DATE
+					LIMIT 1
+				),
+				'Unknown'
+			) AS PROMPT_TEXT
+		FROM LOG_RESPONSE_CARD AS LRC
+			LEFT JOIN V_LOBBY_NAME AS LL ON LL.ID = LRC.LOBBY_ID
+			LEFT JOIN USER AS JU ON JU.ID = LRC.JUDGE_USER_ID
+			LEFT JOIN CARD AS JC ON JC.ID = LRC.JUDGE_CARD_ID
+		WHERE %s
+		GROUP BY LRC.ROUND_ID
+		ORDER BY MIN(LRC.CREATED_ON_DATE) DESC
+		%s
+	`, where, limit)
+	rows, err := query(sqlString, params...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]HistoryRound, 0)
+	for rows.Next() {
+		round := HistoryRound{Responses: make([]HistoryResponse, 0)}
+		if err := rows.Scan(
+			&round.RoundId,
+			&round.LobbyId,
+			&round.LobbyName,
+			&round.CreatedOnDate,
+			&round.JudgeUserName,
+			&round.PromptText,
+		); err != nil {
+			log.Println(err)
+			return nil, errors.New("failed to scan row in query results")
+		}
+		result = append(result, round)
+	}
+
+	if len(result) == 0 {
+		return result, nil
+	}
+
+	roundIndexes := make(map[uuid.UUID]int)
+	roundIds := make([]any, 0)
+	for i, round := range result {
+		roundIndexes[round.RoundId] = i
+		roundIds = append(roundIds, round.RoundId)
+	}
+
+	// the winning response is listed first
+	sqlString = fmt.Sprintf(`
+		SELECT
+			LRC.ROUND_ID,
+			LRC.RESPONSE_ID,
+			COALESCE(PU.NAME, 'Unknown') AS PLAYER_USER_NAME,
+			COALESCE(
+				LRC.RESPONSE_TEXT,
+				(
+					SELECT
+						TEXT
+					FROM AUDIT_CARD
+					WHERE CARD_ID = LRC.PLAYER_CARD_ID
+						AND CREATED_ON_DATE > LRC.CREATED_ON_DATE
+					ORDER BY CREATED_ON_DATE
+					LIMIT 1
+				),
+				C.TEXT,
+				(
+					SELECT
+						TEXT
+					FROM REVIEW_CARD
+					WHERE CARD_ID = LRC.PLAYER_CARD_ID
+					ORDER BY CREATED_ON_DATE
+					LIMIT 1
+				),
+				LRC.SPECIAL_CATEGORY,
+				'Unknown'
+			) AS TEXT,
+			LRC.SPECIAL_CATEGORY,
+			EXISTS (
+				SELECT
+					ID
+				FROM LOG_WIN
+				WHERE RESPONSE_ID = LRC.RESPONSE_ID
+			) AS WON
+		FROM LOG_RESPONSE_CARD AS LRC
+			LEFT JOIN USER AS PU ON PU.ID = LRC.PLAYER_USER_ID
+			LEFT JOIN CARD AS C ON C.ID = LRC.PLAYER_CARD_ID
+		WHERE LRC.ROUND_ID IN (%s)
+		ORDER BY LRC.ROUND_ID,
+			WON DESC,
+			LRC.RESPONSE_ID,
+			LRC.CREATED_ON_DATE
+	`, strings.Repeat("?, ", len(roundIds)-1)+"?")
+	rows, err = query(sqlString, roundIds...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	lastResponseId := uuid.Nil
+	for rows.Next() {
+		var roundId uuid.UUID
+		var responseId uuid.UUID
+		var playerUserName string
+		var card HistoryResponseCard
+		var won bool
+		if err := rows.Scan(
+			&roundId,
+			&responseId,
+			&playerUserName,
+			&card.Text,
+			&card.SpecialCategory,
+			&won,
+		); err != nil {
+			log.Println(err)
+			return nil, errors.New("failed to scan row in query results")
+		}
+
+		round := &result[roundIndexes[roundId]]
+		if responseId != lastResponseId {
+			round.Responses = append(round.Responses, HistoryResponse{
+				PlayerUserName: playerUserName,
+				Cards:          make([]HistoryResponseCard, 0),
+				Won:            won,
+			})
+			lastResponseId = responseId
+		}
+
+		response := &round.Responses[len(round.Responses)-1]
+		response.Cards = append(response.Cards, card)
+	}
+
+	return result, nil
+}
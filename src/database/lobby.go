@@ -2,7 +2,6 @@ package database
 
 import (
 	"database/sql"
-	"encoding/base64"
 	"errors"
 	"fmt"
 	"log"
@@ -57,7 +56,10 @@ type PlayerHandData struct {
 	PlayerIsJudge          bool
 	PlayerDiscardAdvantage bool
 	PlayerIsReady          bool
+	PlayerNextSlot         int
 	PlayerHand             []Card
+
+	JudgeBlankCount int
 }
 
 type PlayerSpecialsData struct {
@@ -73,7 +75,11 @@ type PlayerSpecialsData struct {
 
 	Opponents []opponentData
 
+	Rules            LobbyRules
+	LobbyFilterWords []string
+
 	PlayerId                uuid.UUID
+	PlayerIsLobbyOwner      bool
 	PlayerIsJudge           bool
 	PlayerIsReady           bool
 	PlayerHandicap          int
@@ -111,9 +117,34 @@ type PlayerSpecialsData struct {
 	CannotAffordPerk           bool
 }
 
+type LobbyRules struct {
+	EnabledAlert          bool
+	EnabledGamble         bool
+	EnabledBet            bool
+	EnabledSkipBeingJudge bool
+	EnabledExtraResponse  bool
+	EnabledBlockResponse  bool
+	EnabledSurpriseCard   bool
+	EnabledStealCard      bool
+	EnabledFindCard       bool
+	EnabledWildCard       bool
+	EnabledPerk           bool
+	EnabledFlipTable      bool
+
+	CostSkipBeingJudge int
+	CostExtraResponse  int
+	CostBlockResponse  int
+	CostSurpriseCard   int
+	CostStealCard      int
+	CostFindCard       int
+	CostWildCard       int
+	CostPerk           int
+}
+
 type LobbyGameBoardData struct {
 	LobbyId uuid.UUID
 
+	JudgeCardId        uuid.NullUUID
 	JudgeCardText      sql.NullString
 	JudgeCardYouTube   sql.NullString
 	JudgeCardImage     sql.NullString
@@ -139,6 +170,7 @@ type LobbyGameStatsData struct {
 
 	PlayerId           uuid.UUID
 	PlayerSpyAdvantage bool
+	PlayerCanModerate  bool
 
 	Wins           []nameCountRow
 	Credits        []nameCountRow
@@ -163,14 +195,26 @@ type boardResponse struct {
 	PlayerId       uuid.UUID
 	PlayerUserName string
 	ResponseCards  []boardResponseCard
+
+	// prompt text with the response cards filled into its blanks
+	PromptSegments []PromptSegment
 }
 
 type boardResponseCard struct {
 	ResponseCardId uuid.UUID
 	Card
+	Slot            int
 	SpecialCategory sql.NullString
 }
 
+func (responseCard boardResponseCard) PreviousSlot() int {
+	return responseCard.Slot - 1
+}
+
+func (responseCard boardResponseCard) NextSlot() int {
+	return responseCard.Slot + 1
+}
+
 type nameCountRow struct {
 	Name  string
 	Count int
@@ -180,6 +224,7 @@ type kickVote struct {
 	PlayerId uuid.UUID
 	UserName string
 	Voted    bool
+	Muted    bool
 }
 
 func SearchLobbies(name string, page int) ([]LobbyDetails, error) {
@@ -416,16 +461,31 @@ func SyncDecksInLobby(lobbyId uuid.UUID, deckIdsPrompt []uuid.UUID, deckIdsRespo
 		return err
 	}
 
+	filters, err := GetLobbyCardFilters(lobbyId)
+	if err != nil {
+		return err
+	}
+
+	if filters.DeduplicateCards {
+		err = removeNearDuplicatesFromDrawPile(lobbyId)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
 func addDecksToLobby(lobbyId uuid.UUID, deckIds []uuid.UUID, cardCategory string) error {
+	// ratings compare by their ENUM order, cards with no card or deck rating always match
 	sqlString := fmt.Sprintf(`
 		INSERT INTO DRAW_PILE(LOBBY_ID, CARD_ID)
 		SELECT
-			? AS LOBBY_ID,
+			L.ID AS LOBBY_ID,
 			C.ID AS CARD_ID
 		FROM CARD AS C
+			INNER JOIN DECK AS D ON D.ID = C.DECK_ID
+			INNER JOIN LOBBY AS L ON L.ID = ?
 			LEFT JOIN (
 				SELECT DISTINCT
 					E_C.DECK_ID
@@ -437,6 +497,34 @@ func addDecksToLobby(lobbyId uuid.UUID, deckIds []uuid.UUID, cardCategory string
 		WHERE C.CATEGORY = ?
 			AND C.DECK_ID IN (%s)
 			AND E.DECK_ID IS NULL
+			AND (
+				L.MAX_CONTENT_RATING IS NULL
+				OR COALESCE(C.CONTENT_RATING + 0, D.CONTENT_RATING + 0, 0) <= L.MAX_CONTENT_RATING + 0
+			)
+			AND (
+				NOT EXISTS (
+					SELECT 1
+					FROM LOBBY_CARD_TAG
+					WHERE LOBBY_ID = L.ID
+						AND FILTER = 'INCLUDE'
+				)
+				OR EXISTS (
+					SELECT 1
+					FROM LOBBY_CARD_TAG AS LCT
+						INNER JOIN CARD_TAG AS CT ON CT.TAG = LCT.TAG
+					WHERE LCT.LOBBY_ID = L.ID
+						AND LCT.FILTER = 'INCLUDE'
+						AND CT.CARD_ID = C.ID
+				)
+			)
+			AND NOT EXISTS (
+				SELECT 1
+				FROM LOBBY_CARD_TAG AS LCT
+					INNER JOIN CARD_TAG AS CT ON CT.TAG = LCT.TAG
+				WHERE LCT.LOBBY_ID = L.ID
+					AND LCT.FILTER = 'EXCLUDE'
+					AND CT.CARD_ID = C.ID
+			)
 	`, strings.Repeat("?,", len(deckIds)-1)+"?")
 
 	args := make([]any, len(deckIds)+4)
@@ -616,6 +704,147 @@ func SetLobbyLoseStreakThreshold(id uuid.UUID, loseStreakThreshold int) error {
 	return execute(sqlString, loseStreakThreshold, id)
 }
 
+func GetLobbyRules(lobbyId uuid.UUID) (LobbyRules, error) {
+	var rules LobbyRules
+
+	sqlString := `
+		SELECT
+			FN_GET_SPECIAL_IS_ENABLED(L.ID, 'ALERT'),
+			FN_GET_SPECIAL_IS_ENABLED(L.ID, 'GAMBLE'),
+			FN_GET_SPECIAL_IS_ENABLED(L.ID, 'BET'),
+			FN_GET_SPECIAL_IS_ENABLED(L.ID, 'SKIP-JUDGE'),
+			FN_GET_SPECIAL_IS_ENABLED(L.ID, 'EXTRA-RESPONSE'),
+			FN_GET_SPECIAL_IS_ENABLED(L.ID, 'BLOCK-RESPONSE'),
+			FN_GET_SPECIAL_IS_ENABLED(L.ID, 'SURPRISE'),
+			FN_GET_SPECIAL_IS_ENABLED(L.ID, 'STEAL'),
+			FN_GET_SPECIAL_IS_ENABLED(L.ID, 'FIND'),
+			FN_GET_SPECIAL_IS_ENABLED(L.ID, 'WILD'),
+			FN_GET_SPECIAL_IS_ENABLED(L.ID, 'PERK'),
+			FN_GET_SPECIAL_IS_ENABLED(L.ID, 'FLIP-TABLE'),
+			FN_GET_SPECIAL_COST(L.ID, 'SKIP-JUDGE'),
+			FN_GET_SPECIAL_COST(L.ID, 'EXTRA-RESPONSE'),
+			FN_GET_SPECIAL_COST(L.ID, 'BLOCK-RESPONSE'),
+			FN_GET_SPECIAL_COST(L.ID, 'SURPRISE'),
+			FN_GET_SPECIAL_COST(L.ID, 'STEAL'),
+			FN_GET_SPECIAL_COST(L.ID, 'FIND'),
+			FN_GET_SPECIAL_COST(L.ID, 'WILD'),
+			FN_GET_SPECIAL_COST(L.ID, 'PERK')
+		FROM LOBBY AS L
+		WHERE L.ID = ?
+	`
+	rows, err := query(sqlString, lobbyId)
+	if err != nil {
+		return rules, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err := rows.Scan(
+			&rules.EnabledAlert,
+			&rules.EnabledGamble,
+			&rules.EnabledBet,
+			&rules.EnabledSkipBeingJudge,
+			&rules.EnabledExtraResponse,
+			&rules.EnabledBlockResponse,
+			&rules.EnabledSurpriseCard,
+			&rules.EnabledStealCard,
+			&rules.EnabledFindCard,
+			&rules.EnabledWildCard,
+			&rules.EnabledPerk,
+			&rules.EnabledFlipTable,
+			&rules.CostSkipBeingJudge,
+			&rules.CostExtraResponse,
+			&rules.CostBlockResponse,
+			&rules.CostSurpriseCard,
+			&rules.CostStealCard,
+			&rules.CostFindCard,
+			&rules.CostWildCard,
+			&rules.CostPerk,
+		); err != nil {
+			log.Println(err)
+			return rules, errors.New("failed to scan row in query results")
+		}
+	}
+
+	return rules, nil
+}
+
+func SetLobbyRules(lobbyId uuid.UUID, rules LobbyRules) error {
+	sqlString := `
+		INSERT INTO LOBBY_RULE(LOBBY_ID, CATEGORY, IS_ENABLED, COST)
+		VALUES
+			(?, 'ALERT', ?, NULL),
+			(?, 'GAMBLE', ?, NULL),
+			(?, 'BET', ?, NULL),
+			(?, 'SKIP-JUDGE', ?, ?),
+			(?, 'EXTRA-RESPONSE', ?, ?),
+			(?, 'BLOCK-RESPONSE', ?, ?),
+			(?, 'SURPRISE', ?, ?),
+			(?, 'STEAL', ?, ?),
+			(?, 'FIND', ?, ?),
+			(?, 'WILD', ?, ?),
+			(?, 'PERK', ?, ?),
+			(?, 'FLIP-TABLE', ?, NULL)
+		ON DUPLICATE KEY UPDATE
+			IS_ENABLED = VALUE(IS_ENABLED),
+			COST = VALUE(COST)
+	`
+	return execute(sqlString,
+		lobbyId, rules.EnabledAlert,
+		lobbyId, rules.EnabledGamble,
+		lobbyId, rules.EnabledBet,
+		lobbyId, rules.EnabledSkipBeingJudge, rules.CostSkipBeingJudge,
+		lobbyId, rules.EnabledExtraResponse, rules.CostExtraResponse,
+		lobbyId, rules.EnabledBlockResponse, rules.CostBlockResponse,
+		lobbyId, rules.EnabledSurpriseCard, rules.CostSurpriseCard,
+		lobbyId, rules.EnabledStealCard, rules.CostStealCard,
+		lobbyId, rules.EnabledFindCard, rules.CostFindCard,
+		lobbyId, rules.EnabledWildCard, rules.CostWildCard,
+		lobbyId, rules.EnabledPerk, rules.CostPerk,
+		lobbyId, rules.EnabledFlipTable,
+	)
+}
+
+func GetLobbySpecialIsEnabled(lobbyId uuid.UUID, category string) (bool, error) {
+	isEnabled := true
+
+	sqlString := "SELECT FN_GET_SPECIAL_IS_ENABLED (?, ?)"
+	rows, err := query(sqlString, lobbyId, category)
+	if err != nil {
+		return isEnabled, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err := rows.Scan(&isEnabled); err != nil {
+			log.Println(err)
+			return isEnabled, errors.New("failed to scan row in query results")
+		}
+	}
+
+	return isEnabled, nil
+}
+
+func GetLobbyOwnerPlayerId(lobbyId uuid.UUID) (uuid.UUID, error) {
+	var ownerPlayerId uuid.UUID
+
+	sqlString := "SELECT FN_GET_LOBBY_OWNER_PLAYER_ID (?)"
+	rows, err := query(sqlString, lobbyId)
+	if err != nil {
+		return ownerPlayerId, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err := rows.Scan(&ownerPlayerId); err != nil {
+			log.Println(err)
+			return ownerPlayerId, errors.New("failed to scan row in query results")
+		}
+	}
+
+	return ownerPlayerId, nil
+}
+
 func DeleteLobby(lobbyId uuid.UUID) error {
 	sqlString := `
 		DELETE
@@ -631,15 +860,7 @@ func GetLobbyGameInfo(lobbyId uuid.UUID, playerId uuid.UUID) (LobbyGameInfo, err
 	sqlString := `
 		SELECT
 			L.NAME AS LOBBY_NAME,
-			(
-				SELECT
-					P.ID
-				FROM PLAYER AS P
-				WHERE P.LOBBY_ID = L.ID
-					AND P.IS_ACTIVE = 1
-				ORDER BY P.JOIN_ORDER ASC
-				LIMIT 1
-			) AS LOBBY_OWNER_ID,
+			FN_GET_LOBBY_OWNER_PLAYER_ID(L.ID) AS LOBBY_OWNER_ID,
 			(
 				SELECT
 					U.NAME
@@ -727,7 +948,14 @@ func GetPlayerHandData(playerId uuid.UUID) (PlayerHandData, error) {
 			L.ID AS LOBBY_ID,
 			P.ID AS PLAYER_ID,
 			IF(FN_GET_LOBBY_JUDGE_PLAYER_ID(L.ID) = P.ID, 1, 0) AS PLAYER_IS_JUDGE,
-			P.DISCARD_ADVANTAGE AS PLAYER_DISCARD_ADVANTAGE
+			P.DISCARD_ADVANTAGE AS PLAYER_DISCARD_ADVANTAGE,
+			(
+				SELECT
+					COUNT(*) + 1
+				FROM RESPONSE_CARD
+				WHERE RESPONSE_ID = FN_GET_PLAYER_OPEN_RESPONSE_ID(P.ID)
+			) AS PLAYER_NEXT_SLOT,
+			COALESCE(FN_GET_LOBBY_JUDGE_BLANK_COUNT(L.ID), 1) AS JUDGE_BLANK_COUNT
 		FROM PLAYER AS P
 			INNER JOIN LOBBY AS L ON L.ID = P.LOBBY_ID
 		WHERE P.ID = ?
@@ -744,6 +972,8 @@ func GetPlayerHandData(playerId uuid.UUID) (PlayerHandData, error) {
 			&data.PlayerId,
 			&data.PlayerIsJudge,
 			&data.PlayerDiscardAdvantage,
+			&data.PlayerNextSlot,
+			&data.JudgeBlankCount,
 		); err != nil {
 			log.Println(err)
 			return data, errors.New("failed to scan row in query results")
@@ -790,7 +1020,7 @@ func GetPlayerHandData(playerId uuid.UUID) (PlayerHandData, error) {
 			C.ID,
 			C.TEXT,
 			C.YOUTUBE,
-			C.IMAGE
+			SHA1(C.IMAGE) AS IMAGE
 		FROM HAND AS H
 			INNER JOIN CARD AS C ON C.ID = H.CARD_ID
 		WHERE H.PLAYER_ID = ?
@@ -804,22 +1034,16 @@ func GetPlayerHandData(playerId uuid.UUID) (PlayerHandData, error) {
 
 	for rows.Next() {
 		var card Card
-		var imageBytes []byte
 		if err := rows.Scan(
 			&card.Id,
 			&card.Text,
 			&card.YouTube,
-			&imageBytes,
+			&card.Image,
 		); err != nil {
 			log.Println(err)
 			return data, errors.New("failed to scan row in query results")
 		}
 
-		card.Image.Valid = imageBytes != nil
-		if card.Image.Valid {
-			card.Image.String = base64.StdEncoding.EncodeToString(imageBytes)
-		}
-
 		data.PlayerHand = append(data.PlayerHand, card)
 	}
 
@@ -837,6 +1061,7 @@ func GetPlayerSpecialsData(playerId uuid.UUID) (PlayerSpecialsData, error) {
 			L.WIN_STREAK_THRESHOLD AS LOBBY_WIN_STREAK_THRESHOLD,
 			L.LOSE_STREAK_THRESHOLD AS LOBBY_LOSE_STREAK_THRESHOLD,
 			P.ID AS PLAYER_ID,
+			IF(FN_GET_LOBBY_OWNER_PLAYER_ID(L.ID) = P.ID, 1, 0) AS PLAYER_IS_LOBBY_OWNER,
 			IF(FN_GET_LOBBY_JUDGE_PLAYER_ID(L.ID) = P.ID, 1, 0) AS PLAYER_IS_JUDGE,
 			FN_GET_PLAYER_HANDICAP(P.ID) AS PLAYER_HANDICAP,
 			P.WINNING_STREAK AS PLAYER_WINNING_STREAK,
@@ -866,6 +1091,7 @@ func GetPlayerSpecialsData(playerId uuid.UUID) (PlayerSpecialsData, error) {
 			&data.LobbyWinStreakThreshold,
 			&data.LobbyLoseStreakThreshold,
 			&data.PlayerId,
+			&data.PlayerIsLobbyOwner,
 			&data.PlayerIsJudge,
 			&data.PlayerHandicap,
 			&data.PlayerWinningStreak,
@@ -1018,39 +1244,27 @@ func GetPlayerSpecialsData(playerId uuid.UUID) (PlayerSpecialsData, error) {
 		data.CreditHistory = append(data.CreditHistory, row)
 	}
 
-	sqlString = `
-		SELECT
-			FN_GET_SPECIAL_COST('SKIP-JUDGE'),
-			FN_GET_SPECIAL_COST('EXTRA-RESPONSE'),
-			FN_GET_SPECIAL_COST('BLOCK-RESPONSE'),
-			FN_GET_SPECIAL_COST('SURPRISE'),
-			FN_GET_SPECIAL_COST('STEAL'),
-			FN_GET_SPECIAL_COST('FIND'),
-			FN_GET_SPECIAL_COST('WILD'),
-			FN_GET_SPECIAL_COST('PERK')
-	`
-	rows, err = query(sqlString)
+	data.Rules, err = GetLobbyRules(data.LobbyId)
 	if err != nil {
 		return data, err
 	}
-	defer rows.Close()
 
-	for rows.Next() {
-		if err := rows.Scan(
-			&data.SpecialCostSkipBeingJudge,
-			&data.SpecialCostExtraResponse,
-			&data.SpecialCostBlockResponse,
-			&data.SpecialCostSurpriseCard,
-			&data.SpecialCostStealCard,
-			&data.SpecialCostFindCard,
-			&data.SpecialCostWildCard,
-			&data.SpecialCostPerk,
-		); err != nil {
-			log.Println(err)
-			return data, errors.New("failed to scan row in query results")
+	if data.PlayerIsLobbyOwner {
+		data.LobbyFilterWords, err = GetLobbyFilterWords(data.LobbyId)
+		if err != nil {
+			return data, err
 		}
 	}
 
+	data.SpecialCostSkipBeingJudge = data.Rules.CostSkipBeingJudge
+	data.SpecialCostExtraResponse = data.Rules.CostExtraResponse
+	data.SpecialCostBlockResponse = data.Rules.CostBlockResponse
+	data.SpecialCostSurpriseCard = data.Rules.CostSurpriseCard
+	data.SpecialCostStealCard = data.Rules.CostStealCard
+	data.SpecialCostFindCard = data.Rules.CostFindCard
+	data.SpecialCostWildCard = data.Rules.CostWildCard
+	data.SpecialCostPerk = data.Rules.CostPerk
+
 	data.SpecialCostSkipBeingJudge += data.PlayerHandicap
 	data.SpecialCostExtraResponse += data.PlayerHandicap
 	data.SpecialCostBlockResponse += data.PlayerHandicap
@@ -1093,9 +1307,10 @@ func GetLobbyGameBoardData(playerId uuid.UUID) (LobbyGameBoardData, error) {
 	sqlString := `
 		SELECT
 			L.ID AS LOBBY_ID,
+			J.CARD_ID AS JUDGE_CARD_ID,
 			(SELECT TEXT FROM CARD WHERE ID = J.CARD_ID) AS JUDGE_CARD_TEXT,
 			(SELECT YOUTUBE FROM CARD WHERE ID = J.CARD_ID) AS JUDGE_CARD_YOUTUBE,
-			(SELECT IMAGE FROM CARD WHERE ID = J.CARD_ID) AS JUDGE_CARD_IMAGE,
+			(SELECT SHA1(IMAGE) FROM CARD WHERE ID = J.CARD_ID) AS JUDGE_CARD_IMAGE,
 			J.BLANK_COUNT AS JUDGE_BLANK_COUNT,
 			J.RESPONSE_COUNT AS JUDGE_RESPONSE_COUNT,
 			L.ROUND_TIMER AS ROUND_TIMER,
@@ -1113,12 +1328,12 @@ func GetLobbyGameBoardData(playerId uuid.UUID) (LobbyGameBoardData, error) {
 	defer rows.Close()
 
 	for rows.Next() {
-		var imageBytes []byte
 		if err := rows.Scan(
 			&data.LobbyId,
+			&data.JudgeCardId,
 			&data.JudgeCardText,
 			&data.JudgeCardYouTube,
-			&imageBytes,
+			&data.JudgeCardImage,
 			&data.JudgeBlankCount,
 			&data.JudgeResponseCount,
 			&data.RoundTimer,
@@ -1128,11 +1343,6 @@ func GetLobbyGameBoardData(playerId uuid.UUID) (LobbyGameBoardData, error) {
 			log.Println(err)
 			return data, errors.New("failed to scan row in query results")
 		}
-
-		data.JudgeCardImage.Valid = imageBytes != nil
-		if data.JudgeCardImage.Valid {
-			data.JudgeCardImage.String = base64.StdEncoding.EncodeToString(imageBytes)
-		}
 	}
 
 	sqlString = `
@@ -1196,13 +1406,15 @@ func GetLobbyGameBoardData(playerId uuid.UUID) (LobbyGameBoardData, error) {
 				C.ID AS CARD_ID,
 				C.TEXT AS CARD_TEXT,
 				C.YOUTUBE AS CARD_YOUTUBE,
-				C.IMAGE AS CARD_IMAGE,
+				SHA1(C.IMAGE) AS CARD_IMAGE,
+				RC.SLOT,
 				RC.SPECIAL_CATEGORY
 			FROM RESPONSE AS R
 				INNER JOIN RESPONSE_CARD AS RC ON RC.RESPONSE_ID = R.ID
 				INNER JOIN CARD AS C ON C.ID = RC.CARD_ID
 			WHERE R.ID = ?
-			ORDER BY RC.CREATED_ON_DATE
+			ORDER BY RC.SLOT,
+				RC.CREATED_ON_DATE
 		`
 		rows, err = query(sqlString, br.ResponseId)
 		if err != nil {
@@ -1212,29 +1424,30 @@ func GetLobbyGameBoardData(playerId uuid.UUID) (LobbyGameBoardData, error) {
 
 		for rows.Next() {
 			var responseCard boardResponseCard
-			var imageBytes []byte
 			if err := rows.Scan(
 				&responseCard.ResponseCardId,
 				&responseCard.Id,
 				&responseCard.Text,
 				&responseCard.YouTube,
-				&imageBytes,
+				&responseCard.Image,
+				&responseCard.Slot,
 				&responseCard.SpecialCategory,
 			); err != nil {
 				log.Println(err)
 				return data, errors.New("failed to scan row in query results")
 			}
 
-			responseCard.Image.Valid = imageBytes != nil
-			if responseCard.Image.Valid {
-				responseCard.Image.String = base64.StdEncoding.EncodeToString(imageBytes)
-			}
-
 			data.BoardResponses[i].ResponseCards = append(data.BoardResponses[i].ResponseCards, responseCard)
 
 			totalCardsPlayedCount += 1
 		}
 
+		responseTexts := make([]string, 0)
+		for _, responseCard := range data.BoardResponses[i].ResponseCards {
+			responseTexts = append(responseTexts, responseCard.Text)
+		}
+		data.BoardResponses[i].PromptSegments = fillPromptBlanks(data.JudgeCardText.String, responseTexts)
+
 		if br.PlayerId == data.PlayerId {
 			data.PlayerResponses = append(data.PlayerResponses, data.BoardResponses[i])
 		}
@@ -1327,8 +1540,15 @@ func GetLobbyGameStatsData(playerId uuid.UUID) (LobbyGameStatsData, error) {
 		SELECT
 			P.LOBBY_ID AS LOBBY_ID,
 			P.ID AS PLAYER_ID,
-			P.SPY_ADVANTAGE AS PLAYER_SPY_ADVANTAGE
+			P.SPY_ADVANTAGE AS PLAYER_SPY_ADVANTAGE,
+			IF(
+				FN_GET_LOBBY_OWNER_PLAYER_ID(P.LOBBY_ID) = P.ID
+				OR U.IS_ADMIN = 1,
+				1,
+				0
+			) AS PLAYER_CAN_MODERATE
 		FROM PLAYER AS P
+			INNER JOIN USER AS U ON U.ID = P.USER_ID
 		WHERE P.ID = ?
 	`
 	rows, err := query(sqlString, playerId)
@@ -1342,6 +1562,7 @@ func GetLobbyGameStatsData(playerId uuid.UUID) (LobbyGameStatsData, error) {
 			&data.LobbyId,
 			&data.PlayerId,
 			&data.PlayerSpyAdvantage,
+			&data.PlayerCanModerate,
 		); err != nil {
 			log.Println(err)
 			return data, errors.New("failed to scan row in query results")
@@ -1446,7 +1667,18 @@ func GetLobbyGameStatsData(playerId uuid.UUID) (LobbyGameStatsData, error) {
 				),
 				1,
 				0
-			) AS VOTED
+			) AS VOTED,
+			IF(
+				EXISTS(
+					SELECT
+						ID
+					FROM LOBBY_MUTE
+					WHERE LOBBY_ID = P.LOBBY_ID
+						AND USER_ID = P.USER_ID
+				),
+				1,
+				0
+			) AS MUTED
 		FROM PLAYER AS P
 			INNER JOIN USER AS U ON U.ID = P.USER_ID
 		WHERE P.IS_ACTIVE = 1
@@ -1465,7 +1697,8 @@ func GetLobbyGameStatsData(playerId uuid.UUID) (LobbyGameStatsData, error) {
 		if err := rows.Scan(
 			&row.PlayerId,
 			&row.UserName,
-			&row.Voted); err != nil {
+			&row.Voted,
+			&row.Muted); err != nil {
 			log.Println(err)
 			return data, errors.New("failed to scan row in query results")
 		}
@@ -1608,6 +1841,13 @@ func WithdrawCard(responseCardId uuid.UUID) error {
 	return execute(sqlString, responseCardId)
 }
 
+// MoveResponseCard swaps the response card with the card in the given blank
+// of the same response.
+func MoveResponseCard(playerId uuid.UUID, responseCardId uuid.UUID, slot int) error {
+	sqlString := "CALL SP_MOVE_RESPONSE_CARD (?, ?, ?)"
+	return execute(sqlString, playerId, responseCardId, slot)
+}
+
 func DiscardCard(playerId uuid.UUID, cardId uuid.UUID) error {
 	sqlString := "CALL SP_DISCARD_CARD (?, ?)"
 	return execute(sqlString, playerId, cardId)
@@ -1632,6 +1872,13 @@ func VoteToKick(voterPlayerId uuid.UUID, subjectPlayerId uuid.UUID) (bool, error
 	return isKicked, nil
 }
 
+// KickPlayer removes the player without a vote, logging the kick the same as
+// a successful vote.
+func KickPlayer(subjectPlayerId uuid.UUID) error {
+	sqlString := "CALL SP_KICK_PLAYER (?)"
+	return execute(sqlString, subjectPlayerId)
+}
+
 func VoteToKickUndo(voterPlayerId uuid.UUID, subjectPlayerId uuid.UUID) error {
 	sqlString := "CALL SP_VOTE_TO_KICK_UNDO (?, ?)"
 	return execute(sqlString, voterPlayerId, subjectPlayerId)
@@ -144,3 +144,65 @@ func GetLobbyUserPlayer(lobbyId uuid.UUID, userId uuid.UUID) (Player, error) {
 
 	return player, nil
 }
+
+// GetActiveLobbyPlayers returns the players currently in the lobby.
+func GetActiveLobbyPlayers(lobbyId uuid.UUID) ([]Player, error) {
+	sqlString := `
+		SELECT
+			P.ID,
+			P.CREATED_ON_DATE,
+			U.NAME,
+			P.LOBBY_ID,
+			P.USER_ID,
+			P.JOIN_ORDER,
+			P.IS_ACTIVE,
+			P.WINNING_STREAK,
+			P.LOSING_STREAK,
+			P.CREDITS_SPENT,
+			P.BET_ON_WIN,
+			P.EXTRA_RESPONSES,
+			P.HAND_SIZE_ADVANTAGE,
+			P.DISCARD_ADVANTAGE,
+			P.HANDICAP_ADVANTAGE,
+			P.SPY_ADVANTAGE
+		FROM PLAYER AS P
+			INNER JOIN USER AS U ON U.ID = P.USER_ID
+		WHERE P.LOBBY_ID = ?
+			AND P.IS_ACTIVE = 1
+		ORDER BY P.JOIN_ORDER
+	`
+	rows, err := query(sqlString, lobbyId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]Player, 0)
+	for rows.Next() {
+		var player Player
+		if err := rows.Scan(
+			&player.Id,
+			&player.CreatedOnDate,
+			&player.Name,
+			&player.LobbyId,
+			&player.UserId,
+			&player.JoinOrder,
+			&player.IsActive,
+			&player.WinningStreak,
+			&player.LosingStreak,
+			&player.CreditsSpent,
+			&player.BetOnWin,
+			&player.ExtraResponses,
+			&player.HandSizeAdvantage,
+			&player.DiscardAdvantage,
+			&player.HandicapAdvantage,
+			&player.SpyAdvantage,
+		); err != nil {
+			log.Println(err)
+			return nil, errors.New("failed to scan row in query results")
+		}
+		result = append(result, player)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,539 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type UserRating struct {
+	Rating     float64             `json:"rating"`
+	RoundCount int                 `json:"round_count"`
+	History    []UserRatingHistory `json:"history"`
+}
+
+type UserRatingHistory struct {
+	CreatedOnDate time.Time `json:"created_on_date"`
+	RoundId       uuid.UUID `json:"round_id"`
+	Rating        float64   `json:"rating"`
+	RatingChange  float64   `json:"rating_change"`
+}
+
+const (
+	initialRating = 1500.0
+	ratingKFactor = 32.0
+
+	// a win can commit after a later one was already rated, so rounds this
+	// close before the last one rated are looked at again
+	ratingLateWinWindow = time.Hour
+)
+
+// only one update can process new rounds at a time, or a round could be
+// rated twice
+var ratingMutex sync.Mutex
+
+type ratingRound struct {
+	createdOnDate time.Time
+	roundId       uuid.UUID
+	winners       []uuid.UUID
+	losers        []uuid.UUID
+}
+
+type ratingHistoryRow struct {
+	createdOnDate time.Time
+	userId        uuid.UUID
+	roundId       uuid.UUID
+	rating        float64
+	ratingChange  float64
+}
+
+func (userRating UserRating) RoundedRating() int {
+	return int(math.Round(userRating.Rating))
+}
+
+func (userRating UserRating) ChartMinRating() int {
+	return int(math.Floor(userRating.chartRange()[0]))
+}
+
+func (userRating UserRating) ChartMaxRating() int {
+	return int(math.Ceil(userRating.chartRange()[1]))
+}
+
+func (userRating UserRating) FirstRatedDate() time.Time {
+	if len(userRating.History) == 0 {
+		return time.Time{}
+	}
+	return userRating.History[0].CreatedOnDate
+}
+
+func (userRating UserRating) LastRatedDate() time.Time {
+	if len(userRating.History) == 0 {
+		return time.Time{}
+	}
+	return userRating.History[len(userRating.History)-1].CreatedOnDate
+}
+
+// ChartPoints places the rating after every round on a 100 by 40 grid,
+// starting from the initial rating.
+func (userRating UserRating) ChartPoints() string {
+	chartRange := userRating.chartRange()
+	ratings := []float64{initialRating}
+	for _, history := range userRating.History {
+		ratings = append(ratings, history.Rating)
+	}
+
+	points := make([]string, 0, len(ratings))
+	for i, rating := range ratings {
+		x := float64(i) * 100 / float64(max(len(ratings)-1, 1))
+		y := 40 - (rating-chartRange[0])*40/(chartRange[1]-chartRange[0])
+		points = append(points, fmt.Sprintf("%.2f,%.2f", x, y))
+	}
+	return strings.Join(points, " ")
+}
+
+func (userRating UserRating) chartRange() [2]float64 {
+	low := initialRating
+	high := initialRating
+	for _, history := range userRating.History {
+		low = min(low, history.Rating)
+		high = max(high, history.Rating)
+	}
+
+	// keep a flat line in the middle of the chart
+	return [2]float64{low - 10, high + 10}
+}
+
+func GetUserRating(userId uuid.UUID) (UserRating, error) {
+	result := UserRating{
+		Rating:  initialRating,
+		History: make([]UserRatingHistory, 0),
+	}
+
+	sqlString := `
+		SELECT
+			CREATED_ON_DATE,
+			ROUND_ID,
+			RATING,
+			RATING_CHANGE
+		FROM USER_RATING_HISTORY
+		WHERE USER_ID = ?
+		ORDER BY CREATED_ON_DATE
+	`
+	rows, err := query(sqlString, userId)
+	if err != nil {
+		return result, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var history UserRatingHistory
+		if err := rows.Scan(
+			&history.CreatedOnDate,
+			&history.RoundId,
+			&history.Rating,
+			&history.RatingChange,
+		); err != nil {
+			log.Println(err)
+			return result, errors.New("failed to scan row in query results")
+		}
+		result.History = append(result.History, history)
+	}
+
+	if len(result.History) > 0 {
+		result.Rating = result.History[len(result.History)-1].Rating
+		result.RoundCount = len(result.History)
+	}
+
+	return result, nil
+}
+
+// UpdateUserRatings rates every round won since the last update, treating
+// each round as a match the winner won against every other player.
+func UpdateUserRatings() error {
+	ratingMutex.Lock()
+	defer ratingMutex.Unlock()
+
+	return updateUserRatings()
+}
+
+// RecomputeUserRatings throws away all ratings and rates every round again.
+func RecomputeUserRatings() error {
+	ratingMutex.Lock()
+	defer ratingMutex.Unlock()
+
+	sqlString := `
+		DELETE
+		FROM USER_RATING_HISTORY
+	`
+	err := execute(sqlString)
+	if err != nil {
+		return err
+	}
+
+	sqlString = `
+		DELETE
+		FROM USER_RATING
+	`
+	err = execute(sqlString)
+	if err != nil {
+		return err
+	}
+
+	sqlString = `
+		DELETE
+		FROM STAT_RATING_DAY
+	`
+	err = execute(sqlString)
+	if err != nil {
+		return err
+	}
+
+	sqlString = `
+		DELETE
+		FROM SETTING
+		WHERE NAME = 'USER_RATING_DATE'
+	`
+	err = execute(sqlString)
+	if err != nil {
+		return err
+	}
+
+	return updateUserRatings()
+}
+
+func updateUserRatings() error {
+	ratings, err := getLatestUserRatings()
+	if err != nil {
+		return err
+	}
+
+	lastRatedDate, err := getLastRatedDate()
+	if err != nil {
+		return err
+	}
+
+	afterDate := lastRatedDate.Add(-ratingLateWinWindow)
+	ratedRoundIds, err := getRatedRoundIds(afterDate)
+	if err != nil {
+		return err
+	}
+
+	allRounds, err := getRatingRounds(afterDate)
+	if err != nil {
+		return err
+	}
+
+	rounds := make([]ratingRound, 0)
+	for _, round := range allRounds {
+		if !ratedRoundIds[round.roundId] {
+			rounds = append(rounds, round)
+		}
+	}
+
+	if len(rounds) == 0 {
+		return nil
+	}
+
+	historyRows := make([]ratingHistoryRow, 0)
+	for i, round := range rounds {
+		for userId, change := range getRoundRatingChanges(round, ratings) {
+			rating, ok := ratings[userId]
+			if !ok {
+				rating = initialRating
+			}
+			ratings[userId] = rating + change
+
+			historyRows = append(historyRows, ratingHistoryRow{
+				createdOnDate: round.createdOnDate,
+				userId:        userId,
+				roundId:       round.roundId,
+				rating:        rating + change,
+				ratingChange:  change,
+			})
+		}
+
+		// whole rounds are inserted together, so a failed insert never
+		// leaves a round half rated
+		if len(historyRows) >= 500 || i == len(rounds)-1 {
+			err = insertRatingHistoryRows(historyRows)
+			if err != nil {
+				return err
+			}
+			historyRows = historyRows[:0]
+		}
+	}
+
+	sqlString := `
+		INSERT INTO USER_RATING (USER_ID, RATING, ROUND_COUNT)
+		SELECT
+			USER_ID,
+			RATING,
+			ROUND_COUNT
+		FROM (
+				SELECT
+					USER_ID,
+					RATING,
+					ROW_NUMBER() OVER (PARTITION BY USER_ID ORDER BY CREATED_ON_DATE DESC) AS ROW_NUM,
+					COUNT(*) OVER (PARTITION BY USER_ID) AS ROUND_COUNT
+				FROM USER_RATING_HISTORY
+			) AS T
+		WHERE ROW_NUM = 1
+		ON DUPLICATE KEY
+		UPDATE CHANGED_ON_DATE = CURRENT_TIMESTAMP(6),
+			RATING = VALUES(RATING),
+			ROUND_COUNT = VALUES(ROUND_COUNT)
+	`
+	err = execute(sqlString)
+	if err != nil {
+		return err
+	}
+
+	if rounds[len(rounds)-1].createdOnDate.After(lastRatedDate) {
+		sqlString = `
+			INSERT INTO SETTING(NAME, VALUE)
+			VALUES ('USER_RATING_DATE', ?)
+			ON DUPLICATE KEY UPDATE
+				VALUE = VALUES(VALUE)
+		`
+		err = execute(sqlString, rounds[len(rounds)-1].createdOnDate.Format("2006-01-02 15:04:05.000000"))
+		if err != nil {
+			return err
+		}
+	}
+
+	seasons, err := getUnarchivedSeasons(rounds[0].createdOnDate, rounds[len(rounds)-1].createdOnDate)
+	if err != nil {
+		return err
+	}
+
+	for _, season := range seasons {
+		err = updateSeasonRatings(season)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getLatestUserRatings reads ratings from the history rather than
+// USER_RATING, so an update that failed part way through is picked up again
+// from the last round it rated.
+func getLatestUserRatings() (map[uuid.UUID]float64, error) {
+	ratings := make(map[uuid.UUID]float64)
+
+	sqlString := `
+		SELECT
+			USER_ID,
+			RATING
+		FROM (
+				SELECT
+					USER_ID,
+					RATING,
+					CREATED_ON_DATE,
+					ROW_NUMBER() OVER (PARTITION BY USER_ID ORDER BY CREATED_ON_DATE DESC) AS ROW_NUM
+				FROM USER_RATING_HISTORY
+			) AS T
+		WHERE ROW_NUM = 1
+	`
+	rows, err := query(sqlString)
+	if err != nil {
+		return ratings, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var userId uuid.UUID
+		var rating float64
+		if err := rows.Scan(&userId, &rating); err != nil {
+			log.Println(err)
+			return ratings, errors.New("failed to scan row in query results")
+		}
+		ratings[userId] = rating
+	}
+
+	return ratings, nil
+}
+
+// getLastRatedDate is kept in a setting rather than read from the history,
+// which loses rows when a user is deleted. Ratings from before the setting
+// existed fall back to the history.
+func getLastRatedDate() (time.Time, error) {
+	lastRatedDate := time.Unix(0, 0)
+
+	sqlString := `
+		SELECT
+			COALESCE(
+				(
+					SELECT
+						CAST(VALUE AS DATETIME(6))
+					FROM SETTING
+					WHERE NAME = 'USER_RATING_DATE'
+				),
+				(
+					SELECT
+						MAX(CREATED_ON_DATE)
+					FROM USER_RATING_HISTORY
+				),
+				TIMESTAMP('1970-01-01')
+			)
+	`
+	rows, err := query(sqlString)
+	if err != nil {
+		return lastRatedDate, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err := rows.Scan(&lastRatedDate); err != nil {
+			log.Println(err)
+			return lastRatedDate, errors.New("failed to scan row in query results")
+		}
+	}
+
+	return lastRatedDate, nil
+}
+
+// getRatedRoundIds finds the rounds won after the date that already have
+// history, however long ago they were rated.
+func getRatedRoundIds(afterDate time.Time) (map[uuid.UUID]bool, error) {
+	sqlString := `
+		SELECT DISTINCT
+			ROUND_ID
+		FROM USER_RATING_HISTORY
+		WHERE ROUND_ID IN (
+				SELECT
+					ROUND_ID
+				FROM V_ROUND_WINNER
+				WHERE TIMESTAMP > ?
+			)
+	`
+	rows, err := query(sqlString, afterDate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make(map[uuid.UUID]bool)
+	for rows.Next() {
+		var roundId uuid.UUID
+		if err := rows.Scan(&roundId); err != nil {
+			log.Println(err)
+			return nil, errors.New("failed to scan row in query results")
+		}
+		result[roundId] = true
+	}
+	return result, nil
+}
+
+func getRatingRounds(afterDate time.Time) ([]ratingRound, error) {
+	sqlString := `
+		SELECT
+			RW.TIMESTAMP,
+			RW.ROUND_ID,
+			LRC.PLAYER_USER_ID,
+			MAX(LRC.PLAYER_USER_ID = RW.USER_ID) AS IS_WINNER
+		FROM V_ROUND_WINNER AS RW
+			INNER JOIN LOG_RESPONSE_CARD AS LRC ON LRC.ROUND_ID = RW.ROUND_ID
+			INNER JOIN USER AS U ON U.ID = LRC.PLAYER_USER_ID
+		WHERE RW.TIMESTAMP > ?
+		GROUP BY RW.TIMESTAMP,
+			RW.ROUND_ID,
+			LRC.PLAYER_USER_ID
+		ORDER BY RW.TIMESTAMP,
+			RW.ROUND_ID
+	`
+	rows, err := query(sqlString, afterDate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]ratingRound, 0)
+	seenRoundIds := make(map[uuid.UUID]bool)
+	for rows.Next() {
+		var createdOnDate time.Time
+		var roundId uuid.UUID
+		var userId uuid.UUID
+		var isWinner bool
+		if err := rows.Scan(&createdOnDate, &roundId, &userId, &isWinner); err != nil {
+			log.Println(err)
+			return nil, errors.New("failed to scan row in query results")
+		}
+
+		last := len(result) - 1
+		if last < 0 || result[last].roundId != roundId || !result[last].createdOnDate.Equal(createdOnDate) {
+			// a round only counts once, even if it was somehow won twice
+			if seenRoundIds[roundId] {
+				continue
+			}
+			seenRoundIds[roundId] = true
+
+			result = append(result, ratingRound{
+				createdOnDate: createdOnDate,
+				roundId:       roundId,
+			})
+			last++
+		}
+
+		if isWinner {
+			result[last].winners = append(result[last].winners, userId)
+		} else {
+			result[last].losers = append(result[last].losers, userId)
+		}
+	}
+
+	return result, nil
+}
+
+// getRoundRatingChanges splits the K factor across the other players, so a
+// round moves a rating about as much however many people played. Players who
+// lost to the same winner learn nothing about each other and are not compared.
+func getRoundRatingChanges(round ratingRound, ratings map[uuid.UUID]float64) map[uuid.UUID]float64 {
+	result := make(map[uuid.UUID]float64)
+	if len(round.winners) == 0 || len(round.losers) == 0 {
+		return result
+	}
+
+	getRating := func(userId uuid.UUID) float64 {
+		if rating, ok := ratings[userId]; ok {
+			return rating
+		}
+		return initialRating
+	}
+
+	k := ratingKFactor / float64(len(round.winners)+len(round.losers)-1)
+	for _, winnerId := range round.winners {
+		for _, loserId := range round.losers {
+			expected := 1 / (1 + math.Pow(10, (getRating(loserId)-getRating(winnerId))/400))
+			result[winnerId] += k * (1 - expected)
+			result[loserId] -= k * (1 - expected)
+		}
+	}
+
+	return result
+}
+
+func insertRatingHistoryRows(historyRows []ratingHistoryRow) error {
+	if len(historyRows) == 0 {
+		return nil
+	}
+
+	sqlString := fmt.Sprintf(`
+		INSERT IGNORE INTO USER_RATING_HISTORY(CREATED_ON_DATE, USER_ID, ROUND_ID, RATING, RATING_CHANGE)
+		VALUES %s
+	`, strings.Repeat("(?, ?, ?, ?, ?),", len(historyRows)-1)+"(?, ?, ?, ?, ?)")
+
+	args := make([]any, 0)
+	for _, row := range historyRows {
+		args = append(args, row.createdOnDate, row.userId, row.roundId, row.rating, row.ratingChange)
+	}
+
+	return execute(sqlString, args...)
+}
@@ -0,0 +1,194 @@
+package database
+
+import (
+	"errors"
+	"log"
+)
+
+// StatRollupCheck compares one total counted from the raw logs with the same
+// total read from the daily stat rollups.
+type StatRollupCheck struct {
+	Name        string
+	LogCount    int
+	RollupCount int
+}
+
+func (statRollupCheck StatRollupCheck) IsConsistent() bool {
+	return statRollupCheck.LogCount == statRollupCheck.RollupCount
+}
+
+// UpdateStatRollups moves the days logged since the last update into the
+// daily stat rollups. An event does the same every hour, and the V_STAT_*_DAY
+// views add whatever was logged after it, so reads never need to call this.
+func UpdateStatRollups() error {
+	sqlString := "CALL SP_UPDATE_STAT_ROLLUPS ()"
+	return execute(sqlString)
+}
+
+// RebuildStatRollups backfills the daily stat rollups from the start of the
+// logs.
+func RebuildStatRollups() error {
+	sqlString := `
+		DELETE
+		FROM SETTING
+		WHERE NAME = 'STAT_ROLLUP_DAY'
+	`
+	err := execute(sqlString)
+	if err != nil {
+		return err
+	}
+
+	return UpdateStatRollups()
+}
+
+// CheckStatRollups counts every total again from the raw logs. The stat views
+// add the logs after the last update to the rollups, and each round is counted
+// on the day it started, so any difference means the rollups are wrong.
+func CheckStatRollups() ([]StatRollupCheck, error) {
+	result := make([]StatRollupCheck, 0)
+
+	sqlString := `
+		WITH LOG_USER AS (
+				SELECT
+					COUNT(DISTINCT LRC.PLAYER_USER_ID, LRC.ROUND_ID) AS ROUND_PLAY_COUNT,
+					COUNT(DISTINCT LRC.PLAYER_USER_ID, CASE WHEN LW.ID IS NOT NULL THEN LRC.ROUND_ID END) AS ROUND_WIN_COUNT,
+					COUNT(DISTINCT LRC.PLAYER_USER_ID, LRC.PLAYER_CARD_ID, CASE WHEN LW.ID IS NOT NULL THEN LRC.ROUND_ID END) AS CARD_WIN_COUNT
+				FROM LOG_RESPONSE_CARD AS LRC
+					LEFT JOIN LOG_WIN AS LW ON LW.RESPONSE_ID = LRC.RESPONSE_ID
+			),
+			ROLLUP_USER AS (
+				SELECT
+					COALESCE(SUM(ROUND_PLAY_COUNT), 0) AS ROUND_PLAY_COUNT,
+					COALESCE(SUM(ROUND_WIN_COUNT), 0) AS ROUND_WIN_COUNT,
+					COALESCE(SUM(RESPONSE_CARD_PLAY_COUNT), 0) AS RESPONSE_CARD_PLAY_COUNT,
+					COALESCE(SUM(RESPONSE_CARD_DISCARD_COUNT), 0) AS RESPONSE_CARD_DISCARD_COUNT,
+					COALESCE(SUM(PROMPT_CARD_SKIP_COUNT), 0) AS PROMPT_CARD_SKIP_COUNT,
+					COALESCE(SUM(KICK_COUNT), 0) AS KICK_COUNT,
+					COALESCE(SUM(FLIP_TABLE_COUNT), 0) AS FLIP_TABLE_COUNT
+				FROM V_STAT_USER_DAY
+			),
+			ROLLUP_CARD AS (
+				SELECT
+					COALESCE(SUM(RESPONSE_PLAY_COUNT), 0) AS RESPONSE_PLAY_COUNT,
+					COALESCE(SUM(DISCARD_COUNT), 0) AS DISCARD_COUNT,
+					COALESCE(SUM(SKIP_COUNT), 0) AS SKIP_COUNT
+				FROM V_STAT_CARD_DAY
+			),
+			ROLLUP_CREDITS AS (
+				SELECT
+					COALESCE(SUM(SPENT_AMOUNT), 0) AS SPENT_AMOUNT,
+					COALESCE(SUM(EARNED_AMOUNT), 0) AS EARNED_AMOUNT
+				FROM V_STAT_CREDITS_DAY
+			),
+			ROLLUP_SPECIAL AS (
+				SELECT
+					COALESCE(SUM(RESPONSE_PLAY_COUNT), 0) AS RESPONSE_PLAY_COUNT,
+					COALESCE(SUM(ROUND_PLAY_COUNT), 0) AS ROUND_PLAY_COUNT
+				FROM V_STAT_SPECIAL_DAY
+			)
+		SELECT
+			'Rounds Played',
+			(SELECT ROUND_PLAY_COUNT FROM LOG_USER),
+			(SELECT ROUND_PLAY_COUNT FROM ROLLUP_USER)
+		UNION ALL
+		SELECT
+			'Rounds Won',
+			(SELECT ROUND_WIN_COUNT FROM LOG_USER),
+			(SELECT ROUND_WIN_COUNT FROM ROLLUP_USER)
+		UNION ALL
+		SELECT
+			'Rounds Won (Picks)',
+			(SELECT ROUND_WIN_COUNT FROM LOG_USER),
+			(SELECT COALESCE(SUM(ROUND_WIN_COUNT), 0) FROM V_STAT_PICK_DAY)
+		UNION ALL
+		SELECT
+			'Rounds Won (Picked Cards)',
+			(SELECT CARD_WIN_COUNT FROM LOG_USER),
+			(SELECT COALESCE(SUM(ROUND_WIN_COUNT), 0) FROM V_STAT_PICK_CARD_DAY)
+		UNION ALL
+		SELECT
+			'Rounds Played (Special Categories)',
+			(SELECT COUNT(DISTINCT PLAYER_USER_ID, ROUND_ID, COALESCE(SPECIAL_CATEGORY, 'NONE')) FROM LOG_RESPONSE_CARD),
+			(SELECT ROUND_PLAY_COUNT FROM ROLLUP_SPECIAL)
+		UNION ALL
+		SELECT
+			'Response Cards Played',
+			(SELECT COUNT(*) FROM LOG_RESPONSE_CARD),
+			(SELECT RESPONSE_CARD_PLAY_COUNT FROM ROLLUP_USER)
+		UNION ALL
+		SELECT
+			'Response Cards Played (Cards)',
+			(SELECT COUNT(*) FROM LOG_RESPONSE_CARD),
+			(SELECT RESPONSE_PLAY_COUNT FROM ROLLUP_CARD)
+		UNION ALL
+		SELECT
+			'Response Cards Played (Special Categories)',
+			(SELECT COUNT(*) FROM LOG_RESPONSE_CARD),
+			(SELECT RESPONSE_PLAY_COUNT FROM ROLLUP_SPECIAL)
+		UNION ALL
+		SELECT
+			'Response Cards Discarded',
+			(SELECT COUNT(*) FROM LOG_DISCARD),
+			(SELECT RESPONSE_CARD_DISCARD_COUNT FROM ROLLUP_USER)
+		UNION ALL
+		SELECT
+			'Response Cards Discarded (Cards)',
+			(SELECT COUNT(*) FROM LOG_DISCARD),
+			(SELECT DISCARD_COUNT FROM ROLLUP_CARD)
+		UNION ALL
+		SELECT
+			'Prompt Cards Skipped',
+			(SELECT COUNT(*) FROM LOG_SKIP),
+			(SELECT PROMPT_CARD_SKIP_COUNT FROM ROLLUP_USER)
+		UNION ALL
+		SELECT
+			'Prompt Cards Skipped (Cards)',
+			(SELECT COUNT(*) FROM LOG_SKIP),
+			(SELECT SKIP_COUNT FROM ROLLUP_CARD)
+		UNION ALL
+		SELECT
+			'Credits Spent',
+			(SELECT COALESCE(SUM(AMOUNT), 0) FROM LOG_CREDITS_SPENT WHERE AMOUNT > 0),
+			(SELECT SPENT_AMOUNT FROM ROLLUP_CREDITS)
+		UNION ALL
+		SELECT
+			'Credits Earned',
+			(SELECT COALESCE(SUM(AMOUNT) * -1, 0) FROM LOG_CREDITS_SPENT WHERE AMOUNT < 0),
+			(SELECT EARNED_AMOUNT FROM ROLLUP_CREDITS)
+		UNION ALL
+		SELECT
+			'Kicks',
+			(SELECT COUNT(*) FROM LOG_KICK),
+			(SELECT KICK_COUNT FROM ROLLUP_USER)
+		UNION ALL
+		SELECT
+			'Flipped Tables',
+			(SELECT COUNT(*) FROM LOG_FLIP_TABLE),
+			(SELECT FLIP_TABLE_COUNT FROM ROLLUP_USER)
+		UNION ALL
+		SELECT
+			'Rated Rounds',
+			(SELECT COUNT(*) FROM USER_RATING_HISTORY),
+			(SELECT COALESCE(SUM(ROUND_COUNT), 0) FROM STAT_RATING_DAY)
+	`
+	rows, err := query(sqlString)
+	if err != nil {
+		return result, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var statRollupCheck StatRollupCheck
+		if err := rows.Scan(
+			&statRollupCheck.Name,
+			&statRollupCheck.LogCount,
+			&statRollupCheck.RollupCount,
+		); err != nil {
+			log.Println(err)
+			return result, errors.New("failed to scan row in query results")
+		}
+		result = append(result, statRollupCheck)
+	}
+
+	return result, nil
+}
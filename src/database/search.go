@@ -0,0 +1,327 @@
+package database
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// cardQuery is a parsed card search such as
+// deck:"Office" category:prompt has:image plays:>10 wins:0 "exact phrase" -excluded
+type cardQuery struct {
+	terms   []cardQueryTerm
+	filters []cardQueryFilter
+}
+
+type cardQueryTerm struct {
+	text    string
+	phrase  bool
+	negated bool
+}
+
+type cardQueryFilter struct {
+	key     string
+	value   string
+	negated bool
+}
+
+// cardQueryColumns names the columns to search, so review cards can be
+// searched the same way as cards.
+type cardQueryColumns struct {
+	cardId        string
+	deckId        string
+	category      string
+	text          string
+	youtube       string
+	image         string
+	contentRating string
+
+	// only CARD.TEXT has a full-text index
+	fullText bool
+}
+
+var cardColumns = cardQueryColumns{
+	cardId:        "C.ID",
+	deckId:        "C.DECK_ID",
+	category:      "C.CATEGORY",
+	text:          "C.TEXT",
+	youtube:       "C.YOUTUBE",
+	image:         "C.IMAGE",
+	contentRating: "C.CONTENT_RATING",
+	fullText:      true,
+}
+
+var reviewCardColumns = cardQueryColumns{
+	cardId:   "RC.CARD_ID",
+	deckId:   "RC.DECK_ID",
+	category: "RC.CATEGORY",
+	text:     "RC.TEXT",
+	youtube:  "RC.YOUTUBE",
+	image:    "RC.IMAGE",
+}
+
+var cardQueryFilterKeys = []string{"deck", "category", "has", "tag", "rating", "plays", "wins"}
+
+func parseCardQuery(text string) cardQuery {
+	var cardQuery cardQuery
+
+	runes := []rune(text)
+	i := 0
+
+	// readValue reads a quoted phrase or a single word
+	readValue := func() string {
+		if i < len(runes) && runes[i] == '"' {
+			end := i + 1
+			for end < len(runes) && runes[end] != '"' {
+				end++
+			}
+			value := string(runes[i+1 : end])
+			i = min(end+1, len(runes))
+			return value
+		}
+
+		start := i
+		for i < len(runes) && !unicode.IsSpace(runes[i]) {
+			i++
+		}
+		return string(runes[start:i])
+	}
+
+	for i < len(runes) {
+		if unicode.IsSpace(runes[i]) {
+			i++
+			continue
+		}
+
+		negated := false
+		if runes[i] == '-' && i+1 < len(runes) && !unicode.IsSpace(runes[i+1]) {
+			negated = true
+			i++
+		}
+
+		if runes[i] == '"' {
+			phrase := strings.TrimSpace(readValue())
+			if phrase != "" {
+				cardQuery.terms = append(cardQuery.terms, cardQueryTerm{text: phrase, phrase: true, negated: negated})
+			}
+			continue
+		}
+
+		start := i
+		for i < len(runes) && runes[i] != ':' && !unicode.IsSpace(runes[i]) {
+			i++
+		}
+		key := strings.ToLower(string(runes[start:i]))
+
+		if i < len(runes) && runes[i] == ':' && isCardQueryFilterKey(key) {
+			i++
+			value := strings.TrimSpace(readValue())
+			if value != "" {
+				cardQuery.filters = append(cardQuery.filters, cardQueryFilter{key: key, value: value, negated: negated})
+			}
+			continue
+		}
+
+		// not a filter, so the whole word is searched for
+		i = start
+		cardQuery.terms = append(cardQuery.terms, cardQueryTerm{text: readValue(), negated: negated})
+	}
+
+	return cardQuery
+}
+
+func (cardQuery cardQuery) isEmpty() bool {
+	return len(cardQuery.terms) == 0 && len(cardQuery.filters) == 0
+}
+
+// isPlain is true for a search of only words, with no phrases, exclusions or
+// filters, which the full-text index can answer on its own.
+func (cardQuery cardQuery) isPlain() bool {
+	if len(cardQuery.terms) == 0 || len(cardQuery.filters) > 0 {
+		return false
+	}
+
+	for _, term := range cardQuery.terms {
+		if term.phrase || term.negated {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isCardQueryFilterKey(key string) bool {
+	for _, filterKey := range cardQueryFilterKeys {
+		if key == filterKey {
+			return true
+		}
+	}
+	return false
+}
+
+// where builds the conditions for the query joined by AND, with user values
+// always passed as parameters.
+func (cardQuery cardQuery) where(columns cardQueryColumns) (string, []any) {
+	conditions := make([]string, 0)
+	args := make([]any, 0)
+
+	addCondition := func(condition string, negated bool, conditionArgs ...any) {
+		if negated {
+			condition = "NOT (" + condition + ")"
+		}
+		conditions = append(conditions, condition)
+		args = append(args, conditionArgs...)
+	}
+
+	for _, term := range cardQuery.terms {
+		addCondition(columns.text+" LIKE ?", term.negated, "%"+escapeLike(term.text)+"%")
+	}
+
+	for _, filter := range cardQuery.filters {
+		switch filter.key {
+		case "deck":
+			addCondition(columns.deckId+" IN (SELECT ID FROM DECK WHERE NAME LIKE ?)", filter.negated, "%"+escapeLike(filter.value)+"%")
+		case "category":
+			addCondition(columns.category+" = ?", filter.negated, strings.ToUpper(filter.value))
+		case "tag":
+			tags := ParseTags(filter.value)
+			if len(tags) != 1 {
+				addCondition("1 = 0", false)
+				continue
+			}
+			addCondition("EXISTS (SELECT 1 FROM CARD_TAG WHERE CARD_ID = "+columns.cardId+" AND TAG = ?)", filter.negated, tags[0])
+		case "rating":
+			if columns.contentRating == "" {
+				addCondition("1 = 0", false)
+				continue
+			}
+			addCondition(columns.contentRating+" = ?", filter.negated, strings.ToUpper(filter.value))
+		case "has":
+			switch strings.ToLower(filter.value) {
+			case "image":
+				addCondition(columns.image+" IS NOT NULL", filter.negated)
+			case "youtube":
+				addCondition(columns.youtube+" IS NOT NULL", filter.negated)
+			case "tag", "tags":
+				addCondition("EXISTS (SELECT 1 FROM CARD_TAG WHERE CARD_ID = "+columns.cardId+")", filter.negated)
+			case "rating":
+				if columns.contentRating == "" {
+					addCondition("1 = 0", filter.negated)
+					continue
+				}
+				addCondition(columns.contentRating+" IS NOT NULL", filter.negated)
+			default:
+				addCondition("1 = 0", false)
+			}
+		case "plays", "wins":
+			operator, count, ok := parseCardQueryComparison(filter.value)
+			if !ok {
+				addCondition("1 = 0", false)
+				continue
+			}
+
+			// the counts are read once for every card rather than once per row
+			countSql := `
+				SELECT
+					CARD_ID
+				FROM (
+						SELECT
+							ID,
+							JUDGE_CARD_ID AS CARD_ID
+						FROM LOG_RESPONSE_CARD
+						UNION ALL
+						SELECT
+							ID,
+							PLAYER_CARD_ID AS CARD_ID
+						FROM LOG_RESPONSE_CARD
+					) AS T
+				WHERE CARD_ID IS NOT NULL
+				GROUP BY CARD_ID
+				HAVING `
+			countColumn := "COUNT(DISTINCT ID)"
+			if filter.key == "wins" {
+				countSql = `
+				SELECT
+					LRC.PLAYER_CARD_ID
+				FROM LOG_RESPONSE_CARD AS LRC
+					INNER JOIN LOG_WIN AS LW ON LW.RESPONSE_ID = LRC.RESPONSE_ID
+				WHERE LRC.PLAYER_CARD_ID IS NOT NULL
+				GROUP BY LRC.PLAYER_CARD_ID
+				HAVING `
+				countColumn = "COUNT(DISTINCT LW.ID)"
+			}
+
+			// cards never played have no count, so when a count of zero
+			// matches they are found by leaving out the counts that do not
+			if compareCardQueryCount(0, operator, count) {
+				addCondition(columns.cardId+" NOT IN ("+countSql+"NOT ("+countColumn+" "+operator+" ?))", filter.negated, count)
+			} else {
+				addCondition(columns.cardId+" IN ("+countSql+countColumn+" "+operator+" ?)", filter.negated, count)
+			}
+		}
+	}
+
+	if len(conditions) == 0 {
+		return "1 = 1", args
+	}
+
+	return strings.Join(conditions, "\n\t\t\tAND "), args
+}
+
+// relevance scores how well the text matches the searched words, or 0 when
+// there is nothing to rank by.
+func (cardQuery cardQuery) relevance(columns cardQueryColumns) (string, []any) {
+	words := make([]string, 0)
+	for _, term := range cardQuery.terms {
+		if !term.negated {
+			words = append(words, term.text)
+		}
+	}
+
+	if !columns.fullText || len(words) == 0 {
+		return "0", nil
+	}
+
+	return "MATCH (" + columns.text + ") AGAINST (? IN NATURAL LANGUAGE MODE)", []any{strings.Join(words, " ")}
+}
+
+// parseCardQueryComparison reads values like >10, <=3 or 0, returning an
+// operator that is safe to put in the SQL.
+func parseCardQueryComparison(value string) (string, int, bool) {
+	operator := "="
+	for _, prefix := range []string{">=", "<=", ">", "<", "="} {
+		if strings.HasPrefix(value, prefix) {
+			operator = prefix
+			value = strings.TrimPrefix(value, prefix)
+			break
+		}
+	}
+
+	count, err := strconv.Atoi(value)
+	if err != nil {
+		return "", 0, false
+	}
+
+	return operator, count, true
+}
+
+func compareCardQueryCount(value int, operator string, count int) bool {
+	switch operator {
+	case ">=":
+		return value >= count
+	case "<=":
+		return value <= count
+	case ">":
+		return value > count
+	case "<":
+		return value < count
+	default:
+		return value == count
+	}
+}
+
+// escapeLike stops the wildcards in user text, like the underscores of a
+// blank, from matching any character.
+func escapeLike(text string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(text)
+}
@@ -0,0 +1,155 @@
+package database
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseCardQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		want  cardQuery
+		plain bool
+	}{
+		{
+			name: "empty",
+			text: "   ",
+			want: cardQuery{},
+		},
+		{
+			name: "words",
+			text: "funny  cat",
+			want: cardQuery{
+				terms: []cardQueryTerm{
+					{text: "funny"},
+					{text: "cat"},
+				},
+			},
+			plain: true,
+		},
+		{
+			name: "phrase",
+			text: `"exact phrase"`,
+			want: cardQuery{
+				terms: []cardQueryTerm{
+					{text: "exact phrase", phrase: true},
+				},
+			},
+		},
+		{
+			name: "unclosed phrase",
+			text: `"exact phrase`,
+			want: cardQuery{
+				terms: []cardQueryTerm{
+					{text: "exact phrase", phrase: true},
+				},
+			},
+		},
+		{
+			name: "negated word and phrase",
+			text: `-dog -"hot dog"`,
+			want: cardQuery{
+				terms: []cardQueryTerm{
+					{text: "dog", negated: true},
+					{text: "hot dog", phrase: true, negated: true},
+				},
+			},
+		},
+		{
+			name: "lone dash",
+			text: "cat - dog",
+			want: cardQuery{
+				terms: []cardQueryTerm{
+					{text: "cat"},
+					{text: "-"},
+					{text: "dog"},
+				},
+			},
+			plain: true,
+		},
+		{
+			name: "filters",
+			text: `deck:"The Office" Category:prompt has:image plays:>10 -wins:0`,
+			want: cardQuery{
+				filters: []cardQueryFilter{
+					{key: "deck", value: "The Office"},
+					{key: "category", value: "prompt"},
+					{key: "has", value: "image"},
+					{key: "plays", value: ">10"},
+					{key: "wins", value: "0", negated: true},
+				},
+			},
+		},
+		{
+			name: "unknown key is a word",
+			text: "time:12:30",
+			want: cardQuery{
+				terms: []cardQueryTerm{
+					{text: "time:12:30"},
+				},
+			},
+			plain: true,
+		},
+		{
+			name: "filter without value",
+			text: "tag: cat",
+			want: cardQuery{
+				terms: []cardQueryTerm{
+					{text: "cat"},
+				},
+			},
+			plain: true,
+		},
+		{
+			name: "filter and words",
+			text: "tag:animal big cat",
+			want: cardQuery{
+				terms: []cardQueryTerm{
+					{text: "big"},
+					{text: "cat"},
+				},
+				filters: []cardQueryFilter{
+					{key: "tag", value: "animal"},
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := parseCardQuery(test.text)
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("parseCardQuery(%q) = %+v, want %+v", test.text, got, test.want)
+			}
+			if got.isPlain() != test.plain {
+				t.Errorf("parseCardQuery(%q).isPlain() = %v, want %v", test.text, got.isPlain(), test.plain)
+			}
+		})
+	}
+}
+
+func TestParseCardQueryComparison(t *testing.T) {
+	tests := []struct {
+		value    string
+		operator string
+		count    int
+		ok       bool
+	}{
+		{value: "3", operator: "=", count: 3, ok: true},
+		{value: ">10", operator: ">", count: 10, ok: true},
+		{value: ">=10", operator: ">=", count: 10, ok: true},
+		{value: "<=0", operator: "<=", count: 0, ok: true},
+		{value: "<", ok: false},
+		{value: "many", ok: false},
+		{value: "=>1", ok: false},
+	}
+
+	for _, test := range tests {
+		operator, count, ok := parseCardQueryComparison(test.value)
+		if operator != test.operator || count != test.count || ok != test.ok {
+			t.Errorf("parseCardQueryComparison(%q) = %q, %d, %v, want %q, %d, %v",
+				test.value, operator, count, ok, test.operator, test.count, test.ok)
+		}
+	}
+}
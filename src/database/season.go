@@ -0,0 +1,532 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Season is an admin defined competition. Both dates are whole days and the
+// end date is inclusive.
+type Season struct {
+	Id             uuid.UUID
+	Name           string
+	StartDate      time.Time
+	EndDate        time.Time
+	ArchivedOnDate sql.NullTime
+
+	// Upcoming, Current, Ended or Archived, from the database clock
+	Status string
+}
+
+// SeasonStanding is one player's place in a season. Archived standings are
+// copied out of the logs, so they keep the name the player had at the time.
+type SeasonStanding struct {
+	Ranking        int
+	UserName       string
+	Rating         int
+	RoundPlayCount int
+	RoundWinCount  int
+	GamePlayCount  int
+	GameWinCount   int
+}
+
+// only one archive can run at a time, or both could copy the same standings
+var seasonMutex sync.Mutex
+
+func (season Season) IsArchived() bool {
+	return season.ArchivedOnDate.Valid
+}
+
+// ToDate is the exclusive end of the season, for filters on timestamps.
+func (season Season) ToDate() time.Time {
+	return season.EndDate.AddDate(0, 0, 1)
+}
+
+func GetSeasons() ([]Season, error) {
+	sqlString := `
+		SELECT
+			ID,
+			NAME,
+			START_DATE,
+			END_DATE,
+			ARCHIVED_ON_DATE,
+			STATUS
+		FROM V_SEASON
+		ORDER BY START_DATE DESC
+	`
+	rows, err := query(sqlString)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]Season, 0)
+	for rows.Next() {
+		var season Season
+		if err := rows.Scan(
+			&season.Id,
+			&season.Name,
+			&season.StartDate,
+			&season.EndDate,
+			&season.ArchivedOnDate,
+			&season.Status,
+		); err != nil {
+			log.Println(err)
+			return nil, errors.New("failed to scan row in query results")
+		}
+		result = append(result, season)
+	}
+	return result, nil
+}
+
+func GetSeason(seasonId uuid.UUID) (Season, error) {
+	return getSeason("ID = ?", seasonId)
+}
+
+// GetCurrentSeason returns a season with a nil id when no season is running.
+func GetCurrentSeason() (Season, error) {
+	return getSeason("STATUS = 'Current'")
+}
+
+func getSeason(where string, params ...any) (Season, error) {
+	var season Season
+
+	sqlString := fmt.Sprintf(`
+		SELECT
+			ID,
+			NAME,
+			START_DATE,
+			END_DATE,
+			ARCHIVED_ON_DATE,
+			STATUS
+		FROM V_SEASON
+		WHERE %s
+		ORDER BY START_DATE DESC
+		LIMIT 1
+	`, where)
+	rows, err := query(sqlString, params...)
+	if err != nil {
+		return season, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err := rows.Scan(
+			&season.Id,
+			&season.Name,
+			&season.StartDate,
+			&season.EndDate,
+			&season.ArchivedOnDate,
+			&season.Status,
+		); err != nil {
+			log.Println(err)
+			return season, errors.New("failed to scan row in query results")
+		}
+	}
+
+	return season, nil
+}
+
+// CountOverlappingSeasons counts the seasons sharing a day with the dates,
+// since only one season can be current at a time.
+func CountOverlappingSeasons(startDate time.Time, endDate time.Time) (int, error) {
+	sqlString := `
+		SELECT
+			COUNT(*)
+		FROM SEASON
+		WHERE START_DATE <= ?
+			AND END_DATE >= ?
+	`
+	rows, err := query(sqlString, endDate, startDate)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	var count int
+	for rows.Next() {
+		if err := rows.Scan(&count); err != nil {
+			log.Println(err)
+			return 0, errors.New("failed to scan row in query results")
+		}
+	}
+
+	return count, nil
+}
+
+func CreateSeason(name string, startDate time.Time, endDate time.Time) (uuid.UUID, error) {
+	id, err := uuid.NewUUID()
+	if err != nil {
+		log.Println(err)
+		return id, errors.New("failed to generate new id")
+	}
+
+	sqlString := `
+		INSERT INTO SEASON(ID, NAME, START_DATE, END_DATE)
+		VALUES (?, ?, ?, ?)
+	`
+	err = execute(sqlString, id, name, startDate, endDate)
+	if err != nil {
+		return id, err
+	}
+
+	// a season can start in the past, after its first rounds were rated
+	ratingMutex.Lock()
+	defer ratingMutex.Unlock()
+
+	return id, updateSeasonRatings(Season{Id: id, StartDate: startDate, EndDate: endDate})
+}
+
+func DeleteSeason(seasonId uuid.UUID) error {
+	sqlString := `
+		DELETE
+		FROM SEASON
+		WHERE ID = ?
+	`
+	return execute(sqlString, seasonId)
+}
+
+// ArchiveSeasons snapshots the final standings of every season that has ended
+// since the last archive. It runs in the background every hour. The season
+// ratings are brought up to date first, so the last day of the season is
+// counted.
+func ArchiveSeasons() error {
+	seasonMutex.Lock()
+	defer seasonMutex.Unlock()
+
+	sqlString := `
+		SELECT
+			ID,
+			NAME,
+			START_DATE,
+			END_DATE,
+			ARCHIVED_ON_DATE,
+			STATUS
+		FROM V_SEASON
+		WHERE STATUS = 'Ended'
+		ORDER BY START_DATE
+	`
+	rows, err := query(sqlString)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	seasons := make([]Season, 0)
+	for rows.Next() {
+		var season Season
+		if err := rows.Scan(
+			&season.Id,
+			&season.Name,
+			&season.StartDate,
+			&season.EndDate,
+			&season.ArchivedOnDate,
+			&season.Status,
+		); err != nil {
+			log.Println(err)
+			return errors.New("failed to scan row in query results")
+		}
+		seasons = append(seasons, season)
+	}
+
+	if len(seasons) == 0 {
+		return nil
+	}
+
+	ratingMutex.Lock()
+	defer ratingMutex.Unlock()
+
+	err = updateUserRatings()
+	if err != nil {
+		return err
+	}
+
+	for _, season := range seasons {
+		err = updateSeasonRatings(season)
+		if err != nil {
+			return err
+		}
+
+		err = archiveSeason(season)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// updateSeasonRatings rates the rounds of the season again starting from the
+// initial rating, so everyone starts the season even. The caller must hold
+// ratingMutex.
+func updateSeasonRatings(season Season) error {
+	// getRatingRounds only returns rounds after the date
+	rounds, err := getRatingRounds(season.StartDate.Add(-time.Microsecond))
+	if err != nil {
+		return err
+	}
+
+	ratings := make(map[uuid.UUID]float64)
+	roundCounts := make(map[uuid.UUID]int)
+	for _, round := range rounds {
+		if !round.createdOnDate.Before(season.ToDate()) {
+			break
+		}
+
+		for userId, change := range getRoundRatingChanges(round, ratings) {
+			rating, ok := ratings[userId]
+			if !ok {
+				rating = initialRating
+			}
+			ratings[userId] = rating + change
+			roundCounts[userId]++
+		}
+	}
+
+	return transaction(func(tx *sql.Tx) error {
+		sqlString := `
+			DELETE
+			FROM SEASON_RATING
+			WHERE SEASON_ID = ?
+		`
+		_, err := executeTx(tx, sqlString, season.Id)
+		if err != nil {
+			return err
+		}
+
+		if len(ratings) == 0 {
+			return nil
+		}
+
+		sqlString = fmt.Sprintf(`
+			INSERT INTO SEASON_RATING(SEASON_ID, USER_ID, RATING, ROUND_COUNT)
+			VALUES %s
+		`, strings.Repeat("(?, ?, ?, ?),", len(ratings)-1)+"(?, ?, ?, ?)")
+
+		args := make([]any, 0)
+		for userId, rating := range ratings {
+			args = append(args, season.Id, userId, rating, roundCounts[userId])
+		}
+
+		_, err = executeTx(tx, sqlString, args...)
+		return err
+	})
+}
+
+// getUnarchivedSeasons finds the seasons not yet archived that share a day
+// with the dates, whose ratings change when rounds in between are rated.
+func getUnarchivedSeasons(fromDate time.Time, toDate time.Time) ([]Season, error) {
+	sqlString := `
+		SELECT
+			ID,
+			NAME,
+			START_DATE,
+			END_DATE,
+			ARCHIVED_ON_DATE,
+			STATUS
+		FROM V_SEASON
+		WHERE ARCHIVED_ON_DATE IS NULL
+			AND START_DATE <= DATE(?)
+			AND END_DATE >= DATE(?)
+	`
+	rows, err := query(sqlString, toDate, fromDate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]Season, 0)
+	for rows.Next() {
+		var season Season
+		if err := rows.Scan(
+			&season.Id,
+			&season.Name,
+			&season.StartDate,
+			&season.EndDate,
+			&season.ArchivedOnDate,
+			&season.Status,
+		); err != nil {
+			log.Println(err)
+			return nil, errors.New("failed to scan row in query results")
+		}
+		result = append(result, season)
+	}
+	return result, nil
+}
+
+// archiveSeason clears any standings left by an archive that failed part way
+// through before copying them again, so it is safe to repeat.
+func archiveSeason(season Season) error {
+	sqlString := `
+		DELETE
+		FROM SEASON_STANDING
+		WHERE SEASON_ID = ?
+	`
+	err := execute(sqlString, season.Id)
+	if err != nil {
+		return err
+	}
+
+	standingsSqlString, standingsParams := getSeasonStandingsQuery(season)
+	sqlString = fmt.Sprintf(`
+		INSERT INTO SEASON_STANDING(
+			SEASON_ID,
+			RANKING,
+			USER_ID,
+			USER_NAME,
+			RATING,
+			ROUND_PLAY_COUNT,
+			ROUND_WIN_COUNT,
+			GAME_PLAY_COUNT,
+			GAME_WIN_COUNT
+		)
+		SELECT
+			?,
+			RANKING,
+			USER_ID,
+			USER_NAME,
+			RATING,
+			ROUND_PLAY_COUNT,
+			ROUND_WIN_COUNT,
+			GAME_PLAY_COUNT,
+			GAME_WIN_COUNT
+		FROM (%s) AS STANDINGS
+	`, standingsSqlString)
+	err = execute(sqlString, append([]any{season.Id}, standingsParams...)...)
+	if err != nil {
+		return err
+	}
+
+	sqlString = `
+		UPDATE SEASON
+		SET ARCHIVED_ON_DATE = CURRENT_TIMESTAMP(6)
+		WHERE ID = ?
+	`
+	return execute(sqlString, season.Id)
+}
+
+// GetSeasonStandings reads the snapshot of an archived season, and counts the
+// standings from the logs for any other season.
+func GetSeasonStandings(season Season) ([]SeasonStanding, error) {
+	var sqlString string
+	var params []any
+	if season.IsArchived() {
+		sqlString = `
+			SELECT
+				RANKING,
+				USER_NAME,
+				RATING,
+				ROUND_PLAY_COUNT,
+				ROUND_WIN_COUNT,
+				GAME_PLAY_COUNT,
+				GAME_WIN_COUNT
+			FROM SEASON_STANDING
+			WHERE SEASON_ID = ?
+			ORDER BY RANKING
+		`
+		params = []any{season.Id}
+	} else {
+		standingsSqlString, standingsParams := getSeasonStandingsQuery(season)
+		sqlString = fmt.Sprintf(`
+			SELECT
+				RANKING,
+				USER_NAME,
+				RATING,
+				ROUND_PLAY_COUNT,
+				ROUND_WIN_COUNT,
+				GAME_PLAY_COUNT,
+				GAME_WIN_COUNT
+			FROM (%s) AS STANDINGS
+			ORDER BY RANKING
+		`, standingsSqlString)
+		params = standingsParams
+	}
+
+	rows, err := query(sqlString, params...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]SeasonStanding, 0)
+	for rows.Next() {
+		var standing SeasonStanding
+		if err := rows.Scan(
+			&standing.Ranking,
+			&standing.UserName,
+			&standing.Rating,
+			&standing.RoundPlayCount,
+			&standing.RoundWinCount,
+			&standing.GamePlayCount,
+			&standing.GameWinCount,
+		); err != nil {
+			log.Println(err)
+			return nil, errors.New("failed to scan row in query results")
+		}
+		result = append(result, standing)
+	}
+	return result, nil
+}
+
+// getSeasonStandingsQuery ranks everyone who played a round in the season by
+// their season rating, the same one the season leaderboard shows.
+func getSeasonStandingsQuery(season Season) (string, []any) {
+	sqlString := fmt.Sprintf(`
+		SELECT
+			ROW_NUMBER() OVER (ORDER BY RATING DESC, ROUND_WIN_COUNT DESC, USER_NAME ASC) AS RANKING,
+			USER_ID,
+			USER_NAME,
+			RATING,
+			ROUND_PLAY_COUNT,
+			ROUND_WIN_COUNT,
+			GAME_PLAY_COUNT,
+			GAME_WIN_COUNT
+		FROM (
+				SELECT
+					U.ID AS USER_ID,
+					U.NAME AS USER_NAME,
+					ROUND(COALESCE(SR.RATING, %f)) AS RATING,
+					SUD.ROUND_PLAY_COUNT,
+					SUD.ROUND_WIN_COUNT,
+					SUD.GAME_PLAY_COUNT,
+					COALESCE(GW.GAME_WIN_COUNT, 0) AS GAME_WIN_COUNT
+				FROM USER AS U
+					INNER JOIN (
+						SELECT
+							USER_ID,
+							SUM(ROUND_PLAY_COUNT) AS ROUND_PLAY_COUNT,
+							SUM(ROUND_WIN_COUNT) AS ROUND_WIN_COUNT,
+							COUNT(DISTINCT CASE WHEN RESPONSE_CARD_PLAY_COUNT > 0 THEN LOBBY_ID END) AS GAME_PLAY_COUNT
+						FROM V_STAT_USER_DAY
+						WHERE DAY >= ?
+							AND DAY < ?
+						GROUP BY USER_ID
+						HAVING SUM(ROUND_PLAY_COUNT) > 0
+					) AS SUD ON SUD.USER_ID = U.ID
+					LEFT JOIN SEASON_RATING AS SR ON SR.USER_ID = U.ID
+						AND SR.SEASON_ID = ?
+					LEFT JOIN (
+						SELECT
+							USER_ID,
+							COUNT(DISTINCT LOBBY_ID) AS GAME_WIN_COUNT
+						FROM V_STAT_GAME_WINNER
+						WHERE DAY >= ?
+							AND DAY < ?
+						GROUP BY USER_ID
+					) AS GW ON GW.USER_ID = U.ID
+			) AS T
+	`, initialRating)
+	params := []any{
+		season.StartDate, season.ToDate(),
+		season.Id,
+		season.StartDate, season.ToDate(),
+	}
+	return sqlString, params
+}
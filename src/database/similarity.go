@@ -0,0 +1,336 @@
+package database
+
+import (
+	"errors"
+	"log"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+type SimilarCard struct {
+	Id       uuid.UUID
+	DeckId   uuid.UUID
+	DeckName string
+	Category string
+	Text     string
+
+	// trigram similarity to the card it was compared with, from 0 to 1
+	Similarity float64
+}
+
+type NearDuplicateCards struct {
+	Card  SimilarCard
+	Match SimilarCard
+}
+
+// cards at least this similar are treated as the same joke in different wording
+const nearDuplicateThreshold = 0.7
+
+// cards compared on create, taken from the full text index by relevance
+const similarCardCandidateLimit = 100
+
+func (similarCard SimilarCard) SimilarityPercent() int {
+	return int(math.Round(similarCard.Similarity * 100))
+}
+
+// GetSimilarCards finds cards in decks the user can read that are near
+// duplicates of the text, most similar first. Near duplicates share most of
+// their words, so only the best full text matches are compared.
+func GetSimilarCards(userId uuid.UUID, category string, text string) ([]SimilarCard, error) {
+	words := normalizeCardText(text)
+	if words == "" {
+		return make([]SimilarCard, 0), nil
+	}
+
+	readableDecks, err := GetReadableDecks(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(readableDecks) == 0 {
+		return make([]SimilarCard, 0), nil
+	}
+
+	params := []any{category}
+	for _, deck := range readableDecks {
+		params = append(params, deck.Id)
+	}
+	params = append(params, words, words, similarCardCandidateLimit)
+
+	sqlString := `
+		SELECT
+			C.ID,
+			C.DECK_ID,
+			D.NAME AS DECK_NAME,
+			C.CATEGORY,
+			C.TEXT
+		FROM CARD AS C
+			INNER JOIN DECK AS D ON D.ID = C.DECK_ID
+		WHERE C.CATEGORY = ?
+			AND C.DECK_ID IN (` + strings.Repeat("?, ", len(readableDecks)-1) + `?)
+			AND MATCH (C.TEXT) AGAINST (? IN NATURAL LANGUAGE MODE)
+		ORDER BY MATCH (C.TEXT) AGAINST (? IN NATURAL LANGUAGE MODE) DESC
+		LIMIT ?
+	`
+	cards, err := getSimilarityCards(sqlString, params...)
+	if err != nil {
+		return nil, err
+	}
+
+	trigrams := getCardTrigrams(text)
+
+	result := make([]SimilarCard, 0)
+	for _, card := range cards {
+		card.Similarity = getTrigramSimilarity(trigrams, getCardTrigrams(card.Text))
+		if card.Similarity >= nearDuplicateThreshold {
+			result = append(result, card)
+		}
+	}
+
+	slices.SortFunc(result, func(a, b SimilarCard) int {
+		if a.Similarity > b.Similarity {
+			return -1
+		}
+		if a.Similarity < b.Similarity {
+			return 1
+		}
+		return strings.Compare(a.DeckName, b.DeckName)
+	})
+
+	return result[:min(len(result), 5)], nil
+}
+
+// GetNearDuplicateCards compares every card across all decks, most similar
+// pairs first.
+func GetNearDuplicateCards(limit int) ([]NearDuplicateCards, error) {
+	sqlString := `
+		SELECT
+			C.ID,
+			C.DECK_ID,
+			D.NAME AS DECK_NAME,
+			C.CATEGORY,
+			C.TEXT
+		FROM CARD AS C
+			INNER JOIN DECK AS D ON D.ID = C.DECK_ID
+		ORDER BY C.CREATED_ON_DATE
+	`
+	cards, err := getSimilarityCards(sqlString)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]NearDuplicateCards, 0)
+	for _, pair := range findNearDuplicatePairs(cards) {
+		match := cards[pair.j]
+		match.Similarity = pair.similarity
+		result = append(result, NearDuplicateCards{
+			Card:  cards[pair.i],
+			Match: match,
+		})
+	}
+
+	slices.SortStableFunc(result, func(a, b NearDuplicateCards) int {
+		if a.Match.Similarity > b.Match.Similarity {
+			return -1
+		}
+		if a.Match.Similarity < b.Match.Similarity {
+			return 1
+		}
+		return 0
+	})
+
+	return result[:min(len(result), limit)], nil
+}
+
+// removeNearDuplicatesFromDrawPile keeps the first card of every group of
+// near duplicates in the lobby, counting cards already in player hands.
+func removeNearDuplicatesFromDrawPile(lobbyId uuid.UUID) error {
+	sqlString := `
+		SELECT
+			C.ID,
+			C.DECK_ID,
+			'' AS DECK_NAME,
+			C.CATEGORY,
+			C.TEXT
+		FROM (
+				SELECT
+					0 AS IS_DRAW_PILE,
+					H.CREATED_ON_DATE,
+					H.CARD_ID
+				FROM HAND AS H
+					INNER JOIN PLAYER AS P ON P.ID = H.PLAYER_ID
+				WHERE P.LOBBY_ID = ?
+				UNION ALL
+				SELECT
+					1 AS IS_DRAW_PILE,
+					DP.CREATED_ON_DATE,
+					DP.CARD_ID
+				FROM DRAW_PILE AS DP
+				WHERE DP.LOBBY_ID = ?
+			) AS LC
+			INNER JOIN CARD AS C ON C.ID = LC.CARD_ID
+		ORDER BY LC.IS_DRAW_PILE,
+			LC.CREATED_ON_DATE
+	`
+	cards, err := getSimilarityCards(sqlString, lobbyId, lobbyId)
+	if err != nil {
+		return err
+	}
+
+	removed := make([]bool, len(cards))
+	for _, pair := range findNearDuplicatePairs(cards) {
+		if !removed[pair.i] {
+			removed[pair.j] = true
+		}
+	}
+
+	sqlString = `
+		DELETE
+		FROM DRAW_PILE
+		WHERE LOBBY_ID = ?
+			AND CARD_ID = ?
+	`
+	for i, card := range cards {
+		if !removed[i] {
+			continue
+		}
+
+		err = execute(sqlString, lobbyId, card.Id)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func getSimilarityCards(sqlString string, args ...any) ([]SimilarCard, error) {
+	rows, err := query(sqlString, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]SimilarCard, 0)
+	for rows.Next() {
+		var card SimilarCard
+		if err := rows.Scan(
+			&card.Id,
+			&card.DeckId,
+			&card.DeckName,
+			&card.Category,
+			&card.Text,
+		); err != nil {
+			log.Println(err)
+			return nil, errors.New("failed to scan row in query results")
+		}
+		result = append(result, card)
+	}
+	return result, nil
+}
+
+type nearDuplicatePair struct {
+	i          int
+	j          int
+	similarity float64
+}
+
+// findNearDuplicatePairs returns index pairs (i < j) of cards in the same
+// category that meet the threshold. Trigrams are ordered rarest first, and
+// two sets can only be similar enough if they share one of the rare trigrams
+// at the start of either set, so only those are indexed.
+func findNearDuplicatePairs(cards []SimilarCard) []nearDuplicatePair {
+	trigramSets := make([]map[string]bool, len(cards))
+	frequencies := make(map[string]int)
+	for i, card := range cards {
+		trigramSets[i] = getCardTrigrams(card.Text)
+		for trigram := range trigramSets[i] {
+			frequencies[trigram]++
+		}
+	}
+
+	index := make(map[string][]int)
+	result := make([]nearDuplicatePair, 0)
+	for j, card := range cards {
+		trigrams := make([]string, 0, len(trigramSets[j]))
+		for trigram := range trigramSets[j] {
+			trigrams = append(trigrams, trigram)
+		}
+		slices.SortFunc(trigrams, func(a, b string) int {
+			if frequencies[a] != frequencies[b] {
+				return frequencies[a] - frequencies[b]
+			}
+			return strings.Compare(a, b)
+		})
+
+		prefixLength := len(trigrams) - int(math.Ceil(nearDuplicateThreshold*float64(len(trigrams)))) + 1
+		prefix := trigrams[:min(prefixLength, len(trigrams))]
+
+		compared := make(map[int]bool)
+		for _, trigram := range prefix {
+			for _, i := range index[trigram] {
+				if compared[i] || cards[i].Category != card.Category {
+					continue
+				}
+				compared[i] = true
+
+				similarity := getTrigramSimilarity(trigramSets[i], trigramSets[j])
+				if similarity >= nearDuplicateThreshold {
+					result = append(result, nearDuplicatePair{i: i, j: j, similarity: similarity})
+				}
+			}
+		}
+
+		for _, trigram := range prefix {
+			index[trigram] = append(index[trigram], j)
+		}
+	}
+
+	slices.SortFunc(result, func(a, b nearDuplicatePair) int {
+		if a.i != b.i {
+			return a.i - b.i
+		}
+		return a.j - b.j
+	})
+
+	return result
+}
+
+// normalizeCardText lower cases the text and reduces it to words, so case,
+// punctuation and blanks do not count as differences.
+func normalizeCardText(text string) string {
+	text = strings.ReplaceAll(strings.ToLower(text), promptBlank, " ")
+	return strings.TrimSpace(tagInvalidRegExp.ReplaceAllString(text, " "))
+}
+
+// getCardTrigrams gets the set of three letter sequences in the normalized
+// text, padding each word so short words still count.
+func getCardTrigrams(text string) map[string]bool {
+	result := make(map[string]bool)
+	for _, word := range strings.Fields(normalizeCardText(text)) {
+		padded := []rune(" " + word + " ")
+		for i := 0; i+3 <= len(padded); i++ {
+			result[string(padded[i:i+3])] = true
+		}
+	}
+	return result
+}
+
+// getTrigramSimilarity is the Jaccard index of the two trigram sets.
+func getTrigramSimilarity(a map[string]bool, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+
+	shared := 0
+	for trigram := range a {
+		if b[trigram] {
+			shared++
+		}
+	}
+
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
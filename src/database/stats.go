@@ -5,64 +5,103 @@ import (
 	"fmt"
 	"log"
 	"reflect"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 type StatUser struct {
-	UserName string
+	UserName string `json:"user_name"`
 
-	GamePlayCount            int
-	GameWinCount             int
-	RoundPlayCount           int
-	RoundWinCount            int
-	ResponseCardPlayCount    int
-	ResponseCardDiscardCount int
-	PromptCardPlayCount      int
-	PromptCardSkipCount      int
-	CreditsSpentCount        int
-	CreditsEarnedCount       int
-	LobbyKickCount           int
-	FlipTableCount           int
+	GamePlayCount            int `json:"game_play_count"`
+	GameWinCount             int `json:"game_win_count"`
+	RoundPlayCount           int `json:"round_play_count"`
+	RoundWinCount            int `json:"round_win_count"`
+	ResponseCardPlayCount    int `json:"response_card_play_count"`
+	ResponseCardDiscardCount int `json:"response_card_discard_count"`
+	PromptCardPlayCount      int `json:"prompt_card_play_count"`
+	PromptCardSkipCount      int `json:"prompt_card_skip_count"`
+	CreditsSpentCount        int `json:"credits_spent_count"`
+	CreditsEarnedCount       int `json:"credits_earned_count"`
+	LobbyKickCount           int `json:"lobby_kick_count"`
+	FlipTableCount           int `json:"flip_table_count"`
 }
 
-type Achievement struct {
-	Name     string
-	Progress int
+type StatCard struct {
+	DeckName string `json:"deck_name"`
+	Category string `json:"category"`
+	Text     string `json:"text"`
+
+	PlayCount    int `json:"play_count"`
+	WinCount     int `json:"win_count"`
+	DiscardCount int `json:"discard_count"`
+	SkipCount    int `json:"skip_count"`
 }
 
-type StatCard struct {
-	DeckName string
-	Category string
-	Text     string
+// LeaderboardFilter narrows the rows counted on a leaderboard. Every value is
+// passed to the database as a parameter.
+type LeaderboardFilter struct {
+	// zero dates are unbounded, ToDate is exclusive
+	FromDate time.Time
+	ToDate   time.Time
+
+	// ratings start over each season, so a season shows the season rating
+	// instead of the current rating, nil when not filtering on a season
+	SeasonId uuid.UUID
+
+	// lobbies are matched by name, so every lobby ever given the name counts
+	LobbyName string
+	UserNames []string
 
-	PlayCount    int
-	WinCount     int
-	DiscardCount int
-	SkipCount    int
+	// 1 based index into the headers, 0 keeps the topic's own order
+	SortColumn     int
+	SortDescending bool
+
+	// 0 returns every row
+	Page int
 }
 
-func GetStatsLeaderboard(userId uuid.UUID, timeframe string, topic string, subject string) ([]string, [][]string, error) {
+func (filter LeaderboardFilter) where(params *[]any, dateColumn string, lobbyIdColumn string, userIdColumn string) string {
+	conditions := make([]string, 0)
+
+	if !filter.FromDate.IsZero() {
+		conditions = append(conditions, dateColumn+" >= ?")
+		*params = append(*params, filter.FromDate)
+	}
+
+	if !filter.ToDate.IsZero() {
+		conditions = append(conditions, dateColumn+" < ?")
+		*params = append(*params, filter.ToDate)
+	}
+
+	if filter.LobbyName != "" {
+		conditions = append(conditions, lobbyIdColumn+" IN (SELECT ID FROM LOG_LOBBY WHERE NAME LIKE ?)")
+		*params = append(*params, "%"+escapeLike(filter.LobbyName)+"%")
+	}
+
+	if len(filter.UserNames) > 0 {
+		conditions = append(conditions, userIdColumn+" IN (SELECT ID FROM USER WHERE NAME IN ("+strings.Repeat("?, ", len(filter.UserNames)-1)+"?))")
+		for _, userName := range filter.UserNames {
+			*params = append(*params, userName)
+		}
+	}
+
+	if len(conditions) == 0 {
+		return "1 = 1"
+	}
+
+	return strings.Join(conditions, "\n\t\t\t\tAND ")
+}
+
+// GetStatsLeaderboard returns one page of the leaderboard and the total number
+// of rows across all pages.
+func GetStatsLeaderboard(userId uuid.UUID, topic string, subject string, filter LeaderboardFilter) ([]string, [][]string, int, error) {
 	resultHeaders := make([]string, 0)
 	resultRows := make([][]string, 0)
 	params := make([]any, 0)
 
-	var timeframeDateString string
-	switch timeframe {
-	case "all":
-		timeframeDateString = "1970-01-01"
-	case "year":
-		timeframeDateString = "DATE_SUB(CURRENT_DATE(), INTERVAL 1 YEAR)"
-	case "quarter":
-		timeframeDateString = "DATE_SUB(CURRENT_DATE(), INTERVAL 1 QUARTER)"
-	case "month":
-		timeframeDateString = "DATE_SUB(CURRENT_DATE(), INTERVAL 1 MONTH)"
-	case "day":
-		timeframeDateString = "DATE_SUB(CURRENT_DATE(), INTERVAL 1 DAY)"
-	default:
-		return resultHeaders, resultRows, errors.New("invalid time frame provided")
-	}
-
+	var orderBy string
 	var sqlString string
 	switch topic {
 	case "game-win-ratio":
@@ -72,113 +111,108 @@ func GetStatsLeaderboard(userId uuid.UUID, timeframe string, topic string, subje
 			resultHeaders = append(resultHeaders, "Games Won")
 			resultHeaders = append(resultHeaders, "Win Ratio")
 			resultHeaders = append(resultHeaders, "Player")
+			playWhere := filter.where(&params, "DAY", "LOBBY_ID", "USER_ID")
+			winWhere := filter.where(&params, "DAY", "LOBBY_ID", "USER_ID")
+			orderBy = "WIN_RATIO DESC, PLAY_COUNT DESC, NAME ASC"
 			sqlString = fmt.Sprintf(`
-				WITH GAMES_PLAYED AS (
-						SELECT
-							PLAYER_USER_ID AS USER_ID,
-							COUNT(DISTINCT LOBBY_ID) AS PLAY_COUNT
-						FROM LOG_RESPONSE_CARD
-						WHERE CREATED_ON_DATE >= %s
-						GROUP BY PLAYER_USER_ID
-					),
-					GAME_WINS AS (
-						SELECT
-							USER_ID,
-							COUNT(DISTINCT LOBBY_ID) AS WIN_COUNT
-						FROM V_GAME_WINNER
-						WHERE TIMESTAMP >= %s
-						GROUP BY USER_ID
-					)
 				SELECT
 					GP.PLAY_COUNT,
 					COALESCE(GW.WIN_COUNT, 0) AS WIN_COUNT,
 					COALESCE((GW.WIN_COUNT * 1.0) / (GP.PLAY_COUNT * 1.0), 0.0) AS WIN_RATIO,
 					U.NAME AS NAME
 				FROM USER AS U
-					INNER JOIN GAMES_PLAYED AS GP ON GP.USER_ID = U.ID
-					LEFT JOIN GAME_WINS AS GW ON GW.USER_ID = U.ID
-				ORDER BY WIN_RATIO DESC,
-					PLAY_COUNT DESC,
-					NAME ASC
-				LIMIT 10
-			`, timeframeDateString, timeframeDateString)
+					INNER JOIN (
+						SELECT
+							USER_ID,
+							COUNT(DISTINCT LOBBY_ID) AS PLAY_COUNT
+						FROM V_STAT_USER_DAY
+						WHERE %s
+							AND RESPONSE_CARD_PLAY_COUNT > 0
+						GROUP BY USER_ID
+					) AS GP ON GP.USER_ID = U.ID
+					LEFT JOIN (
+						SELECT
+							USER_ID,
+							COUNT(DISTINCT LOBBY_ID) AS WIN_COUNT
+						FROM V_STAT_GAME_WINNER
+						WHERE %s
+						GROUP BY USER_ID
+					) AS GW ON GW.USER_ID = U.ID
+			`, playWhere, winWhere)
 		default:
-			return resultHeaders, resultRows, errors.New("invalid subject provided")
+			return resultHeaders, resultRows, 0, errors.New("invalid subject provided")
 		}
 	case "game-win":
 		switch subject {
 		case "player":
 			resultHeaders = append(resultHeaders, "Games Won")
 			resultHeaders = append(resultHeaders, "Player")
+			where := filter.where(&params, "DAY", "LOBBY_ID", "USER_ID")
+			orderBy = "COUNT DESC, NAME ASC"
 			sqlString = fmt.Sprintf(`
-				WITH GAME_WINS AS (
-						SELECT
-							USER_ID,
-							COUNT(DISTINCT LOBBY_ID) AS WIN_COUNT
-						FROM V_GAME_WINNER
-						WHERE TIMESTAMP >= %s
-						GROUP BY USER_ID
-					)
 				SELECT
 					GW.WIN_COUNT AS COUNT,
 					U.NAME AS NAME
 				FROM USER AS U
-					INNER JOIN GAME_WINS AS GW ON GW.USER_ID = U.ID
-				ORDER BY COUNT DESC,
-					NAME ASC
-				LIMIT 10
-			`, timeframeDateString)
+					INNER JOIN (
+						SELECT
+							USER_ID,
+							COUNT(DISTINCT LOBBY_ID) AS WIN_COUNT
+						FROM V_STAT_GAME_WINNER
+						WHERE %s
+						GROUP BY USER_ID
+					) AS GW ON GW.USER_ID = U.ID
+			`, where)
 		default:
-			return resultHeaders, resultRows, errors.New("invalid subject provided")
+			return resultHeaders, resultRows, 0, errors.New("invalid subject provided")
 		}
 	case "game-play":
 		switch subject {
 		case "player":
 			resultHeaders = append(resultHeaders, "Games Played")
 			resultHeaders = append(resultHeaders, "Player")
+			where := filter.where(&params, "SUD.DAY", "SUD.LOBBY_ID", "SUD.USER_ID")
+			orderBy = "COUNT DESC, NAME ASC"
 			sqlString = fmt.Sprintf(`
 				SELECT
-					COUNT(DISTINCT LRC.LOBBY_ID) AS COUNT,
+					COUNT(DISTINCT SUD.LOBBY_ID) AS COUNT,
 					U.NAME AS NAME
-				FROM LOG_RESPONSE_CARD AS LRC
-					INNER JOIN USER AS U ON U.ID = LRC.PLAYER_USER_ID
-				WHERE LRC.CREATED_ON_DATE >= %s
+				FROM V_STAT_USER_DAY AS SUD
+					INNER JOIN USER AS U ON U.ID = SUD.USER_ID
+				WHERE %s
+					AND SUD.RESPONSE_CARD_PLAY_COUNT > 0
 				GROUP BY U.ID
-				ORDER BY COUNT DESC,
-					NAME ASC
-				LIMIT 10
-			`, timeframeDateString)
+			`, where)
 		case "card":
 			resultHeaders = append(resultHeaders, "Games Played")
 			resultHeaders = append(resultHeaders, "Card")
+			where := filter.where(&params, "SCD.DAY", "SCD.LOBBY_ID", "SCD.USER_ID")
+			orderBy = "COUNT DESC, NAME ASC"
 			sqlString = fmt.Sprintf(`
 				SELECT
-					COUNT(DISTINCT LRC.LOBBY_ID) AS COUNT,
-					COALESCE(C.TEXT, LRC.SPECIAL_CATEGORY, 'Unknown') AS NAME
-				FROM LOG_RESPONSE_CARD AS LRC
-					LEFT JOIN CARD AS C ON C.ID = LRC.PLAYER_CARD_ID
-				WHERE LRC.CREATED_ON_DATE >= %s
+					COUNT(DISTINCT SCD.LOBBY_ID) AS COUNT,
+					COALESCE(C.TEXT, 'Unknown') AS NAME
+				FROM V_STAT_CARD_DAY AS SCD
+					LEFT JOIN CARD AS C ON C.ID = SCD.CARD_ID
+				WHERE %s
+					AND SCD.RESPONSE_PLAY_COUNT > 0
 				GROUP BY C.ID
-				ORDER BY COUNT DESC,
-					NAME ASC
-				LIMIT 10
-			`, timeframeDateString)
+			`, where)
 		case "special-category":
 			resultHeaders = append(resultHeaders, "Games Played")
 			resultHeaders = append(resultHeaders, "Special Category")
+			where := filter.where(&params, "SSD.DAY", "SSD.LOBBY_ID", "SSD.PLAYER_USER_ID")
+			orderBy = "COUNT DESC, NAME ASC"
 			sqlString = fmt.Sprintf(`
 				SELECT
-					COUNT(DISTINCT LRC.LOBBY_ID) AS COUNT,
-					COALESCE(LRC.SPECIAL_CATEGORY, 'NONE') AS NAME
-				FROM LOG_RESPONSE_CARD AS LRC
-				WHERE LRC.CREATED_ON_DATE >= %s
-				GROUP BY LRC.SPECIAL_CATEGORY
-				ORDER BY COUNT DESC,
-					NAME ASC
-				LIMIT 10
-			`, timeframeDateString)
+					COUNT(DISTINCT SSD.LOBBY_ID) AS COUNT,
+					SSD.SPECIAL_CATEGORY AS NAME
+				FROM V_STAT_SPECIAL_DAY AS SSD
+				WHERE %s
+				GROUP BY SSD.SPECIAL_CATEGORY
+			`, where)
 		default:
-			return resultHeaders, resultRows, errors.New("invalid subject provided")
+			return resultHeaders, resultRows, 0, errors.New("invalid subject provided")
 		}
 	case "round-win-ratio":
 		switch subject {
@@ -187,6 +221,8 @@ func GetStatsLeaderboard(userId uuid.UUID, timeframe string, topic string, subje
 			resultHeaders = append(resultHeaders, "Rounds Won")
 			resultHeaders = append(resultHeaders, "Win Ratio")
 			resultHeaders = append(resultHeaders, "Player")
+			where := filter.where(&params, "SUD.DAY", "SUD.LOBBY_ID", "SUD.USER_ID")
+			orderBy = "WIN_RATIO DESC, PLAY_COUNT DESC, NAME ASC"
 			sqlString = fmt.Sprintf(`
 				SELECT
 					PLAY_COUNT,
@@ -195,26 +231,24 @@ func GetStatsLeaderboard(userId uuid.UUID, timeframe string, topic string, subje
 					NAME
 				FROM (
 						SELECT
-							COUNT(DISTINCT LRC.ROUND_ID) AS PLAY_COUNT,
-							COUNT(DISTINCT LW.ID) AS WIN_COUNT,
+							SUM(SUD.ROUND_PLAY_COUNT) AS PLAY_COUNT,
+							SUM(SUD.ROUND_WIN_COUNT) AS WIN_COUNT,
 							U.NAME AS NAME
-						FROM LOG_RESPONSE_CARD AS LRC
-							LEFT JOIN LOG_WIN AS LW ON LW.RESPONSE_ID = LRC.RESPONSE_ID
-							INNER JOIN USER AS U ON U.ID = LRC.PLAYER_USER_ID
-						WHERE LRC.CREATED_ON_DATE >= %s
+						FROM V_STAT_USER_DAY AS SUD
+							INNER JOIN USER AS U ON U.ID = SUD.USER_ID
+						WHERE %s
 						GROUP BY U.ID
+						HAVING SUM(SUD.ROUND_PLAY_COUNT) > 0
 					) AS T
-				ORDER BY WIN_RATIO DESC,
-					PLAY_COUNT DESC,
-					NAME ASC
-				LIMIT 10
-			`, timeframeDateString)
+			`, where)
 		case "card":
 			resultHeaders = append(resultHeaders, "Rounds Played")
 			resultHeaders = append(resultHeaders, "Rounds Won")
 			resultHeaders = append(resultHeaders, "Win Ratio")
 			resultHeaders = append(resultHeaders, "Card")
+			where := filter.where(&params, "SCD.DAY", "SCD.LOBBY_ID", "SCD.USER_ID")
 			params = append(params, userId)
+			orderBy = "WIN_RATIO DESC, PLAY_COUNT DESC, NAME ASC"
 			sqlString = fmt.Sprintf(`
 				SELECT
 					PLAY_COUNT,
@@ -223,26 +257,24 @@ func GetStatsLeaderboard(userId uuid.UUID, timeframe string, topic string, subje
 					NAME
 				FROM (
 						SELECT
-							COUNT(DISTINCT LRC.ROUND_ID) AS PLAY_COUNT,
-							COUNT(DISTINCT LW.ID) AS WIN_COUNT,
-							COALESCE(C.TEXT, LRC.SPECIAL_CATEGORY, 'Unknown') AS NAME
-						FROM LOG_RESPONSE_CARD AS LRC
-							LEFT JOIN LOG_WIN AS LW ON LW.RESPONSE_ID = LRC.RESPONSE_ID
-							LEFT JOIN CARD AS C ON C.ID = LRC.PLAYER_CARD_ID
-						WHERE LRC.CREATED_ON_DATE >= %s
-							AND FN_USER_HAS_DECK_ACCESS(?, C.DECK_ID)
+							SUM(SCD.ROUND_PLAY_COUNT) AS PLAY_COUNT,
+							SUM(SCD.ROUND_WIN_COUNT) AS WIN_COUNT,
+							COALESCE(C.TEXT, 'Unknown') AS NAME
+						FROM V_STAT_CARD_DAY AS SCD
+							LEFT JOIN CARD AS C ON C.ID = SCD.CARD_ID
+						WHERE %s
+							AND FN_USER_HAS_DECK_ACCESS(?, C.DECK_ID, 'READ')
 						GROUP BY C.ID
+						HAVING SUM(SCD.ROUND_PLAY_COUNT) > 0
 					) AS T
-				ORDER BY WIN_RATIO DESC,
-					PLAY_COUNT DESC,
-					NAME ASC
-				LIMIT 10
-			`, timeframeDateString)
+			`, where)
 		case "special-category":
 			resultHeaders = append(resultHeaders, "Rounds Played")
 			resultHeaders = append(resultHeaders, "Rounds Won")
 			resultHeaders = append(resultHeaders, "Win Ratio")
 			resultHeaders = append(resultHeaders, "Special Category")
+			where := filter.where(&params, "SSD.DAY", "SSD.LOBBY_ID", "SSD.PLAYER_USER_ID")
+			orderBy = "WIN_RATIO DESC, PLAY_COUNT DESC, NAME ASC"
 			sqlString = fmt.Sprintf(`
 				SELECT
 					PLAY_COUNT,
@@ -251,287 +283,310 @@ func GetStatsLeaderboard(userId uuid.UUID, timeframe string, topic string, subje
 					NAME
 				FROM (
 						SELECT
-							COUNT(DISTINCT LRC.ROUND_ID) AS PLAY_COUNT,
-							COUNT(DISTINCT LW.ID) AS WIN_COUNT,
-							COALESCE(LRC.SPECIAL_CATEGORY, 'NONE') AS NAME
-						FROM LOG_RESPONSE_CARD AS LRC
-							LEFT JOIN LOG_WIN AS LW ON LW.RESPONSE_ID = LRC.RESPONSE_ID
-						WHERE LRC.CREATED_ON_DATE >= %s
-						GROUP BY LRC.SPECIAL_CATEGORY
+							SUM(SSD.ROUND_PLAY_COUNT) AS PLAY_COUNT,
+							SUM(SSD.ROUND_WIN_COUNT) AS WIN_COUNT,
+							SSD.SPECIAL_CATEGORY AS NAME
+						FROM V_STAT_SPECIAL_DAY AS SSD
+						WHERE %s
+						GROUP BY SSD.SPECIAL_CATEGORY
 					) AS T
-				ORDER BY WIN_RATIO DESC,
-					PLAY_COUNT DESC,
-					NAME ASC
-				LIMIT 10
-			`, timeframeDateString)
+			`, where)
 		default:
-			return resultHeaders, resultRows, errors.New("invalid subject provided")
+			return resultHeaders, resultRows, 0, errors.New("invalid subject provided")
 		}
 	case "round-win":
 		switch subject {
 		case "player":
 			resultHeaders = append(resultHeaders, "Rounds Won")
 			resultHeaders = append(resultHeaders, "Player")
+			where := filter.where(&params, "SUD.DAY", "SUD.LOBBY_ID", "SUD.USER_ID")
+			orderBy = "COUNT DESC, NAME ASC"
 			sqlString = fmt.Sprintf(`
 				SELECT
-					COUNT(DISTINCT RW.ROUND_ID) AS COUNT,
+					SUM(SUD.ROUND_WIN_COUNT) AS COUNT,
 					U.NAME AS NAME
-				FROM V_ROUND_WINNER AS RW
-					INNER JOIN USER AS U ON U.ID = RW.USER_ID
-				WHERE RW.TIMESTAMP >= %s
+				FROM V_STAT_USER_DAY AS SUD
+					INNER JOIN USER AS U ON U.ID = SUD.USER_ID
+				WHERE %s
 				GROUP BY U.ID
-				ORDER BY COUNT DESC,
-					NAME ASC
-				LIMIT 10
-			`, timeframeDateString)
+				HAVING SUM(SUD.ROUND_WIN_COUNT) > 0
+			`, where)
 		case "card":
 			resultHeaders = append(resultHeaders, "Rounds Won")
 			resultHeaders = append(resultHeaders, "Card")
+			where := filter.where(&params, "SCD.DAY", "SCD.LOBBY_ID", "SCD.USER_ID")
 			params = append(params, userId)
+			orderBy = "COUNT DESC, NAME ASC"
 			sqlString = fmt.Sprintf(`
 				SELECT
-					COUNT(DISTINCT LRC.ROUND_ID) AS COUNT,
-					COALESCE(C.TEXT, LRC.SPECIAL_CATEGORY, 'Unknown') AS NAME
-				FROM LOG_RESPONSE_CARD AS LRC
-					INNER JOIN LOG_WIN AS LW ON LW.RESPONSE_ID = LRC.RESPONSE_ID
-					LEFT JOIN CARD AS C ON C.ID = LRC.PLAYER_CARD_ID
-				WHERE LRC.CREATED_ON_DATE >= %s
-					AND FN_USER_HAS_DECK_ACCESS(?, C.DECK_ID)
+					SUM(SCD.ROUND_WIN_COUNT) AS COUNT,
+					COALESCE(C.TEXT, 'Unknown') AS NAME
+				FROM V_STAT_CARD_DAY AS SCD
+					LEFT JOIN CARD AS C ON C.ID = SCD.CARD_ID
+				WHERE %s
+					AND FN_USER_HAS_DECK_ACCESS(?, C.DECK_ID, 'READ')
 				GROUP BY C.ID
-				ORDER BY COUNT DESC,
-					NAME ASC
-				LIMIT 10
-			`, timeframeDateString)
+				HAVING SUM(SCD.ROUND_WIN_COUNT) > 0
+			`, where)
 		case "special-category":
 			resultHeaders = append(resultHeaders, "Rounds Won")
 			resultHeaders = append(resultHeaders, "Special Category")
+			where := filter.where(&params, "SSD.DAY", "SSD.LOBBY_ID", "SSD.PLAYER_USER_ID")
+			orderBy = "COUNT DESC, NAME ASC"
 			sqlString = fmt.Sprintf(`
 				SELECT
-					COUNT(DISTINCT LRC.ROUND_ID) AS COUNT,
-					COALESCE(LRC.SPECIAL_CATEGORY, 'NONE') AS NAME
-				FROM LOG_RESPONSE_CARD AS LRC
-					INNER JOIN LOG_WIN AS LW ON LW.RESPONSE_ID = LRC.RESPONSE_ID
-				WHERE LRC.CREATED_ON_DATE >= %s
-				GROUP BY LRC.SPECIAL_CATEGORY
-				ORDER BY COUNT DESC,
-					NAME ASC
-				LIMIT 10
-			`, timeframeDateString)
+					SUM(SSD.ROUND_WIN_COUNT) AS COUNT,
+					SSD.SPECIAL_CATEGORY AS NAME
+				FROM V_STAT_SPECIAL_DAY AS SSD
+				WHERE %s
+				GROUP BY SSD.SPECIAL_CATEGORY
+				HAVING SUM(SSD.ROUND_WIN_COUNT) > 0
+			`, where)
 		default:
-			return resultHeaders, resultRows, errors.New("invalid subject provided")
+			return resultHeaders, resultRows, 0, errors.New("invalid subject provided")
 		}
 	case "round-play":
 		switch subject {
 		case "player":
 			resultHeaders = append(resultHeaders, "Rounds Played")
 			resultHeaders = append(resultHeaders, "Player")
+			where := filter.where(&params, "SUD.DAY", "SUD.LOBBY_ID", "SUD.USER_ID")
+			orderBy = "COUNT DESC, NAME ASC"
 			sqlString = fmt.Sprintf(`
 				SELECT
-					COUNT(DISTINCT LRC.ROUND_ID) AS COUNT,
+					SUM(SUD.ROUND_PLAY_COUNT) AS COUNT,
 					U.NAME AS NAME
-				FROM LOG_RESPONSE_CARD AS LRC
-					INNER JOIN USER AS U ON U.ID = LRC.PLAYER_USER_ID
-				WHERE LRC.CREATED_ON_DATE >= %s
+				FROM V_STAT_USER_DAY AS SUD
+					INNER JOIN USER AS U ON U.ID = SUD.USER_ID
+				WHERE %s
 				GROUP BY U.ID
-				ORDER BY COUNT DESC,
-					NAME ASC
-				LIMIT 10
-			`, timeframeDateString)
+				HAVING SUM(SUD.ROUND_PLAY_COUNT) > 0
+			`, where)
 		case "card":
 			resultHeaders = append(resultHeaders, "Rounds Played")
 			resultHeaders = append(resultHeaders, "Card")
+			where := filter.where(&params, "SCD.DAY", "SCD.LOBBY_ID", "SCD.USER_ID")
 			params = append(params, userId)
+			orderBy = "COUNT DESC, NAME ASC"
 			sqlString = fmt.Sprintf(`
 				SELECT
-					COUNT(DISTINCT LRC.ROUND_ID) AS COUNT,
-					COALESCE(C.TEXT, LRC.SPECIAL_CATEGORY, 'Unknown') AS NAME
-				FROM LOG_RESPONSE_CARD AS LRC
-					LEFT JOIN CARD AS C ON C.ID = LRC.PLAYER_CARD_ID
-				WHERE LRC.CREATED_ON_DATE >= %s
-					AND FN_USER_HAS_DECK_ACCESS(?, C.DECK_ID)
+					SUM(SCD.ROUND_PLAY_COUNT) AS COUNT,
+					COALESCE(C.TEXT, 'Unknown') AS NAME
+				FROM V_STAT_CARD_DAY AS SCD
+					LEFT JOIN CARD AS C ON C.ID = SCD.CARD_ID
+				WHERE %s
+					AND FN_USER_HAS_DECK_ACCESS(?, C.DECK_ID, 'READ')
 				GROUP BY C.ID
-				ORDER BY COUNT DESC,
-					NAME ASC
-				LIMIT 10
-			`, timeframeDateString)
+				HAVING SUM(SCD.ROUND_PLAY_COUNT) > 0
+			`, where)
 		case "special-category":
 			resultHeaders = append(resultHeaders, "Rounds Played")
 			resultHeaders = append(resultHeaders, "Special Category")
+			where := filter.where(&params, "SSD.DAY", "SSD.LOBBY_ID", "SSD.PLAYER_USER_ID")
+			orderBy = "COUNT DESC, NAME ASC"
 			sqlString = fmt.Sprintf(`
 				SELECT
-					COUNT(DISTINCT LRC.ROUND_ID) AS COUNT,
-					COALESCE(LRC.SPECIAL_CATEGORY, 'NONE') AS NAME
-				FROM LOG_RESPONSE_CARD AS LRC
-				WHERE LRC.CREATED_ON_DATE >= %s
-				GROUP BY LRC.SPECIAL_CATEGORY
-				ORDER BY COUNT DESC,
-					NAME ASC
-				LIMIT 10
-			`, timeframeDateString)
+					SUM(SSD.ROUND_PLAY_COUNT) AS COUNT,
+					SSD.SPECIAL_CATEGORY AS NAME
+				FROM V_STAT_SPECIAL_DAY AS SSD
+				WHERE %s
+				GROUP BY SSD.SPECIAL_CATEGORY
+			`, where)
+		default:
+			return resultHeaders, resultRows, 0, errors.New("invalid subject provided")
+		}
+	case "rating":
+		switch subject {
+		case "player":
+			resultHeaders = append(resultHeaders, "Rating")
+			resultHeaders = append(resultHeaders, "Rating Change")
+			resultHeaders = append(resultHeaders, "Rounds Rated")
+			resultHeaders = append(resultHeaders, "Player")
+			rating := "UR.RATING"
+			seasonJoin := ""
+			if filter.SeasonId != uuid.Nil {
+				rating = "SR.RATING"
+				seasonJoin = "INNER JOIN SEASON_RATING AS SR ON SR.USER_ID = UR.USER_ID AND SR.SEASON_ID = ?"
+				params = append(params, filter.SeasonId)
+			}
+			where := filter.where(&params, "SRD.DAY", "SRD.LOBBY_ID", "SRD.USER_ID")
+			orderBy = "RATING DESC, NAME ASC"
+			sqlString = fmt.Sprintf(`
+				SELECT
+					ROUND(%s) AS RATING,
+					ROUND(SUM(SRD.RATING_CHANGE)) AS RATING_CHANGE,
+					SUM(SRD.ROUND_COUNT) AS ROUND_COUNT,
+					U.NAME AS NAME
+				FROM USER_RATING AS UR
+					INNER JOIN USER AS U ON U.ID = UR.USER_ID
+					INNER JOIN STAT_RATING_DAY AS SRD ON SRD.USER_ID = UR.USER_ID
+					%s
+				WHERE %s
+				GROUP BY U.ID
+			`, rating, seasonJoin, where)
 		default:
-			return resultHeaders, resultRows, errors.New("invalid subject provided")
+			return resultHeaders, resultRows, 0, errors.New("invalid subject provided")
 		}
 	case "response-card-play":
 		switch subject {
 		case "player":
 			resultHeaders = append(resultHeaders, "Cards Played")
 			resultHeaders = append(resultHeaders, "Player")
+			where := filter.where(&params, "SUD.DAY", "SUD.LOBBY_ID", "SUD.USER_ID")
+			orderBy = "COUNT DESC, NAME ASC"
 			sqlString = fmt.Sprintf(`
 				SELECT
-					COUNT(DISTINCT LRC.ID) AS COUNT,
+					SUM(SUD.RESPONSE_CARD_PLAY_COUNT) AS COUNT,
 					U.NAME AS NAME
-				FROM LOG_RESPONSE_CARD AS LRC
-					INNER JOIN USER AS U ON U.ID = LRC.PLAYER_USER_ID
-				WHERE LRC.CREATED_ON_DATE >= %s
+				FROM V_STAT_USER_DAY AS SUD
+					INNER JOIN USER AS U ON U.ID = SUD.USER_ID
+				WHERE %s
 				GROUP BY U.ID
-				ORDER BY COUNT DESC,
-					NAME ASC
-				LIMIT 10
-			`, timeframeDateString)
+				HAVING SUM(SUD.RESPONSE_CARD_PLAY_COUNT) > 0
+			`, where)
 		case "card":
 			resultHeaders = append(resultHeaders, "Cards Played")
 			resultHeaders = append(resultHeaders, "Card")
+			where := filter.where(&params, "SCD.DAY", "SCD.LOBBY_ID", "SCD.USER_ID")
 			params = append(params, userId)
+			orderBy = "COUNT DESC, NAME ASC"
 			sqlString = fmt.Sprintf(`
 				SELECT
-					COUNT(DISTINCT LRC.ID) AS COUNT,
-					COALESCE(C.TEXT, LRC.SPECIAL_CATEGORY, 'Unknown') AS NAME
-				FROM LOG_RESPONSE_CARD AS LRC
-					LEFT JOIN CARD AS C ON C.ID = LRC.PLAYER_CARD_ID
-				WHERE LRC.CREATED_ON_DATE >= %s
-					AND FN_USER_HAS_DECK_ACCESS(?, C.DECK_ID)
+					SUM(SCD.RESPONSE_PLAY_COUNT) AS COUNT,
+					COALESCE(C.TEXT, 'Unknown') AS NAME
+				FROM V_STAT_CARD_DAY AS SCD
+					LEFT JOIN CARD AS C ON C.ID = SCD.CARD_ID
+				WHERE %s
+					AND FN_USER_HAS_DECK_ACCESS(?, C.DECK_ID, 'READ')
 				GROUP BY C.ID
-				ORDER BY COUNT DESC,
-					NAME ASC
-				LIMIT 10
-			`, timeframeDateString)
+				HAVING SUM(SCD.RESPONSE_PLAY_COUNT) > 0
+			`, where)
 		case "special-category":
 			resultHeaders = append(resultHeaders, "Cards Played")
 			resultHeaders = append(resultHeaders, "Special Category")
+			where := filter.where(&params, "SSD.DAY", "SSD.LOBBY_ID", "SSD.PLAYER_USER_ID")
+			orderBy = "COUNT DESC, NAME ASC"
 			sqlString = fmt.Sprintf(`
 				SELECT
-					COUNT(DISTINCT LRC.ID) AS COUNT,
-					COALESCE(LRC.SPECIAL_CATEGORY, 'NONE') AS NAME
-				FROM LOG_RESPONSE_CARD AS LRC
-				WHERE LRC.CREATED_ON_DATE >= %s
-				GROUP BY LRC.SPECIAL_CATEGORY
-				ORDER BY COUNT DESC,
-					NAME ASC
-				LIMIT 10
-			`, timeframeDateString)
+					SUM(SSD.RESPONSE_PLAY_COUNT) AS COUNT,
+					SSD.SPECIAL_CATEGORY AS NAME
+				FROM V_STAT_SPECIAL_DAY AS SSD
+				WHERE %s
+				GROUP BY SSD.SPECIAL_CATEGORY
+			`, where)
 		default:
-			return resultHeaders, resultRows, errors.New("invalid subject provided")
+			return resultHeaders, resultRows, 0, errors.New("invalid subject provided")
 		}
 	case "response-card-discard":
 		switch subject {
 		case "player":
 			resultHeaders = append(resultHeaders, "Cards Discarded")
 			resultHeaders = append(resultHeaders, "Player")
+			where := filter.where(&params, "SUD.DAY", "SUD.LOBBY_ID", "SUD.USER_ID")
+			orderBy = "COUNT DESC, NAME ASC"
 			sqlString = fmt.Sprintf(`
 				SELECT
-					COUNT(DISTINCT LD.ID) AS COUNT,
+					SUM(SUD.RESPONSE_CARD_DISCARD_COUNT) AS COUNT,
 					U.NAME AS NAME
-				FROM LOG_DISCARD AS LD
-					INNER JOIN USER AS U ON U.ID = LD.USER_ID
-				WHERE LD.CREATED_ON_DATE >= %s
+				FROM V_STAT_USER_DAY AS SUD
+					INNER JOIN USER AS U ON U.ID = SUD.USER_ID
+				WHERE %s
 				GROUP BY U.ID
-				ORDER BY COUNT DESC,
-					NAME ASC
-				LIMIT 10
-			`, timeframeDateString)
+				HAVING SUM(SUD.RESPONSE_CARD_DISCARD_COUNT) > 0
+			`, where)
 		case "card":
 			resultHeaders = append(resultHeaders, "Cards Discarded")
 			resultHeaders = append(resultHeaders, "Card")
+			where := filter.where(&params, "SCD.DAY", "SCD.LOBBY_ID", "SCD.USER_ID")
 			params = append(params, userId)
+			orderBy = "COUNT DESC, NAME ASC"
 			sqlString = fmt.Sprintf(`
 				SELECT
-					COUNT(DISTINCT LD.ID) AS COUNT,
+					SUM(SCD.DISCARD_COUNT) AS COUNT,
 					COALESCE(C.TEXT, 'Unknown') AS NAME
-				FROM LOG_DISCARD AS LD
-					LEFT JOIN CARD AS C ON C.ID = LD.CARD_ID
-				WHERE LD.CREATED_ON_DATE >= %s
-					AND FN_USER_HAS_DECK_ACCESS(?, C.DECK_ID)
+				FROM V_STAT_CARD_DAY AS SCD
+					LEFT JOIN CARD AS C ON C.ID = SCD.CARD_ID
+				WHERE %s
+					AND FN_USER_HAS_DECK_ACCESS(?, C.DECK_ID, 'READ')
 				GROUP BY C.ID
-				ORDER BY COUNT DESC,
-					NAME ASC
-				LIMIT 10
-			`, timeframeDateString)
+				HAVING SUM(SCD.DISCARD_COUNT) > 0
+			`, where)
 		default:
-			return resultHeaders, resultRows, errors.New("invalid subject provided")
+			return resultHeaders, resultRows, 0, errors.New("invalid subject provided")
 		}
 	case "prompt-card-play":
 		switch subject {
 		case "player":
 			resultHeaders = append(resultHeaders, "Cards Played")
 			resultHeaders = append(resultHeaders, "Player")
+			where := filter.where(&params, "SUD.DAY", "SUD.LOBBY_ID", "SUD.USER_ID")
+			orderBy = "COUNT DESC, NAME ASC"
 			sqlString = fmt.Sprintf(`
 				SELECT
-					COUNT(DISTINCT LRC.ROUND_ID) AS COUNT,
+					SUM(SUD.PROMPT_CARD_PLAY_COUNT) AS COUNT,
 					U.NAME AS NAME
-				FROM LOG_RESPONSE_CARD AS LRC
-					INNER JOIN USER AS U ON U.ID = LRC.JUDGE_USER_ID
-				WHERE LRC.CREATED_ON_DATE >= %s
+				FROM V_STAT_USER_DAY AS SUD
+					INNER JOIN USER AS U ON U.ID = SUD.USER_ID
+				WHERE %s
 				GROUP BY U.ID
-				ORDER BY COUNT DESC,
-					NAME ASC
-				LIMIT 10
-			`, timeframeDateString)
+				HAVING SUM(SUD.PROMPT_CARD_PLAY_COUNT) > 0
+			`, where)
 		case "card":
 			resultHeaders = append(resultHeaders, "Cards Played")
 			resultHeaders = append(resultHeaders, "Card")
+			where := filter.where(&params, "SCD.DAY", "SCD.LOBBY_ID", "SCD.USER_ID")
 			params = append(params, userId)
+			orderBy = "COUNT DESC, NAME ASC"
 			sqlString = fmt.Sprintf(`
 				SELECT
-					COUNT(DISTINCT LRC.ROUND_ID) AS COUNT,
+					SUM(SCD.PROMPT_PLAY_COUNT) AS COUNT,
 					COALESCE(C.TEXT, 'Unknown') AS NAME
-				FROM LOG_RESPONSE_CARD AS LRC
-					LEFT JOIN CARD AS C ON C.ID = LRC.JUDGE_CARD_ID
-				WHERE LRC.CREATED_ON_DATE >= %s
-					AND FN_USER_HAS_DECK_ACCESS(?, C.DECK_ID)
+				FROM V_STAT_CARD_DAY AS SCD
+					LEFT JOIN CARD AS C ON C.ID = SCD.CARD_ID
+				WHERE %s
+					AND FN_USER_HAS_DECK_ACCESS(?, C.DECK_ID, 'READ')
 				GROUP BY C.ID
-				ORDER BY COUNT DESC,
-					NAME ASC
-				LIMIT 10
-			`, timeframeDateString)
+				HAVING SUM(SCD.PROMPT_PLAY_COUNT) > 0
+			`, where)
 		default:
-			return resultHeaders, resultRows, errors.New("invalid subject provided")
+			return resultHeaders, resultRows, 0, errors.New("invalid subject provided")
 		}
 	case "prompt-card-skip":
 		switch subject {
 		case "player":
 			resultHeaders = append(resultHeaders, "Cards Skipped")
 			resultHeaders = append(resultHeaders, "Player")
+			where := filter.where(&params, "SUD.DAY", "SUD.LOBBY_ID", "SUD.USER_ID")
+			orderBy = "COUNT DESC, NAME ASC"
 			sqlString = fmt.Sprintf(`
 				SELECT
-					COUNT(DISTINCT LS.ID) AS COUNT,
+					SUM(SUD.PROMPT_CARD_SKIP_COUNT) AS COUNT,
 					U.NAME AS NAME
-				FROM LOG_SKIP AS LS
-					INNER JOIN USER AS U ON U.ID = LS.USER_ID
-				WHERE LS.CREATED_ON_DATE >= %s
+				FROM V_STAT_USER_DAY AS SUD
+					INNER JOIN USER AS U ON U.ID = SUD.USER_ID
+				WHERE %s
 				GROUP BY U.ID
-				ORDER BY COUNT DESC,
-					NAME ASC
-				LIMIT 10
-			`, timeframeDateString)
+				HAVING SUM(SUD.PROMPT_CARD_SKIP_COUNT) > 0
+			`, where)
 		case "card":
 			resultHeaders = append(resultHeaders, "Cards Skipped")
 			resultHeaders = append(resultHeaders, "Card")
+			where := filter.where(&params, "SCD.DAY", "SCD.LOBBY_ID", "SCD.USER_ID")
 			params = append(params, userId)
+			orderBy = "COUNT DESC, NAME ASC"
 			sqlString = fmt.Sprintf(`
 				SELECT
-					COUNT(DISTINCT LS.ID) AS COUNT,
+					SUM(SCD.SKIP_COUNT) AS COUNT,
 					COALESCE(C.TEXT, 'Unknown') AS NAME
-				FROM LOG_SKIP AS LS
-					LEFT JOIN CARD AS C ON C.ID = LS.CARD_ID
-				WHERE LS.CREATED_ON_DATE >= %s
-					AND FN_USER_HAS_DECK_ACCESS(?, C.DECK_ID)
+				FROM V_STAT_CARD_DAY AS SCD
+					LEFT JOIN CARD AS C ON C.ID = SCD.CARD_ID
+				WHERE %s
+					AND FN_USER_HAS_DECK_ACCESS(?, C.DECK_ID, 'READ')
 				GROUP BY C.ID
-				ORDER BY COUNT DESC,
-					NAME ASC
-				LIMIT 10
-			`, timeframeDateString)
+				HAVING SUM(SCD.SKIP_COUNT) > 0
+			`, where)
 		default:
-			return resultHeaders, resultRows, errors.New("invalid subject provided")
+			return resultHeaders, resultRows, 0, errors.New("invalid subject provided")
 		}
 	case "picked-judge":
 		switch subject {
@@ -539,69 +594,64 @@ func GetStatsLeaderboard(userId uuid.UUID, timeframe string, topic string, subje
 			resultHeaders = append(resultHeaders, "Judge Picking")
 			resultHeaders = append(resultHeaders, "Player")
 			resultHeaders = append(resultHeaders, "Count")
+			where := filter.where(&params, "SPD.DAY", "SPD.LOBBY_ID", "SPD.PLAYER_USER_ID")
 			params = append(params, userId)
+			orderBy = "COUNT DESC, NAME ASC"
 			sqlString = fmt.Sprintf(`
 				SELECT
 					UJ.NAME AS JUDGE_NAME,
 					UP.NAME AS NAME,
-					COUNT(DISTINCT LRC.ROUND_ID) AS COUNT
-				FROM LOG_RESPONSE_CARD AS LRC
-					INNER JOIN LOG_WIN AS LW ON LW.RESPONSE_ID = LRC.RESPONSE_ID
-					INNER JOIN USER AS UJ ON UJ.ID = LRC.JUDGE_USER_ID
-					INNER JOIN USER AS UP ON UP.ID = LRC.PLAYER_USER_ID
-				WHERE LRC.CREATED_ON_DATE >= %s
-					AND UJ.ID = ?
-				GROUP BY LRC.JUDGE_USER_ID,
-					LRC.PLAYER_USER_ID
-				ORDER BY COUNT DESC,
-					NAME ASC
-				LIMIT 10
-			`, timeframeDateString)
+					SUM(SPD.ROUND_WIN_COUNT) AS COUNT
+				FROM V_STAT_PICK_DAY AS SPD
+					INNER JOIN USER AS UJ ON UJ.ID = SPD.JUDGE_USER_ID
+					INNER JOIN USER AS UP ON UP.ID = SPD.PLAYER_USER_ID
+				WHERE %s
+					AND SPD.JUDGE_USER_ID = ?
+				GROUP BY SPD.JUDGE_USER_ID,
+					SPD.PLAYER_USER_ID
+			`, where)
 		case "card":
 			resultHeaders = append(resultHeaders, "Judge Picking")
 			resultHeaders = append(resultHeaders, "Card")
 			resultHeaders = append(resultHeaders, "Count")
+			where := filter.where(&params, "SPCD.DAY", "SPCD.LOBBY_ID", "SPCD.PLAYER_USER_ID")
 			params = append(params, userId)
+			orderBy = "COUNT DESC, NAME ASC"
 			sqlString = fmt.Sprintf(`
 				SELECT
 					UJ.NAME AS JUDGE_NAME,
 					CP.TEXT AS NAME,
-					COUNT(DISTINCT LRC.ROUND_ID) AS COUNT
-				FROM LOG_RESPONSE_CARD AS LRC
-					INNER JOIN LOG_WIN AS LW ON LW.RESPONSE_ID = LRC.RESPONSE_ID
-					INNER JOIN USER AS UJ ON UJ.ID = LRC.JUDGE_USER_ID
-					INNER JOIN CARD AS CP ON CP.ID = LRC.PLAYER_CARD_ID
-				WHERE LRC.CREATED_ON_DATE >= %s
-					AND UJ.ID = ?
-				GROUP BY LRC.JUDGE_USER_ID,
-					LRC.PLAYER_CARD_ID
-				ORDER BY COUNT DESC,
-					NAME ASC
-				LIMIT 10
-			`, timeframeDateString)
+					SUM(SPCD.ROUND_WIN_COUNT) AS COUNT
+				FROM V_STAT_PICK_CARD_DAY AS SPCD
+					INNER JOIN USER AS UJ ON UJ.ID = SPCD.JUDGE_USER_ID
+					INNER JOIN CARD AS CP ON CP.ID = SPCD.CARD_ID
+				WHERE %s
+					AND SPCD.JUDGE_USER_ID = ?
+				GROUP BY SPCD.JUDGE_USER_ID,
+					SPCD.CARD_ID
+			`, where)
 		case "special-category":
 			resultHeaders = append(resultHeaders, "Judge Picking")
 			resultHeaders = append(resultHeaders, "Special Category")
 			resultHeaders = append(resultHeaders, "Count")
+			where := filter.where(&params, "SSD.DAY", "SSD.LOBBY_ID", "SSD.PLAYER_USER_ID")
 			params = append(params, userId)
+			orderBy = "COUNT DESC, NAME ASC"
 			sqlString = fmt.Sprintf(`
 				SELECT
 					UJ.NAME AS JUDGE_NAME,
-					COALESCE(LRC.SPECIAL_CATEGORY, 'NONE') AS NAME,
-					COUNT(DISTINCT LRC.ROUND_ID) AS COUNT
-				FROM LOG_RESPONSE_CARD AS LRC
-					INNER JOIN LOG_WIN AS LW ON LW.RESPONSE_ID = LRC.RESPONSE_ID
-					INNER JOIN USER AS UJ ON UJ.ID = LRC.JUDGE_USER_ID
-				WHERE LRC.CREATED_ON_DATE >= %s
-					AND UJ.ID = ?
-				GROUP BY LRC.JUDGE_USER_ID,
-					LRC.SPECIAL_CATEGORY
-				ORDER BY COUNT DESC,
-					NAME ASC
-				LIMIT 10
-			`, timeframeDateString)
+					SSD.SPECIAL_CATEGORY AS NAME,
+					SUM(SSD.ROUND_WIN_COUNT) AS COUNT
+				FROM V_STAT_SPECIAL_DAY AS SSD
+					INNER JOIN USER AS UJ ON UJ.ID = SSD.JUDGE_USER_ID
+				WHERE %s
+					AND SSD.JUDGE_USER_ID = ?
+				GROUP BY SSD.JUDGE_USER_ID,
+					SSD.SPECIAL_CATEGORY
+				HAVING SUM(SSD.ROUND_WIN_COUNT) > 0
+			`, where)
 		default:
-			return resultHeaders, resultRows, errors.New("invalid subject provided")
+			return resultHeaders, resultRows, 0, errors.New("invalid subject provided")
 		}
 	case "picked-player":
 		switch subject {
@@ -609,111 +659,104 @@ func GetStatsLeaderboard(userId uuid.UUID, timeframe string, topic string, subje
 			resultHeaders = append(resultHeaders, "Winner")
 			resultHeaders = append(resultHeaders, "Judge Who Picked")
 			resultHeaders = append(resultHeaders, "Count")
+			where := filter.where(&params, "SPD.DAY", "SPD.LOBBY_ID", "SPD.JUDGE_USER_ID")
 			params = append(params, userId)
+			orderBy = "COUNT DESC, NAME ASC"
 			sqlString = fmt.Sprintf(`
 				SELECT
 					UP.NAME AS PLAYER_NAME,
 					UJ.NAME AS NAME,
-					COUNT(DISTINCT LRC.ROUND_ID) AS COUNT
-				FROM LOG_RESPONSE_CARD AS LRC
-					INNER JOIN LOG_WIN AS LW ON LW.RESPONSE_ID = LRC.RESPONSE_ID
-					INNER JOIN USER AS UJ ON UJ.ID = LRC.JUDGE_USER_ID
-					INNER JOIN USER AS UP ON UP.ID = LRC.PLAYER_USER_ID
-				WHERE LRC.CREATED_ON_DATE >= %s
-					AND UP.ID = ?
-				GROUP BY LRC.JUDGE_USER_ID,
-					LRC.PLAYER_USER_ID
-				ORDER BY COUNT DESC,
-					NAME ASC
-				LIMIT 10
-			`, timeframeDateString)
+					SUM(SPD.ROUND_WIN_COUNT) AS COUNT
+				FROM V_STAT_PICK_DAY AS SPD
+					INNER JOIN USER AS UJ ON UJ.ID = SPD.JUDGE_USER_ID
+					INNER JOIN USER AS UP ON UP.ID = SPD.PLAYER_USER_ID
+				WHERE %s
+					AND SPD.PLAYER_USER_ID = ?
+				GROUP BY SPD.JUDGE_USER_ID,
+					SPD.PLAYER_USER_ID
+			`, where)
 		case "card":
 			resultHeaders = append(resultHeaders, "Winner")
 			resultHeaders = append(resultHeaders, "Card Played")
 			resultHeaders = append(resultHeaders, "Count")
+			where := filter.where(&params, "SPCD.DAY", "SPCD.LOBBY_ID", "SPCD.JUDGE_USER_ID")
 			params = append(params, userId)
+			orderBy = "COUNT DESC, NAME ASC"
 			sqlString = fmt.Sprintf(`
 				SELECT
 					UP.NAME AS PLAYER_NAME,
 					CJ.TEXT AS NAME,
-					COUNT(DISTINCT LRC.ROUND_ID) AS COUNT
-				FROM LOG_RESPONSE_CARD AS LRC
-					INNER JOIN LOG_WIN AS LW ON LW.RESPONSE_ID = LRC.RESPONSE_ID
-					INNER JOIN CARD AS CJ ON CJ.ID = LRC.PLAYER_CARD_ID
-					INNER JOIN USER AS UP ON UP.ID = LRC.PLAYER_USER_ID
-				WHERE LRC.CREATED_ON_DATE >= %s
-					AND UP.ID = ?
-				GROUP BY LRC.PLAYER_CARD_ID,
-					LRC.PLAYER_USER_ID
-				ORDER BY COUNT DESC,
-					NAME ASC
-				LIMIT 10
-			`, timeframeDateString)
+					SUM(SPCD.ROUND_WIN_COUNT) AS COUNT
+				FROM V_STAT_PICK_CARD_DAY AS SPCD
+					INNER JOIN CARD AS CJ ON CJ.ID = SPCD.CARD_ID
+					INNER JOIN USER AS UP ON UP.ID = SPCD.PLAYER_USER_ID
+				WHERE %s
+					AND SPCD.PLAYER_USER_ID = ?
+				GROUP BY SPCD.CARD_ID,
+					SPCD.PLAYER_USER_ID
+			`, where)
 		case "special-category":
 			resultHeaders = append(resultHeaders, "Winner")
 			resultHeaders = append(resultHeaders, "Special Category Played")
 			resultHeaders = append(resultHeaders, "Count")
+			where := filter.where(&params, "SSD.DAY", "SSD.LOBBY_ID", "SSD.JUDGE_USER_ID")
 			params = append(params, userId)
+			orderBy = "COUNT DESC, NAME ASC"
 			sqlString = fmt.Sprintf(`
 				SELECT
 					UP.NAME AS PLAYER_NAME,
-					COALESCE(LRC.SPECIAL_CATEGORY, 'NONE') AS NAME,
-					COUNT(DISTINCT LRC.ROUND_ID) AS COUNT
-				FROM LOG_RESPONSE_CARD AS LRC
-					INNER JOIN LOG_WIN AS LW ON LW.RESPONSE_ID = LRC.RESPONSE_ID
-					INNER JOIN USER AS UP ON UP.ID = LRC.PLAYER_USER_ID
-				WHERE LRC.CREATED_ON_DATE >= %s
-					AND UP.ID = ?
-				GROUP BY LRC.SPECIAL_CATEGORY,
-					LRC.PLAYER_USER_ID
-				ORDER BY COUNT DESC,
-					NAME ASC
-				LIMIT 10
-			`, timeframeDateString)
+					SSD.SPECIAL_CATEGORY AS NAME,
+					SUM(SSD.ROUND_WIN_COUNT) AS COUNT
+				FROM V_STAT_SPECIAL_DAY AS SSD
+					INNER JOIN USER AS UP ON UP.ID = SSD.PLAYER_USER_ID
+				WHERE %s
+					AND SSD.PLAYER_USER_ID = ?
+				GROUP BY SSD.SPECIAL_CATEGORY,
+					SSD.PLAYER_USER_ID
+				HAVING SUM(SSD.ROUND_WIN_COUNT) > 0
+			`, where)
 		default:
-			return resultHeaders, resultRows, errors.New("invalid subject provided")
+			return resultHeaders, resultRows, 0, errors.New("invalid subject provided")
 		}
 	case "credits-spent":
 		switch subject {
 		case "player":
 			resultHeaders = append(resultHeaders, "Credits Spent")
 			resultHeaders = append(resultHeaders, "Player")
+			where := filter.where(&params, "SCRD.DAY", "SCRD.LOBBY_ID", "SCRD.USER_ID")
+			orderBy = "COUNT DESC, NAME ASC"
 			sqlString = fmt.Sprintf(`
 				SELECT
-					SUM(LCS.AMOUNT) AS COUNT,
+					SUM(SCRD.SPENT_AMOUNT) AS COUNT,
 					U.NAME AS NAME
-				FROM LOG_CREDITS_SPENT AS LCS
-					INNER JOIN USER AS U ON U.ID = LCS.USER_ID
-				WHERE LCS.CREATED_ON_DATE >= %s
-					AND LCS.AMOUNT > 0
+				FROM V_STAT_CREDITS_DAY AS SCRD
+					INNER JOIN USER AS U ON U.ID = SCRD.USER_ID
+				WHERE %s
 				GROUP BY U.ID
-				ORDER BY COUNT DESC,
-					NAME ASC
-				LIMIT 10
-			`, timeframeDateString)
+				HAVING SUM(SCRD.SPENT_AMOUNT) > 0
+			`, where)
 		default:
-			return resultHeaders, resultRows, errors.New("invalid subject provided")
+			return resultHeaders, resultRows, 0, errors.New("invalid subject provided")
 		}
 	case "credits-earned":
 		switch subject {
 		case "player":
 			resultHeaders = append(resultHeaders, "Credits Earned")
 			resultHeaders = append(resultHeaders, "Player")
+			where := filter.where(&params, "SCRD.DAY", "SCRD.LOBBY_ID", "SCRD.USER_ID")
+			orderBy = "COUNT DESC, NAME ASC"
 			sqlString = fmt.Sprintf(`
 				SELECT
-					SUM(LCS.AMOUNT) * -1 AS COUNT,
+					SUM(SCRD.EARNED_AMOUNT) AS COUNT,
 					U.NAME AS NAME
-				FROM LOG_CREDITS_SPENT AS LCS
-					INNER JOIN USER AS U ON U.ID = LCS.USER_ID
-				WHERE LCS.CREATED_ON_DATE >= %s
-					AND LCS.AMOUNT < 0
+				FROM V_STAT_CREDITS_DAY AS SCRD
+					INNER JOIN USER AS U ON U.ID = SCRD.USER_ID
+				WHERE %s
 				GROUP BY U.ID
-				ORDER BY COUNT DESC,
-					NAME ASC
-				LIMIT 10
-			`, timeframeDateString)
+				HAVING SUM(SCRD.EARNED_AMOUNT) > 0
+			`, where)
 		default:
-			return resultHeaders, resultRows, errors.New("invalid subject provided")
+			return resultHeaders, resultRows, 0, errors.New("invalid subject provided")
 		}
 	case "credits-spent-category":
 		switch subject {
@@ -721,24 +764,22 @@ func GetStatsLeaderboard(userId uuid.UUID, timeframe string, topic string, subje
 			resultHeaders = append(resultHeaders, "Credits Spent")
 			resultHeaders = append(resultHeaders, "Category")
 			resultHeaders = append(resultHeaders, "Player")
+			where := filter.where(&params, "SCRD.DAY", "SCRD.LOBBY_ID", "SCRD.USER_ID")
+			orderBy = "COUNT DESC, NAME ASC, CATEGORY ASC"
 			sqlString = fmt.Sprintf(`
 				SELECT
-					SUM(LCS.AMOUNT) AS COUNT,
-					LCS.CATEGORY AS CATEGORY,
+					SUM(SCRD.SPENT_AMOUNT) AS COUNT,
+					SCRD.CATEGORY AS CATEGORY,
 					U.NAME AS NAME
-				FROM LOG_CREDITS_SPENT AS LCS
-					INNER JOIN USER AS U ON U.ID = LCS.USER_ID
-				WHERE LCS.CREATED_ON_DATE >= %s
-					AND LCS.AMOUNT > 0
+				FROM V_STAT_CREDITS_DAY AS SCRD
+					INNER JOIN USER AS U ON U.ID = SCRD.USER_ID
+				WHERE %s
 				GROUP BY U.ID,
-					LCS.CATEGORY
-				ORDER BY COUNT DESC,
-					NAME ASC,
-					CATEGORY ASC
-				LIMIT 10
-			`, timeframeDateString)
+					SCRD.CATEGORY
+				HAVING SUM(SCRD.SPENT_AMOUNT) > 0
+			`, where)
 		default:
-			return resultHeaders, resultRows, errors.New("invalid subject provided")
+			return resultHeaders, resultRows, 0, errors.New("invalid subject provided")
 		}
 	case "credits-earned-category":
 		switch subject {
@@ -746,200 +787,230 @@ func GetStatsLeaderboard(userId uuid.UUID, timeframe string, topic string, subje
 			resultHeaders = append(resultHeaders, "Credits Earned")
 			resultHeaders = append(resultHeaders, "Category")
 			resultHeaders = append(resultHeaders, "Player")
+			where := filter.where(&params, "SCRD.DAY", "SCRD.LOBBY_ID", "SCRD.USER_ID")
+			orderBy = "COUNT DESC, NAME ASC, CATEGORY ASC"
 			sqlString = fmt.Sprintf(`
 				SELECT
-					SUM(LCS.AMOUNT) * -1 AS COUNT,
-					LCS.CATEGORY AS CATEGORY,
+					SUM(SCRD.EARNED_AMOUNT) AS COUNT,
+					SCRD.CATEGORY AS CATEGORY,
 					U.NAME AS NAME
-				FROM LOG_CREDITS_SPENT AS LCS
-					INNER JOIN USER AS U ON U.ID = LCS.USER_ID
-				WHERE LCS.CREATED_ON_DATE >= %s
-					AND LCS.AMOUNT < 0
+				FROM V_STAT_CREDITS_DAY AS SCRD
+					INNER JOIN USER AS U ON U.ID = SCRD.USER_ID
+				WHERE %s
 				GROUP BY U.ID,
-					LCS.CATEGORY
-				ORDER BY COUNT DESC,
-					NAME ASC,
-					CATEGORY ASC
-				LIMIT 10
-			`, timeframeDateString)
+					SCRD.CATEGORY
+				HAVING SUM(SCRD.EARNED_AMOUNT) > 0
+			`, where)
 		default:
-			return resultHeaders, resultRows, errors.New("invalid subject provided")
+			return resultHeaders, resultRows, 0, errors.New("invalid subject provided")
 		}
 	case "credits-spent-game":
 		switch subject {
 		case "player":
 			resultHeaders = append(resultHeaders, "Credits Spent in a Game")
 			resultHeaders = append(resultHeaders, "Player")
+			where := filter.where(&params, "SCRD.DAY", "SCRD.LOBBY_ID", "SCRD.USER_ID")
+			orderBy = "COUNT DESC, NAME ASC"
 			sqlString = fmt.Sprintf(`
 				SELECT
-					SUM(LCS.AMOUNT) AS COUNT,
+					SUM(SCRD.SPENT_AMOUNT) AS COUNT,
 					U.NAME AS NAME
-				FROM LOG_CREDITS_SPENT AS LCS
-					INNER JOIN USER AS U ON U.ID = LCS.USER_ID
-				WHERE LCS.CREATED_ON_DATE >= %s
-					AND LCS.AMOUNT > 0
+				FROM V_STAT_CREDITS_DAY AS SCRD
+					INNER JOIN USER AS U ON U.ID = SCRD.USER_ID
+				WHERE %s
 				GROUP BY U.ID,
-					LCS.LOBBY_ID
-				ORDER BY COUNT DESC,
-					NAME ASC
-				LIMIT 10
-			`, timeframeDateString)
+					SCRD.LOBBY_ID
+				HAVING SUM(SCRD.SPENT_AMOUNT) > 0
+			`, where)
 		default:
-			return resultHeaders, resultRows, errors.New("invalid subject provided")
+			return resultHeaders, resultRows, 0, errors.New("invalid subject provided")
 		}
 	case "credits-earned-game":
 		switch subject {
 		case "player":
 			resultHeaders = append(resultHeaders, "Credits Earned in a Game")
 			resultHeaders = append(resultHeaders, "Player")
+			where := filter.where(&params, "SCRD.DAY", "SCRD.LOBBY_ID", "SCRD.USER_ID")
+			orderBy = "COUNT DESC, NAME ASC"
 			sqlString = fmt.Sprintf(`
 				SELECT
-					SUM(LCS.AMOUNT) * -1 AS COUNT,
+					SUM(SCRD.EARNED_AMOUNT) AS COUNT,
 					U.NAME AS NAME
-				FROM LOG_CREDITS_SPENT AS LCS
-					INNER JOIN USER AS U ON U.ID = LCS.USER_ID
-				WHERE LCS.CREATED_ON_DATE >= %s
-					AND LCS.AMOUNT < 0
+				FROM V_STAT_CREDITS_DAY AS SCRD
+					INNER JOIN USER AS U ON U.ID = SCRD.USER_ID
+				WHERE %s
 				GROUP BY U.ID,
-					LCS.LOBBY_ID
-				ORDER BY COUNT DESC,
-					NAME ASC
-				LIMIT 10
-			`, timeframeDateString)
+					SCRD.LOBBY_ID
+				HAVING SUM(SCRD.EARNED_AMOUNT) > 0
+			`, where)
 		default:
-			return resultHeaders, resultRows, errors.New("invalid subject provided")
+			return resultHeaders, resultRows, 0, errors.New("invalid subject provided")
 		}
 	case "gamble":
 		switch subject {
 		case "player":
 			resultHeaders = append(resultHeaders, "Gamble")
 			resultHeaders = append(resultHeaders, "Player")
+			where := filter.where(&params, "SCRD.DAY", "SCRD.LOBBY_ID", "SCRD.USER_ID")
+			orderBy = "COUNT DESC, NAME ASC"
 			sqlString = fmt.Sprintf(`
 				SELECT
-					MAX(LCS.AMOUNT) AS COUNT,
+					MAX(SCRD.MAX_AMOUNT) AS COUNT,
 					U.NAME AS NAME
-				FROM LOG_CREDITS_SPENT AS LCS
-					INNER JOIN USER AS U ON U.ID = LCS.USER_ID
-				WHERE LCS.CREATED_ON_DATE >= %s
-					AND LCS.CATEGORY = 'GAMBLE'
+				FROM V_STAT_CREDITS_DAY AS SCRD
+					INNER JOIN USER AS U ON U.ID = SCRD.USER_ID
+				WHERE %s
+					AND SCRD.CATEGORY = 'GAMBLE'
 				GROUP BY U.ID
-				ORDER BY COUNT DESC,
-					NAME ASC
-				LIMIT 10
-			`, timeframeDateString)
+			`, where)
 		default:
-			return resultHeaders, resultRows, errors.New("invalid subject provided")
+			return resultHeaders, resultRows, 0, errors.New("invalid subject provided")
 		}
 	case "gamble-win":
 		switch subject {
 		case "player":
 			resultHeaders = append(resultHeaders, "Gamble Win")
 			resultHeaders = append(resultHeaders, "Player")
+			where := filter.where(&params, "SCRD.DAY", "SCRD.LOBBY_ID", "SCRD.USER_ID")
+			orderBy = "COUNT DESC, NAME ASC"
 			sqlString = fmt.Sprintf(`
 				SELECT
-					MIN(LCS.AMOUNT) * -1 AS COUNT,
+					MIN(SCRD.MIN_AMOUNT) * -1 AS COUNT,
 					U.NAME AS NAME
-				FROM LOG_CREDITS_SPENT AS LCS
-					INNER JOIN USER AS U ON U.ID = LCS.USER_ID
-				WHERE LCS.CREATED_ON_DATE >= %s
-					AND LCS.CATEGORY = 'GAMBLE-WIN'
+				FROM V_STAT_CREDITS_DAY AS SCRD
+					INNER JOIN USER AS U ON U.ID = SCRD.USER_ID
+				WHERE %s
+					AND SCRD.CATEGORY = 'GAMBLE-WIN'
 				GROUP BY U.ID
-				ORDER BY COUNT DESC,
-					NAME ASC
-				LIMIT 10
-			`, timeframeDateString)
+			`, where)
 		default:
-			return resultHeaders, resultRows, errors.New("invalid subject provided")
+			return resultHeaders, resultRows, 0, errors.New("invalid subject provided")
 		}
 	case "bet":
 		switch subject {
 		case "player":
 			resultHeaders = append(resultHeaders, "Bet")
 			resultHeaders = append(resultHeaders, "Player")
+			where := filter.where(&params, "SCRD.DAY", "SCRD.LOBBY_ID", "SCRD.USER_ID")
+			orderBy = "COUNT DESC, NAME ASC"
 			sqlString = fmt.Sprintf(`
 				SELECT
-					MAX(LCS.AMOUNT) AS COUNT,
+					MAX(SCRD.MAX_AMOUNT) AS COUNT,
 					U.NAME AS NAME
-				FROM LOG_CREDITS_SPENT AS LCS
-					INNER JOIN USER AS U ON U.ID = LCS.USER_ID
-				WHERE LCS.CREATED_ON_DATE >= %s
-					AND LCS.CATEGORY = 'BET'
+				FROM V_STAT_CREDITS_DAY AS SCRD
+					INNER JOIN USER AS U ON U.ID = SCRD.USER_ID
+				WHERE %s
+					AND SCRD.CATEGORY = 'BET'
 				GROUP BY U.ID
-				ORDER BY COUNT DESC,
-					NAME ASC
-				LIMIT 10
-			`, timeframeDateString)
+			`, where)
 		default:
-			return resultHeaders, resultRows, errors.New("invalid subject provided")
+			return resultHeaders, resultRows, 0, errors.New("invalid subject provided")
 		}
 	case "bet-win":
 		switch subject {
 		case "player":
 			resultHeaders = append(resultHeaders, "Bet Win")
 			resultHeaders = append(resultHeaders, "Player")
+			where := filter.where(&params, "SCRD.DAY", "SCRD.LOBBY_ID", "SCRD.USER_ID")
+			orderBy = "COUNT DESC, NAME ASC"
 			sqlString = fmt.Sprintf(`
 				SELECT
-					MIN(LCS.AMOUNT) * -1 AS COUNT,
+					MIN(SCRD.MIN_AMOUNT) * -1 AS COUNT,
 					U.NAME AS NAME
-				FROM LOG_CREDITS_SPENT AS LCS
-					INNER JOIN USER AS U ON U.ID = LCS.USER_ID
-				WHERE LCS.CREATED_ON_DATE >= %s
-					AND LCS.CATEGORY = 'BET-WIN'
+				FROM V_STAT_CREDITS_DAY AS SCRD
+					INNER JOIN USER AS U ON U.ID = SCRD.USER_ID
+				WHERE %s
+					AND SCRD.CATEGORY = 'BET-WIN'
 				GROUP BY U.ID
-				ORDER BY COUNT DESC,
-					NAME ASC
-				LIMIT 10
-			`, timeframeDateString)
+			`, where)
 		default:
-			return resultHeaders, resultRows, errors.New("invalid subject provided")
+			return resultHeaders, resultRows, 0, errors.New("invalid subject provided")
 		}
 	case "kick":
 		switch subject {
 		case "player":
 			resultHeaders = append(resultHeaders, "Kicked")
 			resultHeaders = append(resultHeaders, "Player")
+			where := filter.where(&params, "SUD.DAY", "SUD.LOBBY_ID", "SUD.USER_ID")
+			orderBy = "COUNT DESC, NAME ASC"
 			sqlString = fmt.Sprintf(`
 				SELECT
-					COUNT(*) AS COUNT,
+					SUM(SUD.KICK_COUNT) AS COUNT,
 					U.NAME AS NAME
-				FROM LOG_KICK AS LK
-					INNER JOIN USER AS U ON U.ID = LK.USER_ID
-				WHERE LK.CREATED_ON_DATE >= %s
+				FROM V_STAT_USER_DAY AS SUD
+					INNER JOIN USER AS U ON U.ID = SUD.USER_ID
+				WHERE %s
 				GROUP BY U.ID
-				ORDER BY COUNT DESC,
-					NAME ASC
-				LIMIT 10
-			`, timeframeDateString)
+				HAVING SUM(SUD.KICK_COUNT) > 0
+			`, where)
 		default:
-			return resultHeaders, resultRows, errors.New("invalid subject provided")
+			return resultHeaders, resultRows, 0, errors.New("invalid subject provided")
 		}
 	case "flip-table":
 		switch subject {
 		case "player":
 			resultHeaders = append(resultHeaders, "Flipped Tables")
 			resultHeaders = append(resultHeaders, "Player")
+			where := filter.where(&params, "SUD.DAY", "SUD.LOBBY_ID", "SUD.USER_ID")
+			orderBy = "COUNT DESC, NAME ASC"
 			sqlString = fmt.Sprintf(`
 				SELECT
-					COUNT(*) AS COUNT,
+					SUM(SUD.FLIP_TABLE_COUNT) AS COUNT,
 					U.NAME AS NAME
-				FROM LOG_FLIP_TABLE AS LFT
-					INNER JOIN USER AS U ON U.ID = LFT.USER_ID
-				WHERE LFT.CREATED_ON_DATE >= %s
+				FROM V_STAT_USER_DAY AS SUD
+					INNER JOIN USER AS U ON U.ID = SUD.USER_ID
+				WHERE %s
 				GROUP BY U.ID
-				ORDER BY COUNT DESC,
-					NAME ASC
-				LIMIT 10
-			`, timeframeDateString)
+				HAVING SUM(SUD.FLIP_TABLE_COUNT) > 0
+			`, where)
 		default:
-			return resultHeaders, resultRows, errors.New("invalid subject provided")
+			return resultHeaders, resultRows, 0, errors.New("invalid subject provided")
 		}
 	default:
-		return resultHeaders, resultRows, errors.New("invalid topic provided")
+		return resultHeaders, resultRows, 0, errors.New("invalid topic provided")
+	}
+
+	var rowCount int
+	rows, err := query(fmt.Sprintf(`
+		SELECT
+			COUNT(*)
+		FROM (%s) AS LEADERBOARD
+	`, sqlString), params...)
+	if err != nil {
+		return resultHeaders, resultRows, 0, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err := rows.Scan(&rowCount); err != nil {
+			log.Println(err)
+			return resultHeaders, resultRows, 0, errors.New("failed to scan row in query results")
+		}
+	}
+
+	// the sort column is only ever a number within the headers, never user text
+	if filter.SortColumn >= 1 && filter.SortColumn <= len(resultHeaders) {
+		direction := "ASC"
+		if filter.SortDescending {
+			direction = "DESC"
+		}
+		orderBy = fmt.Sprintf("%d %s, %s", filter.SortColumn, direction, orderBy)
+	}
+
+	sqlString = fmt.Sprintf(`
+		SELECT
+			*
+		FROM (%s) AS LEADERBOARD
+		ORDER BY %s
+	`, sqlString, orderBy)
+	if filter.Page >= 1 {
+		sqlString += "LIMIT 10 OFFSET ?"
+		params = append(params, (filter.Page-1)*10)
 	}
 
-	rows, err := query(sqlString, params...)
+	rows, err = query(sqlString, params...)
 	if err != nil {
-		return resultHeaders, resultRows, err
+		return resultHeaders, resultRows, 0, err
 	}
 	defer rows.Close()
 
@@ -951,7 +1022,7 @@ func GetStatsLeaderboard(userId uuid.UUID, timeframe string, topic string, subje
 	for rows.Next() {
 		if err := rows.Scan(rowValuePointers...); err != nil {
 			log.Println(err)
-			return resultHeaders, resultRows, errors.New("failed to scan row in query results")
+			return resultHeaders, resultRows, 0, errors.New("failed to scan row in query results")
 		}
 
 		row := make([]string, len(resultHeaders))
@@ -961,7 +1032,7 @@ func GetStatsLeaderboard(userId uuid.UUID, timeframe string, topic string, subje
 		resultRows = append(resultRows, row)
 	}
 
-	return resultHeaders, resultRows, nil
+	return resultHeaders, resultRows, rowCount, nil
 }
 
 func GetStatsUser(userId uuid.UUID) (StatUser, error) {
@@ -1058,126 +1129,6 @@ func GetStatsUser(userId uuid.UUID) (StatUser, error) {
 	return result, nil
 }
 
-func GetAchievementsUser(userId uuid.UUID) ([]Achievement, error) {
-	var result []Achievement
-
-	sqlString := `
-		WITH CREDITS_SPENT AS (
-				SELECT
-					CATEGORY
-				FROM LOG_CREDITS_SPENT
-				WHERE USER_ID = ?
-			)
-		SELECT
-			'Games Won',
-			(SELECT COUNT(DISTINCT LOBBY_ID) FROM V_GAME_WINNER WHERE USER_ID = ?)
-		UNION
-		SELECT
-			'Games Played',
-			(SELECT COUNT(DISTINCT LOBBY_ID) FROM LOG_RESPONSE_CARD WHERE PLAYER_USER_ID = ?)
-		UNION
-		SELECT
-			'Winning Streaks',
-			(SELECT COUNT(*) FROM CREDITS_SPENT WHERE CATEGORY = 'WINNING-STREAK')
-		UNION
-		SELECT
-			'Losing Streaks',
-			(SELECT COUNT(*) FROM CREDITS_SPENT WHERE CATEGORY = 'LOSING-STREAK')
-		UNION
-		SELECT
-			'Skipped Being Judge',
-			(SELECT COUNT(*) FROM CREDITS_SPENT WHERE CATEGORY = 'SKIP-JUDGE')
-		UNION
-		SELECT
-			'Lobby Alerts',
-			(SELECT COUNT(*) FROM CREDITS_SPENT WHERE CATEGORY = 'ALERT')
-		UNION
-		SELECT
-			'Gambles Made',
-			(SELECT COUNT(*) FROM CREDITS_SPENT WHERE CATEGORY = 'GAMBLE')
-		UNION
-		SELECT
-			'Gambles Won',
-			(SELECT COUNT(*) FROM CREDITS_SPENT WHERE CATEGORY = 'GAMBLE-WIN')
-		UNION
-		SELECT
-			'Bets Placed',
-			(SELECT COUNT(*) FROM CREDITS_SPENT WHERE CATEGORY = 'BET')
-		UNION
-		SELECT
-			'Bets Won',
-			(SELECT COUNT(*) FROM CREDITS_SPENT WHERE CATEGORY = 'BET-WIN')
-		UNION
-		SELECT
-			'Extra Responses',
-			(SELECT COUNT(*) FROM CREDITS_SPENT WHERE CATEGORY = 'EXTRA-RESPONSE')
-		UNION
-		SELECT
-			'Blocked Responses',
-			(SELECT COUNT(*) FROM CREDITS_SPENT WHERE CATEGORY = 'BLOCK-RESPONSE')
-		UNION
-		SELECT
-			'Stolen Cards Played',
-			(SELECT COUNT(*) FROM CREDITS_SPENT WHERE CATEGORY = 'STEAL')
-		UNION
-		SELECT
-			'Surprise Cards Played',
-			(SELECT COUNT(*) FROM CREDITS_SPENT WHERE CATEGORY = 'SURPRISE')
-		UNION
-		SELECT
-			'Find Cards Played',
-			(SELECT COUNT(*) FROM CREDITS_SPENT WHERE CATEGORY = 'FIND')
-		UNION
-		SELECT
-			'Wild Cards Played',
-			(SELECT COUNT(*) FROM CREDITS_SPENT WHERE CATEGORY = 'WILD')
-		UNION
-		SELECT
-			'Perks Purchased',
-			(SELECT COUNT(*) FROM CREDITS_SPENT WHERE CATEGORY = 'PERK')
-		UNION
-		SELECT
-			'Cards Discarded',
-			(SELECT COUNT(*) FROM LOG_DISCARD WHERE USER_ID = ?)
-		UNION
-		SELECT
-			'Prompts Skipped',
-			(SELECT COUNT(*) FROM LOG_SKIP WHERE USER_ID = ?)
-		UNION
-		SELECT
-			'Kicked From Lobby',
-			(SELECT COUNT(*) FROM LOG_KICK WHERE USER_ID = ?)
-		UNION
-		SELECT
-			'Flipped Tables',
-			(SELECT COUNT(*) FROM LOG_FLIP_TABLE WHERE USER_ID = ?)
-	`
-	rows, err := query(sqlString, userId, userId, userId, userId, userId, userId, userId)
-	if err != nil {
-		return result, err
-	}
-	defer rows.Close()
-
-	for rows.Next() {
-		var achievement Achievement
-		if err := rows.Scan(
-			&achievement.Name,
-			&achievement.Progress,
-		); err != nil {
-			log.Println(err)
-			return result, errors.New("failed to scan row in query results")
-		}
-
-		if achievement.Progress >= 100 {
-			achievement.Progress = 100
-		}
-
-		result = append(result, achievement)
-	}
-
-	return result, nil
-}
-
 func GetStatsCard(cardId uuid.UUID) (StatCard, error) {
 	var result StatCard
 
@@ -0,0 +1,274 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CardSuggestion struct {
+	Id            uuid.UUID
+	CreatedOnDate time.Time
+	ChangedOnDate time.Time
+
+	DeckId     uuid.UUID
+	DeckName   string
+	CardId     uuid.NullUUID
+	UserId     uuid.UUID
+	UserName   string
+	Category   string
+	Text       string
+	YouTube    sql.NullString
+	Status     string
+	ReviewNote sql.NullString
+	IsSeen     bool
+
+	// current text of the card the suggestion edits
+	CardText sql.NullString
+}
+
+func CreateCardSuggestion(deckId uuid.UUID, cardId uuid.UUID, userId uuid.UUID, category string, text string, youtube string) error {
+	sqlString := `
+		INSERT INTO CARD_SUGGESTION(DECK_ID, CARD_ID, USER_ID, CATEGORY, TEXT, YOUTUBE)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+	var cardIdParam any
+	if cardId != uuid.Nil {
+		cardIdParam = cardId
+	}
+
+	var youtubeParam any
+	if len(youtube) != 0 {
+		youtubeParam = youtube
+	}
+
+	return execute(sqlString, deckId, cardIdParam, userId, category, text, youtubeParam)
+}
+
+func GetDeckCardSuggestions(deckId uuid.UUID) ([]CardSuggestion, error) {
+	return getCardSuggestions("CS.DECK_ID = ? AND CS.STATUS = 'PENDING'", deckId)
+}
+
+func GetUserCardSuggestions(userId uuid.UUID) ([]CardSuggestion, error) {
+	return getCardSuggestions("CS.USER_ID = ?", userId)
+}
+
+func GetCardSuggestion(id uuid.UUID) (CardSuggestion, error) {
+	var cardSuggestion CardSuggestion
+
+	cardSuggestions, err := getCardSuggestions("CS.ID = ?", id)
+	if err != nil {
+		return cardSuggestion, err
+	}
+
+	if len(cardSuggestions) == 0 {
+		return cardSuggestion, errors.New("card suggestion not found")
+	}
+
+	return cardSuggestions[0], nil
+}
+
+func getCardSuggestions(where string, id uuid.UUID) ([]CardSuggestion, error) {
+	sqlString := `
+		SELECT
+			CS.ID,
+			CS.CREATED_ON_DATE,
+			CS.CHANGED_ON_DATE,
+			CS.DECK_ID,
+			D.NAME AS DECK_NAME,
+			CS.CARD_ID,
+			CS.USER_ID,
+			U.NAME AS USER_NAME,
+			CS.CATEGORY,
+			CS.TEXT,
+			CS.YOUTUBE,
+			CS.STATUS,
+			CS.REVIEW_NOTE,
+			CS.IS_SEEN,
+			C.TEXT AS CARD_TEXT
+		FROM CARD_SUGGESTION AS CS
+			INNER JOIN DECK AS D ON D.ID = CS.DECK_ID
+			INNER JOIN USER AS U ON U.ID = CS.USER_ID
+			LEFT JOIN CARD AS C ON C.ID = CS.CARD_ID
+		WHERE ` + where + `
+		ORDER BY CS.CHANGED_ON_DATE DESC
+		LIMIT 50
+	`
+	rows, err := query(sqlString, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]CardSuggestion, 0)
+	for rows.Next() {
+		var cardSuggestion CardSuggestion
+		if err := rows.Scan(
+			&cardSuggestion.Id,
+			&cardSuggestion.CreatedOnDate,
+			&cardSuggestion.ChangedOnDate,
+			&cardSuggestion.DeckId,
+			&cardSuggestion.DeckName,
+			&cardSuggestion.CardId,
+			&cardSuggestion.UserId,
+			&cardSuggestion.UserName,
+			&cardSuggestion.Category,
+			&cardSuggestion.Text,
+			&cardSuggestion.YouTube,
+			&cardSuggestion.Status,
+			&cardSuggestion.ReviewNote,
+			&cardSuggestion.IsSeen,
+			&cardSuggestion.CardText,
+		); err != nil {
+			log.Println(err)
+			return nil, errors.New("failed to scan row in query results")
+		}
+		result = append(result, cardSuggestion)
+	}
+	return result, nil
+}
+
+// SetCardSuggestionReviewed records the outcome of a suggestion and marks it
+// unseen so the suggester is notified.
+func SetCardSuggestionReviewed(id uuid.UUID, reviewerUserId uuid.UUID, status string, reviewNote string) error {
+	sqlString := `
+		UPDATE CARD_SUGGESTION
+		SET CHANGED_ON_DATE = CURRENT_TIMESTAMP(6),
+			STATUS = ?,
+			REVIEW_NOTE = ?,
+			REVIEWED_BY_USER_ID = ?,
+			IS_SEEN = 0
+		WHERE ID = ?
+	`
+	if len(reviewNote) == 0 {
+		return execute(sqlString, status, nil, reviewerUserId, id)
+	}
+	return execute(sqlString, status, reviewNote, reviewerUserId, id)
+}
+
+// ApproveCardSuggestion applies the suggestion and marks it approved in one
+// transaction. An edit is only applied while the card is still in the deck the
+// reviewer was authorized against, and a suggestion is only ever applied once.
+func ApproveCardSuggestion(id uuid.UUID, reviewerUserId uuid.UUID, deckId uuid.UUID, cardId uuid.NullUUID, category string, text string, youtube string, reviewNote string) error {
+	var youtubeParam any
+	if len(youtube) != 0 {
+		youtubeParam = youtube
+	}
+
+	var reviewNoteParam any
+	if len(reviewNote) != 0 {
+		reviewNoteParam = reviewNote
+	}
+
+	return transaction(func(tx *sql.Tx) error {
+		if cardId.Valid {
+			rows, err := queryTx(tx, `
+				SELECT
+					DECK_ID
+				FROM CARD
+				WHERE ID = ?
+				FOR UPDATE
+			`, cardId.UUID)
+			if err != nil {
+				return err
+			}
+			defer rows.Close()
+
+			var cardDeckId uuid.UUID
+			for rows.Next() {
+				if err := rows.Scan(&cardDeckId); err != nil {
+					log.Println(err)
+					return errors.New("failed to scan row in query results")
+				}
+			}
+
+			if cardDeckId != deckId {
+				return errors.New("card has been moved or deleted")
+			}
+
+			_, err = executeTx(tx, `
+				UPDATE CARD
+				SET CATEGORY = ?,
+					TEXT = ?,
+					YOUTUBE = ?
+				WHERE ID = ?
+			`, category, text, youtubeParam, cardId.UUID)
+			if err != nil {
+				return err
+			}
+		} else {
+			newCardId, err := uuid.NewUUID()
+			if err != nil {
+				log.Println(err)
+				return errors.New("failed to generate new id")
+			}
+
+			_, err = executeTx(tx, `
+				INSERT INTO CARD(ID, DECK_ID, CATEGORY, TEXT, YOUTUBE)
+				VALUES (?, ?, ?, ?, ?)
+			`, newCardId, deckId, category, text, youtubeParam)
+			if err != nil {
+				return err
+			}
+		}
+
+		reviewedCount, err := executeTx(tx, `
+			UPDATE CARD_SUGGESTION
+			SET CHANGED_ON_DATE = CURRENT_TIMESTAMP(6),
+				STATUS = 'APPROVED',
+				REVIEW_NOTE = ?,
+				REVIEWED_BY_USER_ID = ?,
+				IS_SEEN = 0
+			WHERE ID = ?
+				AND STATUS = 'PENDING'
+		`, reviewNoteParam, reviewerUserId, id)
+		if err != nil {
+			return err
+		}
+
+		if reviewedCount == 0 {
+			return errors.New("suggestion has already been reviewed")
+		}
+
+		return nil
+	})
+}
+
+func CountUnseenCardSuggestions(userId uuid.UUID) (int, error) {
+	sqlString := `
+		SELECT
+			COUNT(*)
+		FROM CARD_SUGGESTION
+		WHERE USER_ID = ?
+			AND STATUS <> 'PENDING'
+			AND IS_SEEN = 0
+	`
+	rows, err := query(sqlString, userId)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	var count int
+	for rows.Next() {
+		if err := rows.Scan(&count); err != nil {
+			log.Println(err)
+			return 0, errors.New("failed to scan row in query results")
+		}
+	}
+
+	return count, nil
+}
+
+func SetCardSuggestionsSeen(userId uuid.UUID) error {
+	sqlString := `
+		UPDATE CARD_SUGGESTION
+		SET IS_SEEN = 1
+		WHERE USER_ID = ?
+			AND STATUS <> 'PENDING'
+	`
+	return execute(sqlString, userId)
+}
@@ -0,0 +1,263 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+type LobbyCardFilters struct {
+	MaxContentRating string
+	IncludeTags      []string
+	ExcludeTags      []string
+
+	// near duplicate cards are removed from the draw pile
+	DeduplicateCards bool
+}
+
+var tagInvalidRegExp = regexp.MustCompile(`[^a-z0-9]+`)
+
+// ParseTags splits comma separated text into tags of lower case words joined
+// by dashes, dropping duplicates.
+func ParseTags(text string) []string {
+	result := make([]string, 0)
+	for _, tag := range strings.Split(text, ",") {
+		tag = strings.Trim(tagInvalidRegExp.ReplaceAllString(strings.ToLower(tag), "-"), "-")
+		if len(tag) > 50 {
+			tag = strings.TrimRight(tag[:50], "-")
+		}
+
+		if tag == "" || slices.Contains(result, tag) {
+			continue
+		}
+
+		result = append(result, tag)
+	}
+	return result
+}
+
+// IsValidContentRating allows an empty rating, which means unrated.
+func IsValidContentRating(contentRating string) bool {
+	switch contentRating {
+	case "", "FAMILY", "TEEN", "MATURE":
+		return true
+	}
+	return false
+}
+
+func GetDeckTags(deckId uuid.UUID) ([]string, error) {
+	sqlString := `
+		SELECT DISTINCT
+			CT.TAG
+		FROM CARD_TAG AS CT
+			INNER JOIN CARD AS C ON C.ID = CT.CARD_ID
+		WHERE C.DECK_ID = ?
+		ORDER BY CT.TAG
+	`
+	rows, err := query(sqlString, deckId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]string, 0)
+	for rows.Next() {
+		var tag string
+		if err := rows.Scan(&tag); err != nil {
+			log.Println(err)
+			return nil, errors.New("failed to scan row in query results")
+		}
+		result = append(result, tag)
+	}
+	return result, nil
+}
+
+func SetCardTags(cardId uuid.UUID, tags []string) error {
+	sqlString := `
+		DELETE
+		FROM CARD_TAG
+		WHERE CARD_ID = ?
+	`
+	err := execute(sqlString, cardId)
+	if err != nil {
+		return err
+	}
+
+	if len(tags) == 0 {
+		return nil
+	}
+
+	sqlString = fmt.Sprintf(`
+		INSERT INTO CARD_TAG(CARD_ID, TAG)
+		VALUES %s
+	`, strings.Repeat("(?, ?),", len(tags)-1)+"(?, ?)")
+
+	args := make([]any, 0)
+	for _, tag := range tags {
+		args = append(args, cardId, tag)
+	}
+
+	return execute(sqlString, args...)
+}
+
+func SetCardContentRating(cardId uuid.UUID, contentRating string) error {
+	sqlString := `
+		UPDATE CARD
+		SET CONTENT_RATING = ?
+		WHERE ID = ?
+	`
+	if contentRating == "" {
+		return execute(sqlString, nil, cardId)
+	}
+	return execute(sqlString, contentRating, cardId)
+}
+
+func SetDeckContentRating(deckId uuid.UUID, contentRating string) error {
+	sqlString := `
+		UPDATE DECK
+		SET CONTENT_RATING = ?
+		WHERE ID = ?
+	`
+	if contentRating == "" {
+		return execute(sqlString, nil, deckId)
+	}
+	return execute(sqlString, contentRating, deckId)
+}
+
+// cardSearchWhere builds the filter shared by the deck card search and the
+// bulk card operations, with the text read as a card search query.
+func cardSearchWhere(deckId uuid.UUID, category string, text string, tag string) (string, []any) {
+	if category == "" {
+		category = "%"
+	}
+
+	queryWhere, queryArgs := parseCardQuery(text).where(cardColumns)
+
+	where := `C.DECK_ID = ?
+			AND C.CATEGORY LIKE ?
+			AND ` + queryWhere
+	args := append([]any{deckId, category}, queryArgs...)
+
+	if tag != "" {
+		where += `
+			AND EXISTS (SELECT 1 FROM CARD_TAG WHERE CARD_ID = C.ID AND TAG = ?)`
+		args = append(args, tag)
+	}
+
+	return where, args
+}
+
+func copyCardTags(tx *sql.Tx, sourceDeckId uuid.UUID, targetDeckId uuid.UUID) error {
+	sqlString := `
+		INSERT IGNORE INTO CARD_TAG(CARD_ID, TAG)
+		SELECT
+			T.ID,
+			CT.TAG
+		FROM CARD AS T
+			INNER JOIN CARD AS S ON S.ID = T.SOURCE_CARD_ID
+			INNER JOIN CARD_TAG AS CT ON CT.CARD_ID = S.ID
+		WHERE T.DECK_ID = ?
+			AND S.DECK_ID = ?
+	`
+	_, err := executeTx(tx, sqlString, targetDeckId, sourceDeckId)
+	return err
+}
+
+func SetLobbyCardFilters(lobbyId uuid.UUID, filters LobbyCardFilters) error {
+	sqlString := `
+		UPDATE LOBBY
+		SET MAX_CONTENT_RATING = ?,
+			DEDUPLICATE_CARDS = ?
+		WHERE ID = ?
+	`
+	var err error
+	if filters.MaxContentRating == "" {
+		err = execute(sqlString, nil, filters.DeduplicateCards, lobbyId)
+	} else {
+		err = execute(sqlString, filters.MaxContentRating, filters.DeduplicateCards, lobbyId)
+	}
+	if err != nil {
+		return err
+	}
+
+	sqlString = `
+		DELETE
+		FROM LOBBY_CARD_TAG
+		WHERE LOBBY_ID = ?
+	`
+	err = execute(sqlString, lobbyId)
+	if err != nil {
+		return err
+	}
+
+	sqlString = `
+		INSERT IGNORE INTO LOBBY_CARD_TAG(LOBBY_ID, TAG, FILTER)
+		VALUES (?, ?, ?)
+	`
+	for _, tag := range filters.IncludeTags {
+		err = execute(sqlString, lobbyId, tag, "INCLUDE")
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, tag := range filters.ExcludeTags {
+		err = execute(sqlString, lobbyId, tag, "EXCLUDE")
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func GetLobbyCardFilters(lobbyId uuid.UUID) (LobbyCardFilters, error) {
+	filters := LobbyCardFilters{
+		IncludeTags: make([]string, 0),
+		ExcludeTags: make([]string, 0),
+	}
+
+	sqlString := `
+		SELECT
+			COALESCE(L.MAX_CONTENT_RATING, '') AS MAX_CONTENT_RATING,
+			L.DEDUPLICATE_CARDS,
+			LCT.TAG,
+			LCT.FILTER
+		FROM LOBBY AS L
+			LEFT JOIN LOBBY_CARD_TAG AS LCT ON LCT.LOBBY_ID = L.ID
+		WHERE L.ID = ?
+		ORDER BY LCT.TAG
+	`
+	rows, err := query(sqlString, lobbyId)
+	if err != nil {
+		return filters, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tag sql.NullString
+		var filter sql.NullString
+		if err := rows.Scan(&filters.MaxContentRating, &filters.DeduplicateCards, &tag, &filter); err != nil {
+			log.Println(err)
+			return filters, errors.New("failed to scan row in query results")
+		}
+
+		if !tag.Valid {
+			continue
+		}
+
+		if filter.String == "INCLUDE" {
+			filters.IncludeTags = append(filters.IncludeTags, tag.String)
+		} else {
+			filters.ExcludeTags = append(filters.ExcludeTags, tag.String)
+		}
+	}
+
+	return filters, nil
+}
@@ -6,6 +6,7 @@ require (
 	github.com/go-sql-driver/mysql v1.8.1
 	github.com/google/uuid v1.6.0
 	golang.org/x/crypto v0.26.0
+	golang.org/x/image v0.18.0
 )
 
 require (
@@ -4,6 +4,7 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"strconv"
 	"time"
 
 	"github.com/grantfbarnes/card-judge/api"
@@ -47,6 +48,67 @@ func main() {
 		}
 	}
 
+	// backfill the stat rollups from the logs and exit instead of serving
+	if len(os.Args) > 1 && os.Args[1] == "rebuild-stats" {
+		err = database.RebuildStatRollups()
+		if err != nil {
+			log.Fatalln(err)
+			return
+		}
+
+		statRollupChecks, err := database.CheckStatRollups()
+		if err != nil {
+			log.Fatalln(err)
+			return
+		}
+
+		for _, statRollupCheck := range statRollupChecks {
+			log.Printf("%s: logs %d, rollups %d", statRollupCheck.Name, statRollupCheck.LogCount, statRollupCheck.RollupCount)
+		}
+		return
+	}
+
+	auditRetentionDays := 14
+	if os.Getenv("CARD_JUDGE_AUDIT_RETENTION_DAYS") != "" {
+		auditRetentionDays, err = strconv.Atoi(os.Getenv("CARD_JUDGE_AUDIT_RETENTION_DAYS"))
+		if err != nil {
+			log.Fatalln(err)
+			return
+		}
+	}
+
+	err = database.SetAuditRetentionDays(auditRetentionDays)
+	if err != nil {
+		log.Fatalln(err)
+		return
+	}
+
+	cardReportThreshold := 3
+	if os.Getenv("CARD_JUDGE_CARD_REPORT_THRESHOLD") != "" {
+		cardReportThreshold, err = strconv.Atoi(os.Getenv("CARD_JUDGE_CARD_REPORT_THRESHOLD"))
+		if err != nil {
+			log.Fatalln(err)
+			return
+		}
+	}
+
+	if cardReportThreshold < 1 {
+		log.Fatalln("card report threshold must be at least 1")
+		return
+	}
+
+	err = database.SetCardReportThreshold(cardReportThreshold)
+	if err != nil {
+		log.Fatalln(err)
+		return
+	}
+
+	err = database.SeedUserAchievements()
+	if err != nil {
+		log.Fatalln(err)
+		return
+	}
+
 	// static files
 	http.Handle("GET /static/", http.StripPrefix("/static/", http.FileServer(http.FS(static.StaticFiles))))
 
@@ -57,10 +119,18 @@ func main() {
 	http.Handle("GET /account", api.MiddlewareForPages(http.HandlerFunc(apiPages.Account)))
 	http.Handle("GET /stats", api.MiddlewareForPages(http.HandlerFunc(apiPages.Stats)))
 	http.Handle("GET /stats/leaderboard", api.MiddlewareForPages(http.HandlerFunc(apiPages.StatsLeaderboard)))
+	http.Handle("GET /stats/seasons", api.MiddlewareForPages(http.HandlerFunc(apiPages.StatsSeasons)))
+	http.Handle("GET /stats/season/{seasonId}", api.MiddlewareForPages(http.HandlerFunc(apiPages.StatsSeason)))
+	http.Handle("GET /stats/history", api.MiddlewareForPages(http.HandlerFunc(apiPages.StatsHistory)))
+	http.Handle("GET /stats/round/{roundId}", api.MiddlewareForPages(http.HandlerFunc(apiPages.StatsRound)))
 	http.Handle("GET /stats/users", api.MiddlewareForPages(http.HandlerFunc(apiPages.StatsUsers)))
 	http.Handle("GET /stats/user/{userId}", api.MiddlewareForPages(http.HandlerFunc(apiPages.StatsUser)))
+	http.Handle("GET /stats/user/{userId}/head-to-head/{otherUserId}", api.MiddlewareForPages(http.HandlerFunc(apiPages.StatsHeadToHead)))
 	http.Handle("GET /stats/cards", api.MiddlewareForPages(http.HandlerFunc(apiPages.StatsCards)))
 	http.Handle("GET /stats/card/{cardId}", api.MiddlewareForPages(http.HandlerFunc(apiPages.StatsCard)))
+	http.Handle("GET /stats/deck/{deckId}", api.MiddlewareForPages(http.HandlerFunc(apiPages.StatsDeck)))
+	http.Handle("GET /stats/combos", api.MiddlewareForPages(http.HandlerFunc(apiPages.StatsCombos)))
+	http.Handle("GET /stats/combo/{promptCardId}/{responseCardId}", api.MiddlewareForPages(http.HandlerFunc(apiPages.StatsCombo)))
 	http.Handle("GET /users", api.MiddlewareForPages(http.HandlerFunc(apiPages.Users)))
 	http.Handle("GET /review", api.MiddlewareForPages(http.HandlerFunc(apiPages.Review)))
 	http.Handle("GET /lobbies", api.MiddlewareForPages(http.HandlerFunc(apiPages.Lobbies)))
@@ -89,15 +159,33 @@ func main() {
 	http.Handle("PUT /api/deck/{deckId}/name", api.MiddlewareForAPIs(http.HandlerFunc(apiDeck.SetName)))
 	http.Handle("PUT /api/deck/{deckId}/password", api.MiddlewareForAPIs(http.HandlerFunc(apiDeck.SetPassword)))
 	http.Handle("PUT /api/deck/{deckId}/is-public-read-only", api.MiddlewareForAPIs(http.HandlerFunc(apiDeck.SetIsPublicReadOnly)))
+	http.Handle("PUT /api/deck/{deckId}/content-rating", api.MiddlewareForAPIs(http.HandlerFunc(apiDeck.SetContentRating)))
+	http.Handle("POST /api/deck/{deckId}/clone", api.MiddlewareForAPIs(http.HandlerFunc(apiDeck.Clone)))
+	http.Handle("POST /api/deck/{deckId}/merge", api.MiddlewareForAPIs(http.HandlerFunc(apiDeck.Merge)))
+	http.Handle("POST /api/deck/{deckId}/card-bulk", api.MiddlewareForAPIs(http.HandlerFunc(apiDeck.BulkEdit)))
+	http.Handle("POST /api/deck/{deckId}/card-bulk/preview", api.MiddlewareForAPIs(http.HandlerFunc(apiDeck.BulkReplacePreview)))
+	http.Handle("POST /api/deck/{deckId}/card-batch/{batchId}/undo", api.MiddlewareForAPIs(http.HandlerFunc(apiDeck.UndoCardBatch)))
+	http.Handle("PUT /api/deck/{deckId}/password-role", api.MiddlewareForAPIs(http.HandlerFunc(apiDeck.SetPasswordRole)))
+	http.Handle("POST /api/deck/{deckId}/access", api.MiddlewareForAPIs(http.HandlerFunc(apiDeck.InviteUser)))
+	http.Handle("PUT /api/deck/{deckId}/access/{userId}", api.MiddlewareForAPIs(http.HandlerFunc(apiDeck.SetUserRole)))
+	http.Handle("DELETE /api/deck/{deckId}/access/{userId}", api.MiddlewareForAPIs(http.HandlerFunc(apiDeck.RevokeUser)))
 	http.Handle("DELETE /api/deck/{deckId}", api.MiddlewareForAPIs(http.HandlerFunc(apiDeck.Delete)))
 
 	// card
 	http.Handle("POST /api/card/find", api.MiddlewareForAPIs(http.HandlerFunc(apiCard.Find)))
 	http.Handle("POST /api/card/create", api.MiddlewareForAPIs(http.HandlerFunc(apiCard.Create)))
+	http.Handle("POST /api/card/suggestion/create", api.MiddlewareForAPIs(http.HandlerFunc(apiCard.Suggest)))
+	http.Handle("POST /api/card/suggestion/{suggestionId}/approve", api.MiddlewareForAPIs(http.HandlerFunc(apiCard.ApproveSuggestion)))
+	http.Handle("POST /api/card/suggestion/{suggestionId}/reject", api.MiddlewareForAPIs(http.HandlerFunc(apiCard.RejectSuggestion)))
 	http.Handle("PUT /api/card/{cardId}", api.MiddlewareForAPIs(http.HandlerFunc(apiCard.Update)))
+	http.Handle("GET /card/{cardId}/image", api.MiddlewareForAPIs(http.HandlerFunc(apiCard.GetImage)))
 	http.Handle("PUT /api/card/{cardId}/image", api.MiddlewareForAPIs(http.HandlerFunc(apiCard.SetImage)))
+	http.Handle("PUT /api/card/{cardId}/tags", api.MiddlewareForAPIs(http.HandlerFunc(apiCard.SetTags)))
 	http.Handle("DELETE /api/card/{cardId}", api.MiddlewareForAPIs(http.HandlerFunc(apiCard.Delete)))
 
+	// card history
+	http.Handle("POST /api/card/history/{Id}/revert", api.MiddlewareForAPIs(http.HandlerFunc(apiCard.Revert)))
+
 	// review card
 	http.Handle("PUT /api/card/review/{Id}/recover", api.MiddlewareForAPIs(http.HandlerFunc(apiCard.Recover)))
 	http.Handle("DELETE /api/card/review/{Id}", api.MiddlewareForAPIs(http.HandlerFunc(apiCard.PermanentlyDelete)))
@@ -109,6 +197,7 @@ func main() {
 	http.Handle("GET /api/lobby/{lobbyId}/html/player-specials", api.MiddlewareForAPIs(http.HandlerFunc(apiLobby.GetPlayerSpecialsHTML)))
 	http.Handle("GET /api/lobby/{lobbyId}/html/lobby-game-board", api.MiddlewareForAPIs(http.HandlerFunc(apiLobby.GetLobbyGameBoardHTML)))
 	http.Handle("GET /api/lobby/{lobbyId}/html/lobby-game-stats", api.MiddlewareForAPIs(http.HandlerFunc(apiLobby.GetLobbyGameStatsHTML)))
+	http.Handle("GET /api/lobby/{lobbyId}/chat/history", api.MiddlewareForAPIs(http.HandlerFunc(apiLobby.GetChatHistory)))
 	http.Handle("POST /api/lobby/create", api.MiddlewareForAPIs(http.HandlerFunc(apiLobby.Create)))
 	http.Handle("POST /api/lobby/{lobbyId}/card/{cardId}/play", api.MiddlewareForAPIs(http.HandlerFunc(apiLobby.PlayCard)))
 	http.Handle("POST /api/lobby/{lobbyId}/card/force/play", api.MiddlewareForAPIs(http.HandlerFunc(apiLobby.PlayForceCard)))
@@ -131,9 +220,13 @@ func main() {
 	http.Handle("POST /api/lobby/{lobbyId}/perk/handicap-advantage", api.MiddlewareForAPIs(http.HandlerFunc(apiLobby.PerkHandicapAdvantage)))
 	http.Handle("POST /api/lobby/{lobbyId}/perk/spy-advantage", api.MiddlewareForAPIs(http.HandlerFunc(apiLobby.PerkSpyAdvantage)))
 	http.Handle("POST /api/lobby/{lobbyId}/response-card/{responseCardId}/withdraw", api.MiddlewareForAPIs(http.HandlerFunc(apiLobby.WithdrawCard)))
+	http.Handle("POST /api/lobby/{lobbyId}/response-card/{responseCardId}/move/{slot}", api.MiddlewareForAPIs(http.HandlerFunc(apiLobby.MoveResponseCard)))
 	http.Handle("POST /api/lobby/{lobbyId}/card/{cardId}/discard", api.MiddlewareForAPIs(http.HandlerFunc(apiLobby.DiscardCard)))
+	http.Handle("POST /api/lobby/{lobbyId}/card/report", api.MiddlewareForAPIs(http.HandlerFunc(apiLobby.ReportCard)))
 	http.Handle("POST /api/lobby/{lobbyId}/player/{playerId}/kick", api.MiddlewareForAPIs(http.HandlerFunc(apiLobby.VoteToKick)))
 	http.Handle("POST /api/lobby/{lobbyId}/player/{playerId}/kick/undo", api.MiddlewareForAPIs(http.HandlerFunc(apiLobby.VoteToKickUndo)))
+	http.Handle("POST /api/lobby/{lobbyId}/player/{playerId}/mute", api.MiddlewareForAPIs(http.HandlerFunc(apiLobby.MutePlayer)))
+	http.Handle("POST /api/lobby/{lobbyId}/player/{playerId}/mute/undo", api.MiddlewareForAPIs(http.HandlerFunc(apiLobby.UnmutePlayer)))
 	http.Handle("POST /api/lobby/{lobbyId}/response/{responseId}/reveal", api.MiddlewareForAPIs(http.HandlerFunc(apiLobby.RevealResponse)))
 	http.Handle("POST /api/lobby/{lobbyId}/response/{responseId}/toggle-rule-out", api.MiddlewareForAPIs(http.HandlerFunc(apiLobby.ToggleRuleOutResponse)))
 	http.Handle("POST /api/lobby/{lobbyId}/response/{responseId}/pick-winner", api.MiddlewareForAPIs(http.HandlerFunc(apiLobby.PickWinner)))
@@ -150,6 +243,8 @@ func main() {
 	http.Handle("PUT /api/lobby/{lobbyId}/free-special-cards", api.MiddlewareForAPIs(http.HandlerFunc(apiLobby.SetFreeSpecialCards)))
 	http.Handle("PUT /api/lobby/{lobbyId}/win-streak-threshold", api.MiddlewareForAPIs(http.HandlerFunc(apiLobby.SetWinStreakThreshold)))
 	http.Handle("PUT /api/lobby/{lobbyId}/lose-streak-threshold", api.MiddlewareForAPIs(http.HandlerFunc(apiLobby.SetLoseStreakThreshold)))
+	http.Handle("PUT /api/lobby/{lobbyId}/rules", api.MiddlewareForAPIs(http.HandlerFunc(apiLobby.SetRules)))
+	http.Handle("PUT /api/lobby/{lobbyId}/filter-words", api.MiddlewareForAPIs(http.HandlerFunc(apiLobby.SetFilterWords)))
 	http.Handle("PUT /api/lobby/{lobbyId}/response-count", api.MiddlewareForAPIs(http.HandlerFunc(apiLobby.SetResponseCount)))
 	http.Handle("PUT /api/lobby/{lobbyId}/set-decks", api.MiddlewareForAPIs(http.HandlerFunc(apiLobby.SetDecks)))
 
@@ -159,6 +254,16 @@ func main() {
 
 	// stats
 	http.Handle("POST /api/stats/leaderboard", api.MiddlewareForAPIs(http.HandlerFunc(apiStats.GetLeaderboard)))
+	http.Handle("POST /api/stats/rating/recompute", api.MiddlewareForAPIs(http.HandlerFunc(apiStats.RecomputeRatings)))
+	http.Handle("POST /api/stats/rollup/rebuild", api.MiddlewareForAPIs(http.HandlerFunc(apiStats.RebuildStatRollups)))
+	http.Handle("POST /api/stats/rollup/check", api.MiddlewareForAPIs(http.HandlerFunc(apiStats.CheckStatRollups)))
+	http.Handle("POST /api/stats/season/create", api.MiddlewareForAPIs(http.HandlerFunc(apiStats.CreateSeason)))
+	http.Handle("DELETE /api/stats/season/{seasonId}", api.MiddlewareForAPIs(http.HandlerFunc(apiStats.DeleteSeason)))
+	http.Handle("GET /api/stats/leaderboard/export", api.MiddlewareForAPIs(http.HandlerFunc(apiStats.GetLeaderboardExport)))
+	http.Handle("GET /api/stats/user/{userId}/export", api.MiddlewareForAPIs(http.HandlerFunc(apiStats.GetUserExport)))
+	http.Handle("GET /api/stats/user/{userId}/achievements/export", api.MiddlewareForAPIs(http.HandlerFunc(apiStats.GetUserAchievementsExport)))
+	http.Handle("GET /api/stats/user/{userId}/data-export", api.MiddlewareForAPIs(http.HandlerFunc(apiStats.GetUserDataExport)))
+	http.Handle("GET /api/stats/card/{cardId}/export", api.MiddlewareForAPIs(http.HandlerFunc(apiStats.GetCardExport)))
 
 	// websocket
 	http.HandleFunc("GET /ws/lobby/{lobbyId}", websocket.ServeWs)
@@ -177,6 +282,23 @@ func main() {
 		port = ":" + os.Getenv("CARD_JUDGE_PORT")
 	}
 
+	// ratings are updated when a round is won, this catches anything missed,
+	// and seasons are archived once they end whether or not anyone looks
+	go func() {
+		for {
+			err := database.UpdateUserRatings()
+			if err != nil {
+				log.Println(err)
+			}
+
+			err = database.ArchiveSeasons()
+			if err != nil {
+				log.Println(err)
+			}
+			time.Sleep(time.Hour)
+		}
+	}()
+
 	log.Println("server is running...")
 	if os.Getenv("CARD_JUDGE_CERT_FILE") != "" && os.Getenv("CARD_JUDGE_KEY_FILE") != "" {
 		err = http.ListenAndServeTLS(port, os.Getenv("CARD_JUDGE_CERT_FILE"), os.Getenv("CARD_JUDGE_KEY_FILE"), nil)
@@ -13,3 +13,9 @@ function downloadCSV(fileName, content) {
 	element.click();
 	document.body.removeChild(element);
 }
+
+function showBulkOperationInputs(operation) {
+	document.querySelectorAll("[data-bulk-operation]").forEach((element) => {
+		element.hidden = !element.dataset.bulkOperation.split(" ").includes(operation);
+	});
+}
@@ -20,7 +20,6 @@ window.onload = () => {
     };
 
     const lobbyChatForm = document.getElementById("lobby-chat-form");
-    const lobbyChatMessages = document.getElementById("lobby-chat-messages");
     const lobbyChatInput = document.getElementById("lobby-chat-input");
 
     lobbyChatForm.onsubmit = (event) => {
@@ -122,26 +121,73 @@ window.onload = () => {
             return;
         }
 
-        messageText = messageText.replaceAll("<red>", '<span class="lobby-chat-message-red">');
-        messageText = messageText.replaceAll("<green>", '<span class="lobby-chat-message-green">');
-        messageText = messageText.replaceAll("<blue>", '<span class="lobby-chat-message-blue">');
-        messageText = messageText.replaceAll("</>", "</span>");
-
-        const now = new Date();
-        messageText = now.getHours().toString().padStart(2, "0") + ":" + now.getMinutes().toString().padStart(2, "0") + " " + messageText;
-
-        const message = document.createElement("div");
-        message.innerHTML = messageText;
-        lobbyChatMessages.appendChild(message);
-
-        while (lobbyChatMessages.childNodes.length > 100) {
-            lobbyChatMessages.removeChild(lobbyChatMessages.childNodes[0]);
+        if (messageText.startsWith("history;;")) {
+            const historyData = messageText.split(";;");
+            if (historyData.length >= 3) {
+                appendChatMessage(historyData.slice(2).join(";;"), new Date(parseInt(historyData[1])));
+            }
+            return;
         }
 
-        lobbyChatMessages.scrollTop = lobbyChatMessages.scrollHeight - lobbyChatMessages.clientHeight;
+        appendChatMessage(messageText, new Date());
     };
 };
 
+let lobbyChatMessageLimit = 100;
+let oldestChatMessageTime = null;
+
+function createChatMessage(messageText, date) {
+    messageText = messageText.replaceAll("<red>", '<span class="lobby-chat-message-red">');
+    messageText = messageText.replaceAll("<green>", '<span class="lobby-chat-message-green">');
+    messageText = messageText.replaceAll("<blue>", '<span class="lobby-chat-message-blue">');
+    messageText = messageText.replaceAll("</>", "</span>");
+
+    messageText = date.getHours().toString().padStart(2, "0") + ":" + date.getMinutes().toString().padStart(2, "0") + " " + messageText;
+
+    if (oldestChatMessageTime === null || date.getTime() < oldestChatMessageTime) {
+        oldestChatMessageTime = date.getTime();
+    }
+
+    const message = document.createElement("div");
+    message.innerHTML = messageText;
+    return message;
+}
+
+function appendChatMessage(messageText, date) {
+    const lobbyChatMessages = document.getElementById("lobby-chat-messages");
+    if (!lobbyChatMessages) return;
+
+    lobbyChatMessages.appendChild(createChatMessage(messageText, date));
+
+    while (lobbyChatMessages.childNodes.length > lobbyChatMessageLimit) {
+        lobbyChatMessages.removeChild(lobbyChatMessages.childNodes[0]);
+    }
+
+    lobbyChatMessages.scrollTop = lobbyChatMessages.scrollHeight - lobbyChatMessages.clientHeight;
+}
+
+function loadOlderChatMessages() {
+    const lobbyChatMessages = document.getElementById("lobby-chat-messages");
+    if (!lobbyChatMessages) return;
+
+    let url = "/api" + document.location.pathname + "/chat/history";
+    if (oldestChatMessageTime !== null) url += "?before=" + oldestChatMessageTime;
+
+    fetch(url)
+        .then((response) => response.text())
+        .then((text) => {
+            const lines = text.split("\n").filter((line) => line.startsWith("history;;"));
+            // insert newest first so each one lands above the previous
+            for (const line of lines.reverse()) {
+                const historyData = line.split(";;");
+                const message = createChatMessage(historyData.slice(2).join(";;"), new Date(parseInt(historyData[1])));
+                lobbyChatMessages.insertBefore(message, lobbyChatMessages.firstChild);
+            }
+            lobbyChatMessageLimit += lines.length;
+            lobbyChatMessages.scrollTop = 0;
+        });
+}
+
 let roundTimerInterval = null;
 
 resetRoundTimerInterval();
@@ -207,3 +253,12 @@ document.addEventListener("htmx:afterSwap", function () {
     const lobbyGameStats = document.getElementById("lobby-game-stats");
     if (lobbyGameStats) lobbyGameStats.scrollTop = lobbyGameStatsScrollTop;
 });
+
+function openReportCardDialog(cardId) {
+    const reportCardDialog = document.getElementById("report-card-dialog");
+    if (!reportCardDialog) return;
+
+    document.getElementById("reportCardId").value = cardId;
+    reportCardDialog.querySelector(".htmx-result").innerHTML = "";
+    reportCardDialog.showModal();
+}
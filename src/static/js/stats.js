@@ -0,0 +1,53 @@
+function sortLeaderboard(column) {
+    const sortColumnElement = document.getElementById("leaderboardSortColumn");
+    const sortDescendingElement = document.getElementById("leaderboardSortDescending");
+    if (!sortColumnElement || !sortDescendingElement) return;
+
+    // sorting the same column again flips the direction
+    if (sortColumnElement.value == column) {
+        sortDescendingElement.value = sortDescendingElement.value === "true" ? "false" : "true";
+    } else {
+        sortColumnElement.value = column;
+        sortDescendingElement.value = "true";
+    }
+
+    goToLeaderboardPage(1);
+}
+
+function goToLeaderboardPage(pageNumberInput) {
+    const pageNumber = Number(pageNumberInput);
+    if (isNaN(pageNumber)) return;
+
+    const pageNumberElement = document.getElementById("leaderboardPage");
+    if (pageNumberElement) {
+        pageNumberElement.value = Math.max(pageNumber, 1);
+        htmx.trigger("#leaderboard-form", "change");
+    }
+}
+
+function resetLeaderboardPage() {
+    const pageNumberElement = document.getElementById("leaderboardPage");
+    if (pageNumberElement) {
+        pageNumberElement.value = 1;
+    }
+}
+
+function resetLeaderboardSort() {
+    // other topics have other columns
+    const sortColumnElement = document.getElementById("leaderboardSortColumn");
+    if (sortColumnElement) {
+        sortColumnElement.value = 0;
+    }
+    resetLeaderboardPage();
+}
+
+function exportLeaderboard(format) {
+    const formElement = document.getElementById("leaderboard-form");
+    if (!formElement) return;
+
+    // the export has every row, so the page is left out
+    const params = new URLSearchParams(new FormData(formElement));
+    params.delete("page");
+    params.set("format", format);
+    window.location.href = "/api/stats/leaderboard/export?" + params.toString();
+}
@@ -0,0 +1,2 @@
+ALTER TABLE AUDIT_CARD
+ADD COLUMN IF NOT EXISTS CONTENT_RATING ENUM('FAMILY', 'TEEN', 'MATURE') NULL AFTER IMAGE;
\ No newline at end of file
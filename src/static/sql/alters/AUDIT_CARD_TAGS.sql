@@ -0,0 +1,2 @@
+ALTER TABLE AUDIT_CARD
+ADD COLUMN IF NOT EXISTS TAGS VARCHAR(2040) NULL AFTER CONTENT_RATING;
\ No newline at end of file
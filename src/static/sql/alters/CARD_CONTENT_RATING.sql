@@ -0,0 +1,2 @@
+ALTER TABLE CARD
+ADD COLUMN IF NOT EXISTS CONTENT_RATING ENUM('FAMILY', 'TEEN', 'MATURE') NULL AFTER SOURCE_CARD_ID;
\ No newline at end of file
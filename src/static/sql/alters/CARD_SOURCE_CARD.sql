@@ -0,0 +1,2 @@
+ALTER TABLE CARD
+ADD COLUMN IF NOT EXISTS SOURCE_CARD_ID UUID NULL AFTER IMAGE;
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE DECK
+ADD COLUMN IF NOT EXISTS CONTENT_RATING ENUM('FAMILY', 'TEEN', 'MATURE') NULL AFTER FORKED_ON_DATE;
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE DECK
+ADD COLUMN IF NOT EXISTS PASSWORD_ROLE ENUM('NONE', 'VIEWER', 'EDITOR') NOT NULL DEFAULT 'EDITOR' AFTER IS_LOBBY_WILD_DECK;
\ No newline at end of file
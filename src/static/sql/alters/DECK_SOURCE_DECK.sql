@@ -0,0 +1,3 @@
+ALTER TABLE DECK
+ADD COLUMN IF NOT EXISTS SOURCE_DECK_ID UUID NULL AFTER PASSWORD_ROLE,
+ADD COLUMN IF NOT EXISTS FORKED_ON_DATE DATETIME(6) NULL AFTER SOURCE_DECK_ID;
\ No newline at end of file
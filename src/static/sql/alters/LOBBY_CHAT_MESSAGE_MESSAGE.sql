@@ -0,0 +1,3 @@
+-- SERVER MESSAGES ARE STORED ESCAPED, WHICH CAN BE LONGER THAN THE TEXT TYPED
+ALTER TABLE LOBBY_CHAT_MESSAGE
+MODIFY COLUMN MESSAGE TEXT NOT NULL;
\ No newline at end of file
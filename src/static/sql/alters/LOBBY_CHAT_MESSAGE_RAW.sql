@@ -0,0 +1,27 @@
+-- USER MESSAGES WERE STORED WITH THE NAME AND ESCAPED, NOW ONLY THE TEXT TYPED IS
+UPDATE LOBBY_CHAT_MESSAGE
+SET MESSAGE = REPLACE(
+        REPLACE(
+            REPLACE(
+                REPLACE(
+                    REPLACE(SUBSTRING(MESSAGE, LOCATE('</>: ', MESSAGE) + 5), '&lt;', '<'),
+                    '&gt;',
+                    '>'
+                ),
+                '&#34;',
+                '"'
+            ),
+            '&#39;',
+            "'"
+        ),
+        '&amp;',
+        '&'
+    )
+WHERE USER_ID IS NOT NULL
+    AND MESSAGE LIKE '<green>%</>: %'
+    AND NOT EXISTS (
+        SELECT
+            ID
+        FROM SETTING
+        WHERE NAME = 'LOBBY_CHAT_MESSAGE_RAW_MIGRATED'
+    );
\ No newline at end of file
@@ -0,0 +1,2 @@
+INSERT IGNORE INTO SETTING(NAME, VALUE)
+VALUES ('LOBBY_CHAT_MESSAGE_RAW_MIGRATED', 'TRUE');
\ No newline at end of file
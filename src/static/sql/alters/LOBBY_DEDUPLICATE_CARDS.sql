@@ -0,0 +1,2 @@
+ALTER TABLE LOBBY
+ADD COLUMN IF NOT EXISTS DEDUPLICATE_CARDS BOOLEAN NOT NULL DEFAULT FALSE AFTER MAX_CONTENT_RATING;
\ No newline at end of file
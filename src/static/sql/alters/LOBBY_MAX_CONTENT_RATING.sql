@@ -0,0 +1,2 @@
+ALTER TABLE LOBBY
+ADD COLUMN IF NOT EXISTS MAX_CONTENT_RATING ENUM('FAMILY', 'TEEN', 'MATURE') NULL AFTER LOSE_STREAK_THRESHOLD;
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE LOG_FLIP_TABLE
+ADD INDEX IF NOT EXISTS CREATED_ON_DATE_INDEX (CREATED_ON_DATE);
\ No newline at end of file
@@ -0,0 +1,7 @@
+-- LOBBIES CREATED BEFORE THE LOBBY LOG HAVE NO NAME IN IT
+INSERT IGNORE INTO LOG_LOBBY(ID, NAME, CREATED_ON_DATE)
+SELECT
+    ID,
+    NAME,
+    CREATED_ON_DATE
+FROM LOBBY;
\ No newline at end of file
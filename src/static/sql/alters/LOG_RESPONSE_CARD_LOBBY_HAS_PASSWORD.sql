@@ -0,0 +1,2 @@
+ALTER TABLE LOG_RESPONSE_CARD
+ADD COLUMN IF NOT EXISTS LOBBY_HAS_PASSWORD BOOLEAN NULL AFTER RESPONSE_TEXT;
\ No newline at end of file
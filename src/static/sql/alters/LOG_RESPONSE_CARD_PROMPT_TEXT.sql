@@ -0,0 +1,2 @@
+ALTER TABLE LOG_RESPONSE_CARD
+ADD COLUMN IF NOT EXISTS PROMPT_TEXT VARCHAR(510) NULL AFTER SPECIAL_CATEGORY;
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE LOG_RESPONSE_CARD
+ADD INDEX IF NOT EXISTS RESPONSE_ID_INDEX (RESPONSE_ID);
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE LOG_RESPONSE_CARD
+ADD COLUMN IF NOT EXISTS RESPONSE_TEXT VARCHAR(510) NULL AFTER PROMPT_TEXT;
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE LOG_RESPONSE_CARD
+ADD INDEX IF NOT EXISTS ROUND_ID_INDEX (ROUND_ID);
\ No newline at end of file
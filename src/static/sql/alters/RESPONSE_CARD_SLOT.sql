@@ -0,0 +1,2 @@
+ALTER TABLE RESPONSE_CARD
+ADD COLUMN IF NOT EXISTS SLOT INT NOT NULL DEFAULT 1 AFTER CARD_ID;
\ No newline at end of file
@@ -0,0 +1,24 @@
+-- RATINGS GIVEN BEFORE THE TRIGGER KEPT THE ROLLUP UP TO DATE
+INSERT INTO STAT_RATING_DAY(DAY, LOBBY_ID, USER_ID, RATING_CHANGE, ROUND_COUNT)
+SELECT
+    DATE(URH.CREATED_ON_DATE),
+    RL.LOBBY_ID,
+    URH.USER_ID,
+    SUM(URH.RATING_CHANGE),
+    COUNT(*)
+FROM USER_RATING_HISTORY AS URH
+    INNER JOIN (
+        SELECT
+            ROUND_ID,
+            MIN(LOBBY_ID) AS LOBBY_ID
+        FROM LOG_RESPONSE_CARD
+        GROUP BY ROUND_ID
+    ) AS RL ON RL.ROUND_ID = URH.ROUND_ID
+WHERE NOT EXISTS (
+        SELECT
+            DAY
+        FROM STAT_RATING_DAY
+    )
+GROUP BY DATE(URH.CREATED_ON_DATE),
+    RL.LOBBY_ID,
+    URH.USER_ID;
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE USER_ACCESS_DECK
+ADD COLUMN IF NOT EXISTS ROLE ENUM('VIEWER', 'EDITOR', 'OWNER') NOT NULL DEFAULT 'VIEWER' AFTER DECK_ID;
\ No newline at end of file
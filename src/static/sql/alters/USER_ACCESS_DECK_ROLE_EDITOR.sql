@@ -0,0 +1,10 @@
+-- EVERY USER GIVEN ACCESS BEFORE ROLES COULD EDIT THE DECK, SO THEY KEEP IT
+UPDATE USER_ACCESS_DECK
+SET ROLE = 'EDITOR'
+WHERE ROLE = 'VIEWER'
+    AND NOT EXISTS (
+        SELECT
+            ID
+        FROM SETTING
+        WHERE NAME = 'USER_ACCESS_DECK_ROLE_MIGRATED'
+    );
\ No newline at end of file
@@ -0,0 +1,2 @@
+INSERT IGNORE INTO SETTING(NAME, VALUE)
+VALUES ('USER_ACCESS_DECK_ROLE_MIGRATED', 'TRUE');
\ No newline at end of file
@@ -0,0 +1,28 @@
+-- THE CREATOR OF A DECK IS THE FIRST USER GIVEN ACCESS TO IT. THIS ONLY RUNS
+-- ONCE, SO A DECK THAT LOSES ITS OWNER LATER DOES NOT PROMOTE ANYONE
+UPDATE USER_ACCESS_DECK
+SET ROLE = 'OWNER'
+WHERE ID IN (
+        SELECT
+            ID
+        FROM (
+                SELECT
+                    ID,
+                    DECK_ID,
+                    ROW_NUMBER() OVER (PARTITION BY DECK_ID ORDER BY CREATED_ON_DATE, ID) AS ACCESS_ORDER
+                FROM USER_ACCESS_DECK
+            ) AS UAD
+        WHERE UAD.ACCESS_ORDER = 1
+            AND UAD.DECK_ID NOT IN (
+                SELECT
+                    DECK_ID
+                FROM USER_ACCESS_DECK
+                WHERE ROLE = 'OWNER'
+            )
+    )
+    AND NOT EXISTS (
+        SELECT
+            ID
+        FROM SETTING
+        WHERE NAME = 'USER_ACCESS_DECK_ROLE_MIGRATED'
+    );
\ No newline at end of file
@@ -2,15 +2,29 @@ CREATE
 OR REPLACE EVENT EVT_CLEAN_AUDIT_TABLES ON SCHEDULE EVERY 1 DAY
 DO
     BEGIN
+        DECLARE VAR_RETENTION_DAYS INT DEFAULT COALESCE(
+                (
+                    SELECT
+                        VALUE
+                    FROM SETTING
+                    WHERE NAME = 'AUDIT_RETENTION_DAYS'
+                ),
+                14
+            );
+
         DELETE
         FROM AUDIT_CARD
-        WHERE CREATED_ON_DATE < DATE_SUB(CURRENT_TIMESTAMP(), INTERVAL 2 WEEK);
+        WHERE CREATED_ON_DATE < DATE_SUB(CURRENT_TIMESTAMP(), INTERVAL VAR_RETENTION_DAYS DAY);
 
         DELETE
         FROM AUDIT_DECK
-        WHERE CREATED_ON_DATE < DATE_SUB(CURRENT_TIMESTAMP(), INTERVAL 2 WEEK);
+        WHERE CREATED_ON_DATE < DATE_SUB(CURRENT_TIMESTAMP(), INTERVAL VAR_RETENTION_DAYS DAY);
 
         DELETE
         FROM AUDIT_USER
-        WHERE CREATED_ON_DATE < DATE_SUB(CURRENT_TIMESTAMP(), INTERVAL 2 WEEK);
+        WHERE CREATED_ON_DATE < DATE_SUB(CURRENT_TIMESTAMP(), INTERVAL VAR_RETENTION_DAYS DAY);
+
+        DELETE
+        FROM CARD_BATCH
+        WHERE CREATED_ON_DATE < DATE_SUB(CURRENT_TIMESTAMP(), INTERVAL VAR_RETENTION_DAYS DAY);
     END;
\ No newline at end of file
@@ -0,0 +1,8 @@
+CREATE
+OR REPLACE EVENT EVT_CLEAN_LOBBY_CHAT_MESSAGES ON SCHEDULE EVERY 1 DAY
+DO
+    BEGIN
+        DELETE
+        FROM LOBBY_CHAT_MESSAGE
+        WHERE CREATED_ON_DATE < DATE_SUB(CURRENT_TIMESTAMP(), INTERVAL 1 WEEK);
+    END;
\ No newline at end of file
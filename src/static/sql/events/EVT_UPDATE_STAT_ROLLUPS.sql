@@ -0,0 +1,6 @@
+CREATE
+OR REPLACE EVENT EVT_UPDATE_STAT_ROLLUPS ON SCHEDULE EVERY 1 HOUR
+DO
+    BEGIN
+        CALL SP_UPDATE_STAT_ROLLUPS();
+    END;
\ No newline at end of file
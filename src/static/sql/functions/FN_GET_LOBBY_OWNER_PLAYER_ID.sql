@@ -0,0 +1,14 @@
+CREATE
+OR REPLACE FUNCTION FN_GET_LOBBY_OWNER_PLAYER_ID(IN VAR_LOBBY_ID UUID)
+RETURNS UUID
+BEGIN
+    RETURN (
+        SELECT
+            ID
+        FROM PLAYER
+        WHERE LOBBY_ID = VAR_LOBBY_ID
+            AND IS_ACTIVE = 1
+        ORDER BY JOIN_ORDER ASC
+        LIMIT 1
+    );
+END;
\ No newline at end of file
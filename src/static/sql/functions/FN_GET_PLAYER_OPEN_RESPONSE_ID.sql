@@ -0,0 +1,25 @@
+CREATE
+OR REPLACE FUNCTION FN_GET_PLAYER_OPEN_RESPONSE_ID(IN VAR_PLAYER_ID UUID)
+RETURNS UUID
+BEGIN
+    DECLARE VAR_LOBBY_ID UUID DEFAULT FN_GET_PLAYER_LOBBY_ID(VAR_PLAYER_ID);
+    DECLARE VAR_JUDGE_BLANK_COUNT INT DEFAULT FN_GET_LOBBY_JUDGE_BLANK_COUNT(VAR_LOBBY_ID);
+
+    RETURN (
+        SELECT
+            ID
+        FROM (
+                SELECT
+                    R.ID,
+                    R.CREATED_ON_DATE,
+                    COUNT(RC.ID) AS CARD_COUNT
+                FROM RESPONSE AS R
+                    LEFT JOIN RESPONSE_CARD AS RC ON RC.RESPONSE_ID = R.ID
+                WHERE R.PLAYER_ID = VAR_PLAYER_ID
+                GROUP BY R.ID
+            ) AS T
+        WHERE CARD_COUNT < VAR_JUDGE_BLANK_COUNT
+        ORDER BY CREATED_ON_DATE
+        LIMIT 1
+    );
+END;
\ No newline at end of file
@@ -1,5 +1,6 @@
 CREATE
 OR REPLACE FUNCTION FN_GET_SPECIAL_COST(
+    IN VAR_LOBBY_ID UUID,
     IN VAR_CATEGORY ENUM(
         'WINNING-STREAK',
         'LOSING-STREAK',
@@ -17,6 +18,20 @@ OR REPLACE FUNCTION FN_GET_SPECIAL_COST(
 )
 RETURNS INT
 BEGIN
+    DECLARE VAR_LOBBY_COST INT DEFAULT (
+            SELECT
+                COST
+            FROM LOBBY_RULE
+            WHERE LOBBY_ID = VAR_LOBBY_ID
+                AND CATEGORY = VAR_CATEGORY
+            LIMIT 1
+        );
+
+    IF VAR_LOBBY_COST IS NOT NULL THEN
+        RETURN VAR_LOBBY_COST;
+    END
+    IF;
+
     RETURN
     CASE
         WHEN VAR_CATEGORY = 'WINNING-STREAK' THEN 1
@@ -0,0 +1,32 @@
+CREATE
+OR REPLACE FUNCTION FN_GET_SPECIAL_IS_ENABLED(
+    IN VAR_LOBBY_ID UUID,
+    IN VAR_CATEGORY ENUM(
+        'ALERT',
+        'GAMBLE',
+        'BET',
+        'SKIP-JUDGE',
+        'EXTRA-RESPONSE',
+        'BLOCK-RESPONSE',
+        'SURPRISE',
+        'STEAL',
+        'FIND',
+        'WILD',
+        'PERK',
+        'FLIP-TABLE'
+    )
+)
+RETURNS BOOLEAN
+BEGIN
+    RETURN COALESCE(
+            (
+                SELECT
+                    IS_ENABLED
+                FROM LOBBY_RULE
+                WHERE LOBBY_ID = VAR_LOBBY_ID
+                    AND CATEGORY = VAR_CATEGORY
+                LIMIT 1
+            ),
+            1
+        );
+END;
\ No newline at end of file
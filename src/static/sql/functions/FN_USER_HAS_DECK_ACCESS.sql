@@ -1,10 +1,19 @@
 CREATE
 OR REPLACE FUNCTION FN_USER_HAS_DECK_ACCESS(
     IN VAR_USER_ID UUID,
-    IN VAR_DECK_ID UUID
+    IN VAR_DECK_ID UUID,
+    IN VAR_CAPABILITY VARCHAR(50)
 )
 RETURNS BOOLEAN
 BEGIN
+    DECLARE VAR_ROLE VARCHAR(50) DEFAULT (
+            SELECT
+                ROLE
+            FROM USER_ACCESS_DECK
+            WHERE USER_ID = VAR_USER_ID
+                AND DECK_ID = VAR_DECK_ID
+        );
+
     -- USER IS ADMIN
     IF EXISTS(SELECT ID FROM USER WHERE ID = VAR_USER_ID AND IS_ADMIN = 1) THEN
         RETURN 1;
@@ -12,14 +21,14 @@ BEGIN
     IF;
 
     -- USER HAS ACCESS
-    IF EXISTS(
-        SELECT
-            ID
-        FROM USER_ACCESS_DECK
-        WHERE USER_ID = VAR_USER_ID
-            AND DECK_ID = VAR_DECK_ID
-    ) THEN
-        RETURN 1;
+    IF VAR_ROLE IS NOT NULL THEN
+        RETURN
+        CASE
+            WHEN VAR_CAPABILITY = 'READ' THEN 1
+            WHEN VAR_CAPABILITY = 'EDIT' THEN VAR_ROLE IN ('EDITOR', 'OWNER')
+            WHEN VAR_CAPABILITY = 'MANAGE' THEN VAR_ROLE = 'OWNER'
+            ELSE 0
+        END;
     END
     IF;
 
@@ -0,0 +1,30 @@
+CREATE
+OR REPLACE PROCEDURE SP_ADD_LOBBY_CHAT_MESSAGE(
+    IN VAR_LOBBY_ID UUID,
+    IN VAR_USER_ID UUID,
+    IN VAR_MESSAGE TEXT
+)
+BEGIN
+    DECLARE VAR_CUTOFF_DATE DATETIME(6) DEFAULT NULL;
+
+    INSERT INTO LOBBY_CHAT_MESSAGE(LOBBY_ID, USER_ID, MESSAGE)
+    VALUES (VAR_LOBBY_ID, VAR_USER_ID, VAR_MESSAGE);
+
+    -- ONLY KEEP THE MOST RECENT 500 MESSAGES PER LOBBY
+    SET VAR_CUTOFF_DATE = (
+            SELECT
+                CREATED_ON_DATE
+            FROM LOBBY_CHAT_MESSAGE
+            WHERE LOBBY_ID = VAR_LOBBY_ID
+            ORDER BY CREATED_ON_DATE DESC
+            LIMIT 1 OFFSET 499
+        );
+
+    IF VAR_CUTOFF_DATE IS NOT NULL THEN
+        DELETE
+        FROM LOBBY_CHAT_MESSAGE
+        WHERE LOBBY_ID = VAR_LOBBY_ID
+            AND CREATED_ON_DATE < VAR_CUTOFF_DATE;
+    END
+    IF;
+END;
\ No newline at end of file
@@ -0,0 +1,23 @@
+CREATE
+OR REPLACE PROCEDURE SP_KICK_PLAYER(
+    IN VAR_SUBJECT_PLAYER_ID UUID
+)
+BEGIN
+    DECLARE VAR_LOBBY_ID UUID DEFAULT FN_GET_PLAYER_LOBBY_ID(VAR_SUBJECT_PLAYER_ID);
+
+    DECLARE VAR_SUBJECT_USER_ID UUID DEFAULT (
+            SELECT
+                USER_ID
+            FROM PLAYER
+            WHERE ID = VAR_SUBJECT_PLAYER_ID
+        );
+
+    DELETE
+    FROM KICK
+    WHERE SUBJECT_PLAYER_ID = VAR_SUBJECT_PLAYER_ID;
+
+    INSERT INTO LOG_KICK(LOBBY_ID, USER_ID)
+    VALUES (VAR_LOBBY_ID, VAR_SUBJECT_USER_ID);
+
+    CALL SP_SET_PLAYER_INACTIVE(VAR_LOBBY_ID, VAR_SUBJECT_USER_ID);
+END;
\ No newline at end of file
@@ -0,0 +1,49 @@
+CREATE
+OR REPLACE PROCEDURE SP_MOVE_RESPONSE_CARD(
+    IN VAR_PLAYER_ID UUID,
+    IN VAR_RESPONSE_CARD_ID UUID,
+    IN VAR_SLOT INT
+)
+BEGIN
+    DECLARE VAR_RESPONSE_ID UUID;
+    DECLARE VAR_CURRENT_SLOT INT;
+
+    SELECT
+        RC.RESPONSE_ID,
+        RC.SLOT
+    INTO
+        VAR_RESPONSE_ID,
+        VAR_CURRENT_SLOT
+    FROM RESPONSE_CARD AS RC
+        INNER JOIN RESPONSE AS R ON R.ID = RC.RESPONSE_ID
+    WHERE RC.ID = VAR_RESPONSE_CARD_ID
+        AND R.PLAYER_ID = VAR_PLAYER_ID
+        AND R.IS_REVEALED = 0;
+
+    IF VAR_RESPONSE_ID IS NULL THEN
+        SIGNAL SQLSTATE '45000'
+            SET MESSAGE_TEXT = 'Response card cannot be moved.';
+    END
+    IF;
+
+    IF VAR_SLOT < 1
+        OR VAR_SLOT > (
+            SELECT
+                COUNT(*)
+            FROM RESPONSE_CARD
+            WHERE RESPONSE_ID = VAR_RESPONSE_ID
+        ) THEN
+        SIGNAL SQLSTATE '45000'
+            SET MESSAGE_TEXT = 'Slot is not filled.';
+    END
+    IF;
+
+    UPDATE RESPONSE_CARD
+    SET SLOT = VAR_CURRENT_SLOT
+    WHERE RESPONSE_ID = VAR_RESPONSE_ID
+        AND SLOT = VAR_SLOT;
+
+    UPDATE RESPONSE_CARD
+    SET SLOT = VAR_SLOT
+    WHERE ID = VAR_RESPONSE_CARD_ID;
+END;
\ No newline at end of file
@@ -0,0 +1,68 @@
+CREATE
+OR REPLACE PROCEDURE SP_REPORT_CARD(
+    IN VAR_CARD_ID UUID,
+    IN VAR_PLAYER_ID UUID,
+    IN VAR_REASON VARCHAR(50)
+)
+BEGIN
+    DECLARE VAR_LOBBY_ID UUID DEFAULT FN_GET_PLAYER_LOBBY_ID(VAR_PLAYER_ID);
+
+    DECLARE VAR_REPORT_THRESHOLD INT DEFAULT COALESCE(
+            (
+                SELECT
+                    VALUE
+                FROM SETTING
+                WHERE NAME = 'CARD_REPORT_THRESHOLD'
+            ),
+            3
+        );
+
+    DECLARE VAR_USER_ID UUID DEFAULT (
+            SELECT
+                USER_ID
+            FROM PLAYER
+            WHERE ID = VAR_PLAYER_ID
+        );
+
+    INSERT INTO CARD_REPORT(CARD_ID, LOBBY_ID, USER_ID, REASON)
+    VALUES (VAR_CARD_ID, VAR_LOBBY_ID, VAR_USER_ID, VAR_REASON)
+    ON DUPLICATE KEY UPDATE
+        LOBBY_ID = VAR_LOBBY_ID,
+        REASON = VAR_REASON;
+
+    IF(
+        SELECT
+            COUNT(DISTINCT USER_ID)
+        FROM CARD_REPORT
+        WHERE CARD_ID = VAR_CARD_ID
+    ) >= VAR_REPORT_THRESHOLD THEN
+        INSERT INTO REVIEW_CARD(
+            CARD_ID,
+            DECK_ID,
+            CATEGORY,
+            TEXT,
+            YOUTUBE,
+            IMAGE
+        )
+        SELECT
+            ID AS CARD_ID,
+            DECK_ID,
+            CATEGORY,
+            TEXT,
+            YOUTUBE,
+            IMAGE
+        FROM CARD
+        WHERE ID = VAR_CARD_ID;
+
+        DELETE
+        FROM CARD
+        WHERE ID = VAR_CARD_ID;
+
+        SELECT
+            ROW_COUNT() > 0;
+        ELSE
+        SELECT
+            0;
+    END
+    IF;
+END;
\ No newline at end of file
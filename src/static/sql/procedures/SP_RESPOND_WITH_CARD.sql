@@ -6,32 +6,27 @@ OR REPLACE PROCEDURE SP_RESPOND_WITH_CARD(
 )
 BEGIN
     DECLARE VAR_RESPONSE_CARD_ID UUID DEFAULT UUID();
-    DECLARE VAR_LOBBY_ID UUID DEFAULT FN_GET_PLAYER_LOBBY_ID(VAR_PLAYER_ID);
-    DECLARE VAR_JUDGE_BLANK_COUNT INT DEFAULT FN_GET_LOBBY_JUDGE_BLANK_COUNT(VAR_LOBBY_ID);
+    DECLARE VAR_RESPONSE_ID UUID DEFAULT FN_GET_PLAYER_OPEN_RESPONSE_ID(VAR_PLAYER_ID);
 
-    DECLARE VAR_RESPONSE_ID UUID DEFAULT (
+    DECLARE VAR_SLOT INT DEFAULT (
             SELECT
-                ID
-            FROM (
-                    SELECT
-                        R.ID,
-                        R.CREATED_ON_DATE,
-                        COUNT(RC.ID) AS CARD_COUNT
-                    FROM RESPONSE AS R
-                        LEFT JOIN RESPONSE_CARD AS RC ON RC.RESPONSE_ID = R.ID
-                    WHERE R.PLAYER_ID = VAR_PLAYER_ID
-                    GROUP BY R.ID
-                ) AS T
-            WHERE CARD_COUNT < VAR_JUDGE_BLANK_COUNT
-            ORDER BY CREATED_ON_DATE
-            LIMIT 1
+                COUNT(*) + 1
+            FROM RESPONSE_CARD
+            WHERE RESPONSE_ID = VAR_RESPONSE_ID
         );
 
-    INSERT INTO RESPONSE_CARD(ID, RESPONSE_ID, CARD_ID, SPECIAL_CATEGORY)
+    IF VAR_RESPONSE_ID IS NULL THEN
+        SIGNAL SQLSTATE '45000'
+            SET MESSAGE_TEXT = 'All response slots are already filled.';
+    END
+    IF;
+
+    INSERT INTO RESPONSE_CARD(ID, RESPONSE_ID, CARD_ID, SLOT, SPECIAL_CATEGORY)
     VALUES (
         VAR_RESPONSE_CARD_ID,
         VAR_RESPONSE_ID,
         VAR_CARD_ID,
+        VAR_SLOT,
         VAR_SPECIAL_CATEGORY
     );
 
@@ -44,7 +39,10 @@ BEGIN
         JUDGE_CARD_ID,
         PLAYER_USER_ID,
         PLAYER_CARD_ID,
-        SPECIAL_CATEGORY
+        SPECIAL_CATEGORY,
+        PROMPT_TEXT,
+        RESPONSE_TEXT,
+        LOBBY_HAS_PASSWORD
     )
     SELECT
         L.ID AS LOBBY_ID,
@@ -55,13 +53,19 @@ BEGIN
         J.CARD_ID AS JUDGE_CARD_ID,
         P.USER_ID AS PLAYER_USER_ID,
         RC.CARD_ID AS PLAYER_CARD_ID,
-        RC.SPECIAL_CATEGORY AS SPECIAL_CATEGORY
+        RC.SPECIAL_CATEGORY AS SPECIAL_CATEGORY,
+        JC.TEXT AS PROMPT_TEXT,
+        PC.TEXT AS RESPONSE_TEXT,
+        L.PASSWORD_HASH IS NOT NULL AS LOBBY_HAS_PASSWORD
     FROM RESPONSE_CARD AS RC
         INNER JOIN RESPONSE AS R ON R.ID = RC.RESPONSE_ID
         INNER JOIN PLAYER AS P ON P.ID = R.PLAYER_ID
         INNER JOIN LOBBY AS L ON L.ID = P.LOBBY_ID
         INNER JOIN JUDGE AS J ON J.LOBBY_ID = L.ID
         INNER JOIN PLAYER AS JP ON JP.ID = J.PLAYER_ID
+        -- THE TEXT IS KEPT AS PLAYED, SINCE CARDS ARE EDITED AND DELETED
+        INNER JOIN CARD AS JC ON JC.ID = J.CARD_ID
+        INNER JOIN CARD AS PC ON PC.ID = RC.CARD_ID
     WHERE RC.ID = VAR_RESPONSE_CARD_ID;
 
     DELETE
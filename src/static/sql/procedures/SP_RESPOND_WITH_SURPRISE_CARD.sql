@@ -16,7 +16,7 @@ BEGIN
     CALL SP_SPEND_CREDITS(
             VAR_PLAYER_ID,
             (
-                FN_GET_SPECIAL_COST('SURPRISE') +
+                FN_GET_SPECIAL_COST(VAR_LOBBY_ID, 'SURPRISE') +
                     FN_GET_PLAYER_HANDICAP(VAR_PLAYER_ID)
             ),
             'SURPRISE'
@@ -20,7 +20,7 @@ BEGIN
     CALL SP_SPEND_CREDITS(
             VAR_PLAYER_ID,
             (
-                FN_GET_SPECIAL_COST('WILD') +
+                FN_GET_SPECIAL_COST(VAR_LOBBY_ID, 'WILD') +
                     FN_GET_PLAYER_HANDICAP(VAR_PLAYER_ID)
             ),
             'WILD'
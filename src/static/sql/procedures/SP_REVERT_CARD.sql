@@ -0,0 +1,106 @@
+CREATE
+OR REPLACE PROCEDURE SP_REVERT_CARD(
+    IN VAR_AUDIT_CARD_ID UUID
+)
+BEGIN
+    DECLARE VAR_CARD_ID UUID;
+    DECLARE VAR_DECK_ID UUID;
+    DECLARE VAR_CATEGORY VARCHAR(50);
+    DECLARE VAR_TEXT VARCHAR(510);
+    DECLARE VAR_YOUTUBE CHAR(11);
+    DECLARE VAR_IMAGE BLOB;
+    DECLARE VAR_CONTENT_RATING VARCHAR(50);
+    DECLARE VAR_TAGS VARCHAR(2040);
+    DECLARE VAR_TAG VARCHAR(50);
+
+    -- COPY INTO VARIABLES SINCE THE AUDIT TRIGGERS WRITE TO AUDIT_CARD
+    SELECT
+        CARD_ID,
+        DECK_ID,
+        CATEGORY,
+        TEXT,
+        YOUTUBE,
+        IMAGE,
+        CONTENT_RATING,
+        TAGS INTO VAR_CARD_ID,
+        VAR_DECK_ID,
+        VAR_CATEGORY,
+        VAR_TEXT,
+        VAR_YOUTUBE,
+        VAR_IMAGE,
+        VAR_CONTENT_RATING,
+        VAR_TAGS
+    FROM AUDIT_CARD
+    WHERE ID = VAR_AUDIT_CARD_ID;
+
+    -- ANOTHER CARD IN THE DECK MAY HAVE THE TEXT BY NOW
+    IF EXISTS(
+        SELECT
+            ID
+        FROM CARD
+        WHERE DECK_ID = VAR_DECK_ID
+            AND TEXT = VAR_TEXT
+            AND ID <> VAR_CARD_ID
+    ) THEN
+        SELECT
+            FALSE;
+        ELSE
+        IF EXISTS(
+            SELECT
+                ID
+            FROM CARD
+            WHERE ID = VAR_CARD_ID
+        ) THEN
+            UPDATE CARD
+            SET
+                CATEGORY = VAR_CATEGORY,
+                TEXT = VAR_TEXT,
+                YOUTUBE = VAR_YOUTUBE,
+                IMAGE = VAR_IMAGE
+            WHERE ID = VAR_CARD_ID;
+            ELSE
+            INSERT INTO CARD(ID, DECK_ID, CATEGORY, TEXT, YOUTUBE, IMAGE)
+            VALUES (
+                VAR_CARD_ID,
+                VAR_DECK_ID,
+                VAR_CATEGORY,
+                VAR_TEXT,
+                VAR_YOUTUBE,
+                VAR_IMAGE
+            );
+        END
+        IF;
+
+        -- A CARD RESTORED FROM REVIEW IS NO LONGER WAITING ON IT
+        DELETE
+        FROM REVIEW_CARD
+        WHERE CARD_ID = VAR_CARD_ID;
+
+        -- ROWS AUDITED BEFORE RATINGS AND TAGS WERE KEPT HAVE NO TAGS, AND
+        -- LEAVE THE CARD'S CURRENT ONES ALONE
+        IF VAR_TAGS IS NOT NULL THEN
+            UPDATE CARD
+            SET CONTENT_RATING = VAR_CONTENT_RATING
+            WHERE ID = VAR_CARD_ID;
+
+            DELETE
+            FROM CARD_TAG
+            WHERE CARD_ID = VAR_CARD_ID;
+
+            WHILE VAR_TAGS <> ''
+            DO
+                SET VAR_TAG = SUBSTRING_INDEX(VAR_TAGS, ',', 1);
+                SET VAR_TAGS = SUBSTRING(VAR_TAGS, CHAR_LENGTH(VAR_TAG) + 2);
+
+                INSERT IGNORE INTO CARD_TAG(CARD_ID, TAG)
+                VALUES (VAR_CARD_ID, VAR_TAG);
+            END
+            WHILE;
+        END
+        IF;
+
+        SELECT
+            TRUE;
+    END
+    IF;
+END;
\ No newline at end of file
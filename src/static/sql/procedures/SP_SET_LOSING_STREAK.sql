@@ -61,7 +61,7 @@ BEGIN
         CALL SP_SPEND_CREDITS(
                 VAR_PLAYER_ID,
                 (
-                    FN_GET_SPECIAL_COST('LOSING-STREAK') +
+                    FN_GET_SPECIAL_COST(VAR_LOBBY_ID, 'LOSING-STREAK') +
                     FN_GET_PLAYER_HANDICAP_INVERSE(VAR_PLAYER_ID)
                 ) * -1,
                 'LOSING-STREAK'
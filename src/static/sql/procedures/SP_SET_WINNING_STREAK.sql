@@ -61,7 +61,7 @@ BEGIN
         CALL SP_SPEND_CREDITS(
                 VAR_PLAYER_ID,
                 (
-                    FN_GET_SPECIAL_COST('WINNING-STREAK') +
+                    FN_GET_SPECIAL_COST(VAR_LOBBY_ID, 'WINNING-STREAK') +
                     FN_GET_PLAYER_HANDICAP(VAR_PLAYER_ID)
                 ),
                 'WINNING-STREAK'
@@ -6,7 +6,7 @@ BEGIN
     CALL SP_SPEND_CREDITS(
             VAR_PLAYER_ID,
             (
-                FN_GET_SPECIAL_COST('SKIP-JUDGE') +
+                FN_GET_SPECIAL_COST(VAR_LOBBY_ID, 'SKIP-JUDGE') +
                     FN_GET_PLAYER_HANDICAP(VAR_PLAYER_ID)
             ),
             'SKIP-JUDGE'
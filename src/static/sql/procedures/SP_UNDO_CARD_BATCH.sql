@@ -0,0 +1,71 @@
+CREATE
+OR REPLACE PROCEDURE SP_UNDO_CARD_BATCH(
+    IN VAR_BATCH_ID UUID
+)
+BEGIN
+    -- A CONFLICTING CARD (SAME TEXT IN THE SAME DECK) UNDOES NOTHING
+    DECLARE EXIT HANDLER FOR SQLEXCEPTION
+    BEGIN
+        ROLLBACK;
+        RESIGNAL;
+    END;
+
+    IF EXISTS(
+        SELECT
+            ID
+        FROM CARD_BATCH
+        WHERE ID = VAR_BATCH_ID
+            AND UNDONE_ON_DATE IS NOT NULL
+    ) THEN
+        SIGNAL SQLSTATE '45000'
+            SET MESSAGE_TEXT = 'Batch has already been undone.';
+    END
+    IF;
+
+    START TRANSACTION;
+
+    UPDATE CARD AS C
+        INNER JOIN CARD_BATCH_CARD AS S ON S.CARD_ID = C.ID
+    SET
+        C.DECK_ID = S.DECK_ID,
+        C.CATEGORY = S.CATEGORY,
+        C.TEXT = S.TEXT,
+        C.YOUTUBE = S.YOUTUBE,
+        C.IMAGE = S.IMAGE,
+        C.CONTENT_RATING = S.CONTENT_RATING
+    WHERE S.BATCH_ID = VAR_BATCH_ID;
+
+    INSERT INTO CARD(ID, CREATED_ON_DATE, DECK_ID, CATEGORY, TEXT, YOUTUBE, IMAGE, SOURCE_CARD_ID, CONTENT_RATING)
+    SELECT
+        S.CARD_ID,
+        S.CARD_CREATED_ON_DATE,
+        S.DECK_ID,
+        S.CATEGORY,
+        S.TEXT,
+        S.YOUTUBE,
+        S.IMAGE,
+        S.SOURCE_CARD_ID,
+        S.CONTENT_RATING
+    FROM CARD_BATCH_CARD AS S
+        LEFT JOIN CARD AS C ON C.ID = S.CARD_ID
+    WHERE S.BATCH_ID = VAR_BATCH_ID
+        AND C.ID IS NULL;
+
+    DELETE CT
+    FROM CARD_TAG AS CT
+        INNER JOIN CARD_BATCH_CARD AS S ON S.CARD_ID = CT.CARD_ID
+    WHERE S.BATCH_ID = VAR_BATCH_ID;
+
+    INSERT INTO CARD_TAG(CARD_ID, TAG)
+    SELECT
+        CARD_ID,
+        TAG
+    FROM CARD_BATCH_TAG
+    WHERE BATCH_ID = VAR_BATCH_ID;
+
+    UPDATE CARD_BATCH
+    SET UNDONE_ON_DATE = CURRENT_TIMESTAMP(6)
+    WHERE ID = VAR_BATCH_ID;
+
+    COMMIT;
+END;
\ No newline at end of file
@@ -0,0 +1,186 @@
+CREATE
+OR REPLACE PROCEDURE SP_UPDATE_STAT_ROLLUPS()
+BEGIN
+    -- THE LAST DAY ROLLED UP WAS STILL GOING, SO IT IS ALWAYS BUILT AGAIN
+    DECLARE VAR_FROM_DAY DATE DEFAULT (SELECT DAY FROM V_STAT_ROLLUP_DAY);
+
+    -- LOGS WRITTEN JUST BEFORE MIDNIGHT CAN STILL BE COMMITTING AFTER IT
+    DECLARE VAR_TO_DAY DATE DEFAULT DATE(DATE_SUB(CURRENT_TIMESTAMP(), INTERVAL 1 HOUR));
+
+    DECLARE EXIT HANDLER FOR SQLEXCEPTION
+    BEGIN
+        ROLLBACK;
+        DO RELEASE_LOCK('SP_UPDATE_STAT_ROLLUPS');
+        RESIGNAL;
+    END;
+
+    -- THE EVENT AND A REBUILD CAN BOTH ASK FOR AN UPDATE AT ONCE, AND GET_LOCK
+    -- RETURNS NULL RATHER THAN 0 WHEN IT FAILS WITH AN ERROR
+    IF GET_LOCK('SP_UPDATE_STAT_ROLLUPS', 60) IS NOT TRUE THEN
+        SIGNAL SQLSTATE '45000'
+            SET MESSAGE_TEXT = 'Stat rollups are already being updated.';
+    END
+    IF;
+
+    START TRANSACTION;
+
+    DELETE
+    FROM STAT_USER_DAY
+    WHERE DAY >= VAR_FROM_DAY;
+
+    DELETE
+    FROM STAT_CARD_DAY
+    WHERE DAY >= VAR_FROM_DAY;
+
+    DELETE
+    FROM STAT_CREDITS_DAY
+    WHERE DAY >= VAR_FROM_DAY;
+
+    DELETE
+    FROM STAT_PICK_DAY
+    WHERE DAY >= VAR_FROM_DAY;
+
+    DELETE
+    FROM STAT_PICK_CARD_DAY
+    WHERE DAY >= VAR_FROM_DAY;
+
+    DELETE
+    FROM STAT_SPECIAL_DAY
+    WHERE DAY >= VAR_FROM_DAY;
+
+    -- THE LOG VIEWS READ FROM THE LAST DAY ROLLED UP, WHICH IS STILL VAR_FROM_DAY
+    INSERT INTO STAT_USER_DAY(
+        DAY,
+        LOBBY_ID,
+        USER_ID,
+        ROUND_PLAY_COUNT,
+        ROUND_WIN_COUNT,
+        RESPONSE_CARD_PLAY_COUNT,
+        RESPONSE_CARD_DISCARD_COUNT,
+        PROMPT_CARD_PLAY_COUNT,
+        PROMPT_CARD_SKIP_COUNT,
+        KICK_COUNT,
+        FLIP_TABLE_COUNT
+    )
+    SELECT
+        DAY,
+        LOBBY_ID,
+        USER_ID,
+        ROUND_PLAY_COUNT,
+        ROUND_WIN_COUNT,
+        RESPONSE_CARD_PLAY_COUNT,
+        RESPONSE_CARD_DISCARD_COUNT,
+        PROMPT_CARD_PLAY_COUNT,
+        PROMPT_CARD_SKIP_COUNT,
+        KICK_COUNT,
+        FLIP_TABLE_COUNT
+    FROM V_STAT_USER_DAY_LOG;
+
+    INSERT INTO STAT_CARD_DAY(
+        DAY,
+        LOBBY_ID,
+        USER_ID,
+        CARD_ID,
+        RESPONSE_PLAY_COUNT,
+        ROUND_PLAY_COUNT,
+        ROUND_WIN_COUNT,
+        DISCARD_COUNT,
+        PROMPT_PLAY_COUNT,
+        SKIP_COUNT
+    )
+    SELECT
+        DAY,
+        LOBBY_ID,
+        USER_ID,
+        CARD_ID,
+        RESPONSE_PLAY_COUNT,
+        ROUND_PLAY_COUNT,
+        ROUND_WIN_COUNT,
+        DISCARD_COUNT,
+        PROMPT_PLAY_COUNT,
+        SKIP_COUNT
+    FROM V_STAT_CARD_DAY_LOG;
+
+    INSERT INTO STAT_CREDITS_DAY(
+        DAY,
+        LOBBY_ID,
+        USER_ID,
+        CATEGORY,
+        SPENT_AMOUNT,
+        EARNED_AMOUNT,
+        MAX_AMOUNT,
+        MIN_AMOUNT
+    )
+    SELECT
+        DAY,
+        LOBBY_ID,
+        USER_ID,
+        CATEGORY,
+        SPENT_AMOUNT,
+        EARNED_AMOUNT,
+        MAX_AMOUNT,
+        MIN_AMOUNT
+    FROM V_STAT_CREDITS_DAY_LOG;
+
+    INSERT INTO STAT_PICK_DAY(
+        DAY,
+        LOBBY_ID,
+        JUDGE_USER_ID,
+        PLAYER_USER_ID,
+        ROUND_WIN_COUNT
+    )
+    SELECT
+        DAY,
+        LOBBY_ID,
+        JUDGE_USER_ID,
+        PLAYER_USER_ID,
+        ROUND_WIN_COUNT
+    FROM V_STAT_PICK_DAY_LOG;
+
+    INSERT INTO STAT_PICK_CARD_DAY(
+        DAY,
+        LOBBY_ID,
+        JUDGE_USER_ID,
+        PLAYER_USER_ID,
+        CARD_ID,
+        ROUND_WIN_COUNT
+    )
+    SELECT
+        DAY,
+        LOBBY_ID,
+        JUDGE_USER_ID,
+        PLAYER_USER_ID,
+        CARD_ID,
+        ROUND_WIN_COUNT
+    FROM V_STAT_PICK_CARD_DAY_LOG;
+
+    INSERT INTO STAT_SPECIAL_DAY(
+        DAY,
+        LOBBY_ID,
+        JUDGE_USER_ID,
+        PLAYER_USER_ID,
+        SPECIAL_CATEGORY,
+        RESPONSE_PLAY_COUNT,
+        ROUND_PLAY_COUNT,
+        ROUND_WIN_COUNT
+    )
+    SELECT
+        DAY,
+        LOBBY_ID,
+        JUDGE_USER_ID,
+        PLAYER_USER_ID,
+        SPECIAL_CATEGORY,
+        RESPONSE_PLAY_COUNT,
+        ROUND_PLAY_COUNT,
+        ROUND_WIN_COUNT
+    FROM V_STAT_SPECIAL_DAY_LOG;
+
+    INSERT INTO SETTING(NAME, VALUE)
+    VALUES ('STAT_ROLLUP_DAY', VAR_TO_DAY)
+    ON DUPLICATE KEY UPDATE
+        VALUE = VAR_TO_DAY;
+
+    COMMIT;
+
+    DO RELEASE_LOCK('SP_UPDATE_STAT_ROLLUPS');
+END;
\ No newline at end of file
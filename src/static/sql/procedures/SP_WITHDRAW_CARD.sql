@@ -4,15 +4,18 @@ BEGIN
     DECLARE VAR_RESPONSE_ID UUID;
     DECLARE VAR_CARD_ID UUID;
     DECLARE VAR_PLAYER_ID UUID;
+    DECLARE VAR_SLOT INT;
 
     SELECT
         RC.RESPONSE_ID,
         RC.CARD_ID,
-        R.PLAYER_ID
+        R.PLAYER_ID,
+        RC.SLOT
     INTO
         VAR_RESPONSE_ID,
         VAR_CARD_ID,
-        VAR_PLAYER_ID
+        VAR_PLAYER_ID,
+        VAR_SLOT
     FROM RESPONSE_CARD AS RC
         INNER JOIN RESPONSE AS R ON R.ID = RC.RESPONSE_ID
     WHERE RC.ID = VAR_RESPONSE_CARD_ID;
@@ -29,6 +32,11 @@ BEGIN
     FROM RESPONSE_CARD
     WHERE ID = VAR_RESPONSE_CARD_ID;
 
+    UPDATE RESPONSE_CARD
+    SET SLOT = SLOT - 1
+    WHERE RESPONSE_ID = VAR_RESPONSE_ID
+        AND SLOT > VAR_SLOT;
+
     DELETE
     FROM LOG_RESPONSE_CARD
     WHERE RESPONSE_CARD_ID = VAR_RESPONSE_CARD_ID;
@@ -8,5 +8,7 @@ CREATE TABLE IF NOT EXISTS AUDIT_CARD(
     TEXT VARCHAR(510) NOT NULL,
     YOUTUBE CHAR(11) NULL,
     IMAGE BLOB NULL,
+    CONTENT_RATING ENUM('FAMILY', 'TEEN', 'MATURE') NULL,
+    TAGS VARCHAR(2040) NULL,
     PRIMARY KEY(ID)
 );
\ No newline at end of file
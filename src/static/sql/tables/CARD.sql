@@ -7,6 +7,8 @@ CREATE TABLE IF NOT EXISTS CARD(
     TEXT VARCHAR(510) NOT NULL,
     YOUTUBE CHAR(11) NULL,
     IMAGE BLOB NULL,
+    SOURCE_CARD_ID UUID NULL,
+    CONTENT_RATING ENUM('FAMILY', 'TEEN', 'MATURE') NULL,
     PRIMARY KEY(ID),
     FOREIGN KEY(DECK_ID) REFERENCES DECK(ID) ON DELETE CASCADE,
     FULLTEXT KEY(TEXT),
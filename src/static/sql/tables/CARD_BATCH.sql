@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS CARD_BATCH(
+    ID UUID NOT NULL DEFAULT UUID(),
+    CREATED_ON_DATE DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    DECK_ID UUID NOT NULL,
+    TARGET_DECK_ID UUID NULL,
+    USER_ID UUID NULL,
+    OPERATION ENUM('MOVE', 'CATEGORY', 'REPLACE', 'DELETE', 'ADD_TAGS', 'REMOVE_TAGS', 'CLEAR_IMAGES') NOT NULL,
+    DETAIL VARCHAR(510) NULL,
+    CARD_COUNT INT NOT NULL DEFAULT 0,
+    UNDONE_ON_DATE DATETIME(6) NULL,
+    PRIMARY KEY(ID),
+    FOREIGN KEY(DECK_ID) REFERENCES DECK(ID) ON DELETE CASCADE,
+    FOREIGN KEY(TARGET_DECK_ID) REFERENCES DECK(ID) ON DELETE CASCADE,
+    FOREIGN KEY(USER_ID) REFERENCES USER (ID) ON DELETE SET NULL
+);
\ No newline at end of file
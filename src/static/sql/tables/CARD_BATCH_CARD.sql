@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS CARD_BATCH_CARD(
+    ID UUID NOT NULL DEFAULT UUID(),
+    BATCH_ID UUID NOT NULL,
+    CARD_ID UUID NOT NULL,
+    CARD_CREATED_ON_DATE DATETIME(6) NOT NULL,
+    DECK_ID UUID NOT NULL,
+    CATEGORY ENUM('PROMPT', 'RESPONSE') NOT NULL,
+    TEXT VARCHAR(510) NOT NULL,
+    YOUTUBE CHAR(11) NULL,
+    IMAGE BLOB NULL,
+    SOURCE_CARD_ID UUID NULL,
+    CONTENT_RATING ENUM('FAMILY', 'TEEN', 'MATURE') NULL,
+    PRIMARY KEY(ID),
+    FOREIGN KEY(BATCH_ID) REFERENCES CARD_BATCH(ID) ON DELETE CASCADE,
+    CONSTRAINT BATCH_CARD_UNIQUE UNIQUE(BATCH_ID, CARD_ID)
+);
\ No newline at end of file
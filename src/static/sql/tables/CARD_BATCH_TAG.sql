@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS CARD_BATCH_TAG(
+    ID UUID NOT NULL DEFAULT UUID(),
+    BATCH_ID UUID NOT NULL,
+    CARD_ID UUID NOT NULL,
+    TAG VARCHAR(50) NOT NULL,
+    PRIMARY KEY(ID),
+    FOREIGN KEY(BATCH_ID) REFERENCES CARD_BATCH(ID) ON DELETE CASCADE
+);
\ No newline at end of file
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS CARD_REPORT(
+    ID UUID NOT NULL DEFAULT UUID(),
+    CREATED_ON_DATE DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    CARD_ID UUID NOT NULL,
+    LOBBY_ID UUID NULL,
+    USER_ID UUID NOT NULL,
+    REASON ENUM('OFFENSIVE', 'BROKEN-BLANK', 'DUPLICATE', 'TYPO') NOT NULL,
+    PRIMARY KEY(ID),
+    FOREIGN KEY(LOBBY_ID) REFERENCES LOBBY(ID) ON DELETE SET NULL,
+    FOREIGN KEY(USER_ID) REFERENCES USER (ID) ON DELETE CASCADE,
+    CONSTRAINT CARD_USER_UNIQUE UNIQUE(CARD_ID, USER_ID)
+);
\ No newline at end of file
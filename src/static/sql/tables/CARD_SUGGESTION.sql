@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS CARD_SUGGESTION(
+    ID UUID NOT NULL DEFAULT UUID(),
+    CREATED_ON_DATE DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    CHANGED_ON_DATE DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    DECK_ID UUID NOT NULL,
+    CARD_ID UUID NULL,
+    USER_ID UUID NOT NULL,
+    CATEGORY ENUM('PROMPT', 'RESPONSE') NOT NULL DEFAULT 'PROMPT',
+    TEXT VARCHAR(510) NOT NULL,
+    YOUTUBE CHAR(11) NULL,
+    STATUS ENUM('PENDING', 'APPROVED', 'REJECTED') NOT NULL DEFAULT 'PENDING',
+    REVIEW_NOTE VARCHAR(255) NULL,
+    REVIEWED_BY_USER_ID UUID NULL,
+    IS_SEEN BOOLEAN NOT NULL DEFAULT 0,
+    PRIMARY KEY(ID),
+    FOREIGN KEY(DECK_ID) REFERENCES DECK(ID) ON DELETE CASCADE,
+    FOREIGN KEY(CARD_ID) REFERENCES CARD(ID) ON DELETE CASCADE,
+    FOREIGN KEY(USER_ID) REFERENCES USER (ID) ON DELETE CASCADE,
+    FOREIGN KEY(REVIEWED_BY_USER_ID) REFERENCES USER (ID) ON DELETE SET NULL
+);
\ No newline at end of file
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS CARD_TAG(
+    ID UUID NOT NULL DEFAULT UUID(),
+    CREATED_ON_DATE DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    CARD_ID UUID NOT NULL,
+    TAG VARCHAR(50) NOT NULL,
+    PRIMARY KEY(ID),
+    FOREIGN KEY(CARD_ID) REFERENCES CARD(ID) ON DELETE CASCADE,
+    KEY(TAG),
+    CONSTRAINT CARD_TAG_UNIQUE UNIQUE(CARD_ID, TAG)
+);
\ No newline at end of file
@@ -6,6 +6,10 @@ CREATE TABLE IF NOT EXISTS DECK(
     PASSWORD_HASH CHAR(60) NOT NULL,
     IS_PUBLIC_READONLY BOOLEAN NOT NULL DEFAULT 0,
     IS_LOBBY_WILD_DECK BOOLEAN NOT NULL DEFAULT 0,
+    PASSWORD_ROLE ENUM('NONE', 'VIEWER', 'EDITOR') NOT NULL DEFAULT 'EDITOR',
+    SOURCE_DECK_ID UUID NULL,
+    FORKED_ON_DATE DATETIME(6) NULL,
+    CONTENT_RATING ENUM('FAMILY', 'TEEN', 'MATURE') NULL,
     PRIMARY KEY(ID),
     CONSTRAINT NAME_UNIQUE UNIQUE(NAME)
 );
\ No newline at end of file
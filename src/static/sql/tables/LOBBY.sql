@@ -11,6 +11,8 @@ CREATE TABLE IF NOT EXISTS LOBBY(
     FREE_SPECIAL_CARDS BOOLEAN NOT NULL DEFAULT FALSE,
     WIN_STREAK_THRESHOLD INT NOT NULL DEFAULT 3,
     LOSE_STREAK_THRESHOLD INT NOT NULL DEFAULT 3,
+    MAX_CONTENT_RATING ENUM('FAMILY', 'TEEN', 'MATURE') NULL,
+    DEDUPLICATE_CARDS BOOLEAN NOT NULL DEFAULT FALSE,
     ROUND_ID UUID NOT NULL DEFAULT UUID(),
     PRIMARY KEY(ID),
     CONSTRAINT NAME_UNIQUE UNIQUE(NAME)
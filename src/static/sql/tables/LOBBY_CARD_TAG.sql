@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS LOBBY_CARD_TAG(
+    ID UUID NOT NULL DEFAULT UUID(),
+    CREATED_ON_DATE DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    LOBBY_ID UUID NOT NULL,
+    TAG VARCHAR(50) NOT NULL,
+    FILTER ENUM('INCLUDE', 'EXCLUDE') NOT NULL,
+    PRIMARY KEY(ID),
+    FOREIGN KEY(LOBBY_ID) REFERENCES LOBBY(ID) ON DELETE CASCADE,
+    CONSTRAINT LOBBY_TAG_UNIQUE UNIQUE(LOBBY_ID, TAG)
+);
\ No newline at end of file
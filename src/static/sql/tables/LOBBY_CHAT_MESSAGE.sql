@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS LOBBY_CHAT_MESSAGE(
+    ID UUID NOT NULL DEFAULT UUID(),
+    CREATED_ON_DATE DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    LOBBY_ID UUID NOT NULL,
+    USER_ID UUID NULL,
+    MESSAGE TEXT NOT NULL,
+    PRIMARY KEY(ID),
+    FOREIGN KEY(LOBBY_ID) REFERENCES LOBBY(ID) ON DELETE CASCADE,
+    FOREIGN KEY(USER_ID) REFERENCES USER (ID) ON DELETE CASCADE,
+    KEY(LOBBY_ID, CREATED_ON_DATE)
+);
\ No newline at end of file
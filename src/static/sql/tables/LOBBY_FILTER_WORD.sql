@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS LOBBY_FILTER_WORD(
+    ID UUID NOT NULL DEFAULT UUID(),
+    CREATED_ON_DATE DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    LOBBY_ID UUID NOT NULL,
+    WORD VARCHAR(255) NOT NULL,
+    PRIMARY KEY(ID),
+    FOREIGN KEY(LOBBY_ID) REFERENCES LOBBY(ID) ON DELETE CASCADE,
+    CONSTRAINT LOBBY_WORD_UNIQUE UNIQUE(LOBBY_ID, WORD)
+);
\ No newline at end of file
@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS LOBBY_RULE(
+    ID UUID NOT NULL DEFAULT UUID(),
+    CREATED_ON_DATE DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    LOBBY_ID UUID NOT NULL,
+    CATEGORY ENUM(
+        'ALERT',
+        'GAMBLE',
+        'BET',
+        'SKIP-JUDGE',
+        'EXTRA-RESPONSE',
+        'BLOCK-RESPONSE',
+        'SURPRISE',
+        'STEAL',
+        'FIND',
+        'WILD',
+        'PERK',
+        'FLIP-TABLE'
+    ) NOT NULL,
+    COST INT NULL,
+    IS_ENABLED BOOLEAN NOT NULL DEFAULT 1,
+    PRIMARY KEY(ID),
+    FOREIGN KEY(LOBBY_ID) REFERENCES LOBBY(ID) ON DELETE CASCADE,
+    CONSTRAINT LOBBY_CATEGORY_UNIQUE UNIQUE(LOBBY_ID, CATEGORY)
+);
\ No newline at end of file
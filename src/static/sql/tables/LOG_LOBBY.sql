@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS LOG_LOBBY(
+    ID UUID NOT NULL,
+    CREATED_ON_DATE DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    NAME VARCHAR(255) NOT NULL,
+    PRIMARY KEY(ID, NAME),
+    KEY(NAME)
+);
\ No newline at end of file
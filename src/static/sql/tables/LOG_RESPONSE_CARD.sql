@@ -10,5 +10,8 @@ CREATE TABLE IF NOT EXISTS LOG_RESPONSE_CARD(
     PLAYER_USER_ID UUID NOT NULL,
     PLAYER_CARD_ID UUID NOT NULL,
     SPECIAL_CATEGORY ENUM('SURPRISE', 'STEAL', 'FIND', 'WILD') NULL,
+    PROMPT_TEXT VARCHAR(510) NULL,
+    RESPONSE_TEXT VARCHAR(510) NULL,
+    LOBBY_HAS_PASSWORD BOOLEAN NULL,
     PRIMARY KEY(ID)
 );
\ No newline at end of file
@@ -3,6 +3,7 @@ CREATE TABLE IF NOT EXISTS RESPONSE_CARD(
     CREATED_ON_DATE DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
     RESPONSE_ID UUID NOT NULL,
     CARD_ID UUID NOT NULL,
+    SLOT INT NOT NULL DEFAULT 1,
     SPECIAL_CATEGORY ENUM('SURPRISE', 'STEAL', 'FIND', 'WILD') NULL DEFAULT NULL,
     PRIMARY KEY(ID),
     FOREIGN KEY(RESPONSE_ID) REFERENCES RESPONSE(ID) ON DELETE CASCADE,
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS SEASON(
+    ID UUID NOT NULL DEFAULT UUID(),
+    CREATED_ON_DATE DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    NAME VARCHAR(255) NOT NULL,
+    START_DATE DATE NOT NULL,
+    END_DATE DATE NOT NULL,
+    ARCHIVED_ON_DATE DATETIME(6) NULL,
+    PRIMARY KEY(ID),
+    KEY(START_DATE)
+);
\ No newline at end of file
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS SEASON_RATING(
+    SEASON_ID UUID NOT NULL,
+    USER_ID UUID NOT NULL,
+    RATING DOUBLE NOT NULL,
+    ROUND_COUNT INT NOT NULL DEFAULT 0,
+    PRIMARY KEY(SEASON_ID, USER_ID),
+    FOREIGN KEY(SEASON_ID) REFERENCES SEASON(ID) ON DELETE CASCADE,
+    FOREIGN KEY(USER_ID) REFERENCES USER (ID) ON DELETE CASCADE
+);
\ No newline at end of file
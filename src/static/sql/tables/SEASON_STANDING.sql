@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS SEASON_STANDING(
+    SEASON_ID UUID NOT NULL,
+    RANKING INT NOT NULL,
+    USER_ID UUID NULL,
+    USER_NAME VARCHAR(255) NOT NULL,
+    RATING INT NOT NULL,
+    ROUND_PLAY_COUNT INT NOT NULL DEFAULT 0,
+    ROUND_WIN_COUNT INT NOT NULL DEFAULT 0,
+    GAME_PLAY_COUNT INT NOT NULL DEFAULT 0,
+    GAME_WIN_COUNT INT NOT NULL DEFAULT 0,
+    PRIMARY KEY(SEASON_ID, RANKING),
+    FOREIGN KEY(SEASON_ID) REFERENCES SEASON(ID) ON DELETE CASCADE
+);
\ No newline at end of file
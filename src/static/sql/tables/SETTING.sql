@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS SETTING(
+    ID UUID NOT NULL DEFAULT UUID(),
+    CREATED_ON_DATE DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    NAME VARCHAR(255) NOT NULL,
+    VALUE VARCHAR(255) NOT NULL,
+    PRIMARY KEY(ID),
+    CONSTRAINT NAME_UNIQUE UNIQUE(NAME)
+);
\ No newline at end of file
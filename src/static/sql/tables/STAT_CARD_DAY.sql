@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS STAT_CARD_DAY(
+    DAY DATE NOT NULL,
+    LOBBY_ID UUID NOT NULL,
+    USER_ID UUID NOT NULL,
+    CARD_ID UUID NOT NULL,
+    RESPONSE_PLAY_COUNT INT NOT NULL DEFAULT 0,
+    ROUND_PLAY_COUNT INT NOT NULL DEFAULT 0,
+    ROUND_WIN_COUNT INT NOT NULL DEFAULT 0,
+    DISCARD_COUNT INT NOT NULL DEFAULT 0,
+    PROMPT_PLAY_COUNT INT NOT NULL DEFAULT 0,
+    SKIP_COUNT INT NOT NULL DEFAULT 0,
+    PRIMARY KEY(DAY, LOBBY_ID, USER_ID, CARD_ID),
+    KEY(CARD_ID)
+);
\ No newline at end of file
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS STAT_CREDITS_DAY(
+    DAY DATE NOT NULL,
+    LOBBY_ID UUID NOT NULL,
+    USER_ID UUID NOT NULL,
+    CATEGORY VARCHAR(255) NOT NULL,
+    SPENT_AMOUNT INT NOT NULL DEFAULT 0,
+    EARNED_AMOUNT INT NOT NULL DEFAULT 0,
+    MAX_AMOUNT INT NOT NULL,
+    MIN_AMOUNT INT NOT NULL,
+    PRIMARY KEY(DAY, LOBBY_ID, USER_ID, CATEGORY),
+    KEY(USER_ID)
+);
\ No newline at end of file
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS STAT_PICK_CARD_DAY(
+    DAY DATE NOT NULL,
+    LOBBY_ID UUID NOT NULL,
+    JUDGE_USER_ID UUID NOT NULL,
+    PLAYER_USER_ID UUID NOT NULL,
+    CARD_ID UUID NOT NULL,
+    ROUND_WIN_COUNT INT NOT NULL DEFAULT 0,
+    PRIMARY KEY(DAY, LOBBY_ID, JUDGE_USER_ID, PLAYER_USER_ID, CARD_ID),
+    KEY(JUDGE_USER_ID),
+    KEY(PLAYER_USER_ID)
+);
\ No newline at end of file
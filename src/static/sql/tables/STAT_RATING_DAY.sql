@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS STAT_RATING_DAY(
+    DAY DATE NOT NULL,
+    LOBBY_ID UUID NOT NULL,
+    USER_ID UUID NOT NULL,
+    RATING_CHANGE DOUBLE NOT NULL DEFAULT 0,
+    ROUND_COUNT INT NOT NULL DEFAULT 0,
+    PRIMARY KEY(DAY, LOBBY_ID, USER_ID),
+    KEY(USER_ID)
+);
\ No newline at end of file
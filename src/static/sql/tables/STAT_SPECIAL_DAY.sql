@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS STAT_SPECIAL_DAY(
+    DAY DATE NOT NULL,
+    LOBBY_ID UUID NOT NULL,
+    JUDGE_USER_ID UUID NOT NULL,
+    PLAYER_USER_ID UUID NOT NULL,
+    SPECIAL_CATEGORY ENUM('NONE', 'SURPRISE', 'STEAL', 'FIND', 'WILD') NOT NULL,
+    RESPONSE_PLAY_COUNT INT NOT NULL DEFAULT 0,
+    ROUND_PLAY_COUNT INT NOT NULL DEFAULT 0,
+    ROUND_WIN_COUNT INT NOT NULL DEFAULT 0,
+    PRIMARY KEY(DAY, LOBBY_ID, JUDGE_USER_ID, PLAYER_USER_ID, SPECIAL_CATEGORY),
+    KEY(JUDGE_USER_ID)
+);
\ No newline at end of file
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS STAT_USER_DAY(
+    DAY DATE NOT NULL,
+    LOBBY_ID UUID NOT NULL,
+    USER_ID UUID NOT NULL,
+    ROUND_PLAY_COUNT INT NOT NULL DEFAULT 0,
+    ROUND_WIN_COUNT INT NOT NULL DEFAULT 0,
+    RESPONSE_CARD_PLAY_COUNT INT NOT NULL DEFAULT 0,
+    RESPONSE_CARD_DISCARD_COUNT INT NOT NULL DEFAULT 0,
+    PROMPT_CARD_PLAY_COUNT INT NOT NULL DEFAULT 0,
+    PROMPT_CARD_SKIP_COUNT INT NOT NULL DEFAULT 0,
+    KICK_COUNT INT NOT NULL DEFAULT 0,
+    FLIP_TABLE_COUNT INT NOT NULL DEFAULT 0,
+    PRIMARY KEY(DAY, LOBBY_ID, USER_ID),
+    KEY(USER_ID),
+    KEY(LOBBY_ID)
+);
\ No newline at end of file
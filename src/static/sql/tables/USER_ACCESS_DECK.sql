@@ -3,6 +3,7 @@ CREATE TABLE IF NOT EXISTS USER_ACCESS_DECK(
     CREATED_ON_DATE DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
     USER_ID UUID NOT NULL,
     DECK_ID UUID NOT NULL,
+    ROLE ENUM('VIEWER', 'EDITOR', 'OWNER') NOT NULL DEFAULT 'VIEWER',
     PRIMARY KEY(ID),
     FOREIGN KEY(USER_ID) REFERENCES USER (ID) ON DELETE CASCADE,
     FOREIGN KEY(DECK_ID) REFERENCES DECK(ID) ON DELETE CASCADE,
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS USER_ACHIEVEMENT(
+    USER_ID UUID NOT NULL,
+    ACHIEVEMENT_KEY VARCHAR(50) NOT NULL,
+    TIER INT NOT NULL,
+    CREATED_ON_DATE DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    LOBBY_ID UUID NULL,
+    PRIMARY KEY(USER_ID, ACHIEVEMENT_KEY, TIER),
+    FOREIGN KEY(USER_ID) REFERENCES USER (ID) ON DELETE CASCADE
+);
\ No newline at end of file
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS USER_RATING(
+    USER_ID UUID NOT NULL,
+    CHANGED_ON_DATE DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    RATING DOUBLE NOT NULL,
+    ROUND_COUNT INT NOT NULL DEFAULT 0,
+    PRIMARY KEY(USER_ID),
+    FOREIGN KEY(USER_ID) REFERENCES USER (ID) ON DELETE CASCADE
+);
\ No newline at end of file
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS USER_RATING_HISTORY(
+    ID UUID NOT NULL DEFAULT UUID(),
+    CREATED_ON_DATE DATETIME(6) NOT NULL,
+    USER_ID UUID NOT NULL,
+    ROUND_ID UUID NOT NULL,
+    RATING DOUBLE NOT NULL,
+    RATING_CHANGE DOUBLE NOT NULL,
+    PRIMARY KEY(ID),
+    FOREIGN KEY(USER_ID) REFERENCES USER (ID) ON DELETE CASCADE,
+    KEY(CREATED_ON_DATE),
+    CONSTRAINT USER_RATING_HISTORY_UNIQUE UNIQUE(USER_ID, ROUND_ID)
+);
\ No newline at end of file
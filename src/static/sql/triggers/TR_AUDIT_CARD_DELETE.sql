@@ -18,7 +18,9 @@ BEGIN
             CATEGORY,
             TEXT,
             YOUTUBE,
-            IMAGE
+            IMAGE,
+            CONTENT_RATING,
+            TAGS
         )
         VALUES (
             'DELETE',
@@ -27,7 +29,14 @@ BEGIN
             OLD.CATEGORY,
             OLD.TEXT,
             OLD.YOUTUBE,
-            OLD.IMAGE
+            OLD.IMAGE,
+            OLD.CONTENT_RATING,
+            (
+                SELECT
+                    COALESCE(GROUP_CONCAT(TAG ORDER BY TAG), '')
+                FROM CARD_TAG
+                WHERE CARD_ID = OLD.ID
+            )
         );
     END
     IF;
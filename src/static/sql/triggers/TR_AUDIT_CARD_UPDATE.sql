@@ -10,7 +10,9 @@ BEGIN
         CATEGORY,
         TEXT,
         YOUTUBE,
-        IMAGE
+        IMAGE,
+        CONTENT_RATING,
+        TAGS
     )
     VALUES (
         'UPDATE',
@@ -19,6 +21,13 @@ BEGIN
         OLD.CATEGORY,
         OLD.TEXT,
         OLD.YOUTUBE,
-        OLD.IMAGE
+        OLD.IMAGE,
+        OLD.CONTENT_RATING,
+        (
+            SELECT
+                COALESCE(GROUP_CONCAT(TAG ORDER BY TAG), '')
+            FROM CARD_TAG
+            WHERE CARD_ID = OLD.ID
+        )
     );
 END;
\ No newline at end of file
@@ -13,4 +13,7 @@ BEGIN
         '000000000000000000000000000000000000000000000000000000000000',
         TRUE
     );
+
+    INSERT INTO LOG_LOBBY(ID, NAME)
+    VALUES (NEW.ID, NEW.NAME);
 END;
\ No newline at end of file
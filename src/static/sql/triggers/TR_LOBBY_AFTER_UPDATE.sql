@@ -3,6 +3,14 @@ OR REPLACE TRIGGER TR_LOBBY_AFTER_UPDATE
 AFTER UPDATE ON LOBBY
 FOR EACH ROW
 BEGIN
+    IF NEW.NAME <> OLD.NAME THEN
+        INSERT INTO LOG_LOBBY(ID, NAME)
+        VALUES (NEW.ID, NEW.NAME)
+        ON DUPLICATE KEY
+        UPDATE CREATED_ON_DATE = CURRENT_TIMESTAMP(6);
+    END
+    IF;
+
     IF NEW.HAND_SIZE > OLD.HAND_SIZE THEN
         BEGIN
             DECLARE VAR_LOOP_DONE BOOLEAN DEFAULT FALSE;
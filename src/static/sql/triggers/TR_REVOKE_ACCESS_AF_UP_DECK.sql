@@ -3,10 +3,12 @@ OR REPLACE TRIGGER TR_REVOKE_ACCESS_AF_UP_DECK
 AFTER UPDATE ON DECK
 FOR EACH ROW
 BEGIN
+    -- OWNERS KEEP ACCESS, EVERYONE ELSE MUST BE INVITED OR USE THE NEW PASSWORD
     IF OLD.PASSWORD_HASH <> NEW.PASSWORD_HASH THEN
         DELETE
         FROM USER_ACCESS_DECK
-        WHERE DECK_ID = NEW.ID;
+        WHERE DECK_ID = NEW.ID
+            AND ROLE <> 'OWNER';
     END
     IF;
 END;
\ No newline at end of file
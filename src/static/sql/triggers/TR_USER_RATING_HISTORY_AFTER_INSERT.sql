@@ -0,0 +1,21 @@
+CREATE
+OR REPLACE TRIGGER TR_USER_RATING_HISTORY_AFTER_INSERT
+AFTER INSERT ON USER_RATING_HISTORY
+FOR EACH ROW
+BEGIN
+    -- RATINGS CAN BE GIVEN LONG AFTER THE ROUND, SO THEIR ROLLUP IS KEPT HERE
+    -- INSTEAD OF BEING REBUILT FROM THE LAST DAY ROLLED UP
+    INSERT INTO STAT_RATING_DAY(DAY, LOBBY_ID, USER_ID, RATING_CHANGE, ROUND_COUNT)
+    SELECT
+        DATE(NEW.CREATED_ON_DATE),
+        LOBBY_ID,
+        NEW.USER_ID,
+        NEW.RATING_CHANGE,
+        1
+    FROM LOG_RESPONSE_CARD
+    WHERE ROUND_ID = NEW.ROUND_ID
+    LIMIT 1
+    ON DUPLICATE KEY
+    UPDATE RATING_CHANGE = STAT_RATING_DAY.RATING_CHANGE + NEW.RATING_CHANGE,
+        ROUND_COUNT = STAT_RATING_DAY.ROUND_COUNT + 1;
+END;
\ No newline at end of file
@@ -0,0 +1,13 @@
+CREATE
+OR REPLACE VIEW V_LOBBY_NAME AS
+SELECT
+    ID,
+    NAME
+FROM (
+        SELECT
+            ID,
+            NAME,
+            ROW_NUMBER() OVER (PARTITION BY ID ORDER BY CREATED_ON_DATE DESC, NAME) AS NAME_ORDER
+        FROM LOG_LOBBY
+    ) AS LL
+WHERE NAME_ORDER = 1;
\ No newline at end of file
@@ -0,0 +1,16 @@
+-- THE STATUS IS WORKED OUT ON THE DATABASE CLOCK, WHICH ARCHIVING USES TOO
+CREATE
+OR REPLACE VIEW V_SEASON AS
+SELECT
+    ID,
+    NAME,
+    START_DATE,
+    END_DATE,
+    ARCHIVED_ON_DATE,
+    CASE
+        WHEN ARCHIVED_ON_DATE IS NOT NULL THEN 'Archived'
+        WHEN START_DATE > CURRENT_DATE THEN 'Upcoming'
+        WHEN END_DATE < CURRENT_DATE THEN 'Ended'
+        ELSE 'Current'
+    END AS STATUS
+FROM SEASON;
\ No newline at end of file
@@ -0,0 +1,28 @@
+CREATE
+OR REPLACE VIEW V_STAT_CARD_DAY AS
+SELECT
+    DAY,
+    LOBBY_ID,
+    USER_ID,
+    CARD_ID,
+    RESPONSE_PLAY_COUNT,
+    ROUND_PLAY_COUNT,
+    ROUND_WIN_COUNT,
+    DISCARD_COUNT,
+    PROMPT_PLAY_COUNT,
+    SKIP_COUNT
+FROM STAT_CARD_DAY
+WHERE DAY < (SELECT DAY FROM V_STAT_ROLLUP_DAY)
+UNION ALL
+SELECT
+    DAY,
+    LOBBY_ID,
+    USER_ID,
+    CARD_ID,
+    RESPONSE_PLAY_COUNT,
+    ROUND_PLAY_COUNT,
+    ROUND_WIN_COUNT,
+    DISCARD_COUNT,
+    PROMPT_PLAY_COUNT,
+    SKIP_COUNT
+FROM V_STAT_CARD_DAY_LOG;
\ No newline at end of file
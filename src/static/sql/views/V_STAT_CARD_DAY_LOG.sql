@@ -0,0 +1,88 @@
+CREATE
+OR REPLACE VIEW V_STAT_CARD_DAY_LOG AS
+SELECT
+    DAY,
+    LOBBY_ID,
+    USER_ID,
+    CARD_ID,
+    SUM(RESPONSE_PLAY_COUNT) AS RESPONSE_PLAY_COUNT,
+    SUM(ROUND_PLAY_COUNT) AS ROUND_PLAY_COUNT,
+    SUM(ROUND_WIN_COUNT) AS ROUND_WIN_COUNT,
+    SUM(DISCARD_COUNT) AS DISCARD_COUNT,
+    SUM(PROMPT_PLAY_COUNT) AS PROMPT_PLAY_COUNT,
+    SUM(SKIP_COUNT) AS SKIP_COUNT
+FROM (
+        SELECT
+            DAY,
+            LOBBY_ID,
+            PLAYER_USER_ID AS USER_ID,
+            PLAYER_CARD_ID AS CARD_ID,
+            COUNT(DISTINCT ID) AS RESPONSE_PLAY_COUNT,
+            COUNT(DISTINCT ROUND_ID) AS ROUND_PLAY_COUNT,
+            COUNT(DISTINCT CASE WHEN WON THEN ROUND_ID END) AS ROUND_WIN_COUNT,
+            0 AS DISCARD_COUNT,
+            0 AS PROMPT_PLAY_COUNT,
+            0 AS SKIP_COUNT
+        FROM V_STAT_RESPONSE_CARD_LOG
+        GROUP BY DAY,
+            LOBBY_ID,
+            PLAYER_USER_ID,
+            PLAYER_CARD_ID
+        UNION ALL
+        SELECT
+            DAY,
+            LOBBY_ID,
+            JUDGE_USER_ID,
+            JUDGE_CARD_ID,
+            0,
+            0,
+            0,
+            0,
+            COUNT(DISTINCT ROUND_ID),
+            0
+        FROM V_STAT_RESPONSE_CARD_LOG
+        GROUP BY DAY,
+            LOBBY_ID,
+            JUDGE_USER_ID,
+            JUDGE_CARD_ID
+        UNION ALL
+        SELECT
+            DATE(CREATED_ON_DATE),
+            LOBBY_ID,
+            USER_ID,
+            CARD_ID,
+            0,
+            0,
+            0,
+            COUNT(*),
+            0,
+            0
+        FROM LOG_DISCARD
+        WHERE CREATED_ON_DATE >= (SELECT DAY FROM V_STAT_ROLLUP_DAY)
+        GROUP BY DATE(CREATED_ON_DATE),
+            LOBBY_ID,
+            USER_ID,
+            CARD_ID
+        UNION ALL
+        SELECT
+            DATE(CREATED_ON_DATE),
+            LOBBY_ID,
+            USER_ID,
+            CARD_ID,
+            0,
+            0,
+            0,
+            0,
+            0,
+            COUNT(*)
+        FROM LOG_SKIP
+        WHERE CREATED_ON_DATE >= (SELECT DAY FROM V_STAT_ROLLUP_DAY)
+        GROUP BY DATE(CREATED_ON_DATE),
+            LOBBY_ID,
+            USER_ID,
+            CARD_ID
+    ) AS T
+GROUP BY DAY,
+    LOBBY_ID,
+    USER_ID,
+    CARD_ID;
\ No newline at end of file
@@ -0,0 +1,24 @@
+CREATE
+OR REPLACE VIEW V_STAT_CREDITS_DAY AS
+SELECT
+    DAY,
+    LOBBY_ID,
+    USER_ID,
+    CATEGORY,
+    SPENT_AMOUNT,
+    EARNED_AMOUNT,
+    MAX_AMOUNT,
+    MIN_AMOUNT
+FROM STAT_CREDITS_DAY
+WHERE DAY < (SELECT DAY FROM V_STAT_ROLLUP_DAY)
+UNION ALL
+SELECT
+    DAY,
+    LOBBY_ID,
+    USER_ID,
+    CATEGORY,
+    SPENT_AMOUNT,
+    EARNED_AMOUNT,
+    MAX_AMOUNT,
+    MIN_AMOUNT
+FROM V_STAT_CREDITS_DAY_LOG;
\ No newline at end of file
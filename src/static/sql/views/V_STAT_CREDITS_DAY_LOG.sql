@@ -0,0 +1,17 @@
+CREATE
+OR REPLACE VIEW V_STAT_CREDITS_DAY_LOG AS
+SELECT
+    DATE(CREATED_ON_DATE) AS DAY,
+    LOBBY_ID,
+    USER_ID,
+    CATEGORY,
+    SUM(CASE WHEN AMOUNT > 0 THEN AMOUNT ELSE 0 END) AS SPENT_AMOUNT,
+    SUM(CASE WHEN AMOUNT < 0 THEN AMOUNT * -1 ELSE 0 END) AS EARNED_AMOUNT,
+    MAX(AMOUNT) AS MAX_AMOUNT,
+    MIN(AMOUNT) AS MIN_AMOUNT
+FROM LOG_CREDITS_SPENT
+WHERE CREATED_ON_DATE >= (SELECT DAY FROM V_STAT_ROLLUP_DAY)
+GROUP BY DATE(CREATED_ON_DATE),
+    LOBBY_ID,
+    USER_ID,
+    CATEGORY;
\ No newline at end of file
@@ -0,0 +1,19 @@
+CREATE
+OR REPLACE VIEW V_STAT_GAME_WINNER AS
+SELECT
+    LOBBY_ID,
+    USER_ID,
+    DAY
+FROM (
+        SELECT
+            LOBBY_ID,
+            USER_ID,
+            SUM(ROUND_WIN_COUNT) AS ROUND_WIN_COUNT,
+            RANK() OVER (PARTITION BY LOBBY_ID ORDER BY SUM(ROUND_WIN_COUNT) DESC) AS RANKING,
+            MAX(CASE WHEN ROUND_WIN_COUNT > 0 THEN DAY END) AS DAY
+        FROM V_STAT_USER_DAY
+        GROUP BY LOBBY_ID,
+            USER_ID
+        HAVING SUM(ROUND_WIN_COUNT) > 0
+    ) AS LOBBY_RANKING
+WHERE RANKING = 1;
\ No newline at end of file
@@ -0,0 +1,20 @@
+CREATE
+OR REPLACE VIEW V_STAT_PICK_CARD_DAY AS
+SELECT
+    DAY,
+    LOBBY_ID,
+    JUDGE_USER_ID,
+    PLAYER_USER_ID,
+    CARD_ID,
+    ROUND_WIN_COUNT
+FROM STAT_PICK_CARD_DAY
+WHERE DAY < (SELECT DAY FROM V_STAT_ROLLUP_DAY)
+UNION ALL
+SELECT
+    DAY,
+    LOBBY_ID,
+    JUDGE_USER_ID,
+    PLAYER_USER_ID,
+    CARD_ID,
+    ROUND_WIN_COUNT
+FROM V_STAT_PICK_CARD_DAY_LOG;
\ No newline at end of file
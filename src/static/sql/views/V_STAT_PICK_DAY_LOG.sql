@@ -0,0 +1,14 @@
+CREATE
+OR REPLACE VIEW V_STAT_PICK_DAY_LOG AS
+SELECT
+    DAY,
+    LOBBY_ID,
+    JUDGE_USER_ID,
+    PLAYER_USER_ID,
+    COUNT(DISTINCT ROUND_ID) AS ROUND_WIN_COUNT
+FROM V_STAT_RESPONSE_CARD_LOG
+WHERE WON
+GROUP BY DAY,
+    LOBBY_ID,
+    JUDGE_USER_ID,
+    PLAYER_USER_ID;
\ No newline at end of file
@@ -0,0 +1,32 @@
+-- RESPONSES NOT YET ROLLED UP, ON THE DAY THEIR ROUND STARTED SO A ROUND THAT
+-- RUNS PAST MIDNIGHT IS ONLY COUNTED ONCE
+CREATE
+OR REPLACE VIEW V_STAT_RESPONSE_CARD_LOG AS
+SELECT
+    RD.DAY,
+    LRC.ID,
+    LRC.LOBBY_ID,
+    LRC.ROUND_ID,
+    LRC.JUDGE_USER_ID,
+    LRC.JUDGE_CARD_ID,
+    LRC.PLAYER_USER_ID,
+    LRC.PLAYER_CARD_ID,
+    COALESCE(LRC.SPECIAL_CATEGORY, 'NONE') AS SPECIAL_CATEGORY,
+    LW.ID IS NOT NULL AS WON
+FROM (
+        SELECT
+            ROUND_ID,
+            DATE(MIN(CREATED_ON_DATE)) AS DAY
+        FROM LOG_RESPONSE_CARD AS LRC
+        WHERE CREATED_ON_DATE >= (SELECT DAY FROM V_STAT_ROLLUP_DAY)
+            AND NOT EXISTS (
+                SELECT
+                    ID
+                FROM LOG_RESPONSE_CARD
+                WHERE ROUND_ID = LRC.ROUND_ID
+                    AND CREATED_ON_DATE < (SELECT DAY FROM V_STAT_ROLLUP_DAY)
+            )
+        GROUP BY ROUND_ID
+    ) AS RD
+    INNER JOIN LOG_RESPONSE_CARD AS LRC ON LRC.ROUND_ID = RD.ROUND_ID
+    LEFT JOIN LOG_WIN AS LW ON LW.RESPONSE_ID = LRC.RESPONSE_ID;
\ No newline at end of file
@@ -0,0 +1,12 @@
+CREATE
+OR REPLACE VIEW V_STAT_ROLLUP_DAY AS
+SELECT
+    COALESCE(
+        (
+            SELECT
+                CAST(VALUE AS DATE)
+            FROM SETTING
+            WHERE NAME = 'STAT_ROLLUP_DAY'
+        ),
+        DATE('1970-01-01')
+    ) AS DAY;
\ No newline at end of file
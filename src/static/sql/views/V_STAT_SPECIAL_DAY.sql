@@ -0,0 +1,24 @@
+CREATE
+OR REPLACE VIEW V_STAT_SPECIAL_DAY AS
+SELECT
+    DAY,
+    LOBBY_ID,
+    JUDGE_USER_ID,
+    PLAYER_USER_ID,
+    SPECIAL_CATEGORY,
+    RESPONSE_PLAY_COUNT,
+    ROUND_PLAY_COUNT,
+    ROUND_WIN_COUNT
+FROM STAT_SPECIAL_DAY
+WHERE DAY < (SELECT DAY FROM V_STAT_ROLLUP_DAY)
+UNION ALL
+SELECT
+    DAY,
+    LOBBY_ID,
+    JUDGE_USER_ID,
+    PLAYER_USER_ID,
+    SPECIAL_CATEGORY,
+    RESPONSE_PLAY_COUNT,
+    ROUND_PLAY_COUNT,
+    ROUND_WIN_COUNT
+FROM V_STAT_SPECIAL_DAY_LOG;
\ No newline at end of file
@@ -0,0 +1,17 @@
+CREATE
+OR REPLACE VIEW V_STAT_SPECIAL_DAY_LOG AS
+SELECT
+    DAY,
+    LOBBY_ID,
+    JUDGE_USER_ID,
+    PLAYER_USER_ID,
+    SPECIAL_CATEGORY,
+    COUNT(DISTINCT ID) AS RESPONSE_PLAY_COUNT,
+    COUNT(DISTINCT ROUND_ID) AS ROUND_PLAY_COUNT,
+    COUNT(DISTINCT CASE WHEN WON THEN ROUND_ID END) AS ROUND_WIN_COUNT
+FROM V_STAT_RESPONSE_CARD_LOG
+GROUP BY DAY,
+    LOBBY_ID,
+    JUDGE_USER_ID,
+    PLAYER_USER_ID,
+    SPECIAL_CATEGORY;
\ No newline at end of file
@@ -0,0 +1,30 @@
+CREATE
+OR REPLACE VIEW V_STAT_USER_DAY AS
+SELECT
+    DAY,
+    LOBBY_ID,
+    USER_ID,
+    ROUND_PLAY_COUNT,
+    ROUND_WIN_COUNT,
+    RESPONSE_CARD_PLAY_COUNT,
+    RESPONSE_CARD_DISCARD_COUNT,
+    PROMPT_CARD_PLAY_COUNT,
+    PROMPT_CARD_SKIP_COUNT,
+    KICK_COUNT,
+    FLIP_TABLE_COUNT
+FROM STAT_USER_DAY
+WHERE DAY < (SELECT DAY FROM V_STAT_ROLLUP_DAY)
+UNION ALL
+SELECT
+    DAY,
+    LOBBY_ID,
+    USER_ID,
+    ROUND_PLAY_COUNT,
+    ROUND_WIN_COUNT,
+    RESPONSE_CARD_PLAY_COUNT,
+    RESPONSE_CARD_DISCARD_COUNT,
+    PROMPT_CARD_PLAY_COUNT,
+    PROMPT_CARD_SKIP_COUNT,
+    KICK_COUNT,
+    FLIP_TABLE_COUNT
+FROM V_STAT_USER_DAY_LOG;
\ No newline at end of file
@@ -0,0 +1,124 @@
+CREATE
+OR REPLACE VIEW V_STAT_USER_DAY_LOG AS
+SELECT
+    DAY,
+    LOBBY_ID,
+    USER_ID,
+    SUM(ROUND_PLAY_COUNT) AS ROUND_PLAY_COUNT,
+    SUM(ROUND_WIN_COUNT) AS ROUND_WIN_COUNT,
+    SUM(RESPONSE_CARD_PLAY_COUNT) AS RESPONSE_CARD_PLAY_COUNT,
+    SUM(RESPONSE_CARD_DISCARD_COUNT) AS RESPONSE_CARD_DISCARD_COUNT,
+    SUM(PROMPT_CARD_PLAY_COUNT) AS PROMPT_CARD_PLAY_COUNT,
+    SUM(PROMPT_CARD_SKIP_COUNT) AS PROMPT_CARD_SKIP_COUNT,
+    SUM(KICK_COUNT) AS KICK_COUNT,
+    SUM(FLIP_TABLE_COUNT) AS FLIP_TABLE_COUNT
+FROM (
+        SELECT
+            DAY,
+            LOBBY_ID,
+            PLAYER_USER_ID AS USER_ID,
+            COUNT(DISTINCT ROUND_ID) AS ROUND_PLAY_COUNT,
+            COUNT(DISTINCT CASE WHEN WON THEN ROUND_ID END) AS ROUND_WIN_COUNT,
+            COUNT(DISTINCT ID) AS RESPONSE_CARD_PLAY_COUNT,
+            0 AS RESPONSE_CARD_DISCARD_COUNT,
+            0 AS PROMPT_CARD_PLAY_COUNT,
+            0 AS PROMPT_CARD_SKIP_COUNT,
+            0 AS KICK_COUNT,
+            0 AS FLIP_TABLE_COUNT
+        FROM V_STAT_RESPONSE_CARD_LOG
+        GROUP BY DAY,
+            LOBBY_ID,
+            PLAYER_USER_ID
+        UNION ALL
+        SELECT
+            DAY,
+            LOBBY_ID,
+            JUDGE_USER_ID,
+            0,
+            0,
+            0,
+            0,
+            COUNT(DISTINCT ROUND_ID),
+            0,
+            0,
+            0
+        FROM V_STAT_RESPONSE_CARD_LOG
+        GROUP BY DAY,
+            LOBBY_ID,
+            JUDGE_USER_ID
+        UNION ALL
+        SELECT
+            DATE(CREATED_ON_DATE),
+            LOBBY_ID,
+            USER_ID,
+            0,
+            0,
+            0,
+            COUNT(*),
+            0,
+            0,
+            0,
+            0
+        FROM LOG_DISCARD
+        WHERE CREATED_ON_DATE >= (SELECT DAY FROM V_STAT_ROLLUP_DAY)
+        GROUP BY DATE(CREATED_ON_DATE),
+            LOBBY_ID,
+            USER_ID
+        UNION ALL
+        SELECT
+            DATE(CREATED_ON_DATE),
+            LOBBY_ID,
+            USER_ID,
+            0,
+            0,
+            0,
+            0,
+            0,
+            COUNT(*),
+            0,
+            0
+        FROM LOG_SKIP
+        WHERE CREATED_ON_DATE >= (SELECT DAY FROM V_STAT_ROLLUP_DAY)
+        GROUP BY DATE(CREATED_ON_DATE),
+            LOBBY_ID,
+            USER_ID
+        UNION ALL
+        SELECT
+            DATE(CREATED_ON_DATE),
+            LOBBY_ID,
+            USER_ID,
+            0,
+            0,
+            0,
+            0,
+            0,
+            0,
+            COUNT(*),
+            0
+        FROM LOG_KICK
+        WHERE CREATED_ON_DATE >= (SELECT DAY FROM V_STAT_ROLLUP_DAY)
+        GROUP BY DATE(CREATED_ON_DATE),
+            LOBBY_ID,
+            USER_ID
+        UNION ALL
+        SELECT
+            DATE(CREATED_ON_DATE),
+            LOBBY_ID,
+            USER_ID,
+            0,
+            0,
+            0,
+            0,
+            0,
+            0,
+            0,
+            COUNT(*)
+        FROM LOG_FLIP_TABLE
+        WHERE CREATED_ON_DATE >= (SELECT DAY FROM V_STAT_ROLLUP_DAY)
+        GROUP BY DATE(CREATED_ON_DATE),
+            LOBBY_ID,
+            USER_ID
+    ) AS T
+GROUP BY DAY,
+    LOBBY_ID,
+    USER_ID;
\ No newline at end of file
@@ -6,7 +6,7 @@ import "embed"
 var StaticFiles embed.FS
 
 // SQLFiles is the ordered list of SQL files to execute for database setup.
-// Order matters: settings -> tables -> functions -> procedures -> events -> triggers
+// Order matters: settings -> tables -> alters -> functions -> procedures -> events -> triggers
 // Tables must be in dependency order (e.g., DECK before CARD).
 var SQLFiles = []string{
 	// database
@@ -17,6 +17,7 @@ var SQLFiles = []string{
 	"sql/tables/DECK.sql",
 	"sql/tables/CARD.sql",
 	"sql/tables/LOBBY.sql",
+	"sql/tables/LOBBY_RULE.sql",
 	"sql/tables/DRAW_PILE.sql",
 	"sql/tables/PLAYER.sql",
 	"sql/tables/JUDGE.sql",
@@ -37,31 +38,110 @@ var SQLFiles = []string{
 	"sql/tables/LOG_WIN.sql",
 	"sql/tables/LOG_KICK.sql",
 	"sql/tables/LOG_FLIP_TABLE.sql",
+	"sql/tables/LOG_LOBBY.sql",
 	"sql/tables/AUDIT_CARD.sql",
 	"sql/tables/AUDIT_DECK.sql",
 	"sql/tables/AUDIT_USER.sql",
+	"sql/tables/LOBBY_CHAT_MESSAGE.sql",
+	"sql/tables/LOBBY_MUTE.sql",
+	"sql/tables/LOBBY_FILTER_WORD.sql",
+	"sql/tables/CARD_REPORT.sql",
+	"sql/tables/CARD_SUGGESTION.sql",
+	"sql/tables/CARD_TAG.sql",
+	"sql/tables/LOBBY_CARD_TAG.sql",
+	"sql/tables/CARD_BATCH.sql",
+	"sql/tables/CARD_BATCH_CARD.sql",
+	"sql/tables/CARD_BATCH_TAG.sql",
+	"sql/tables/USER_RATING.sql",
+	"sql/tables/USER_RATING_HISTORY.sql",
+	"sql/tables/USER_ACHIEVEMENT.sql",
+	"sql/tables/STAT_USER_DAY.sql",
+	"sql/tables/STAT_CARD_DAY.sql",
+	"sql/tables/STAT_CREDITS_DAY.sql",
+	"sql/tables/STAT_PICK_DAY.sql",
+	"sql/tables/STAT_PICK_CARD_DAY.sql",
+	"sql/tables/STAT_SPECIAL_DAY.sql",
+	"sql/tables/STAT_RATING_DAY.sql",
+	"sql/tables/SEASON.sql",
+	"sql/tables/SEASON_STANDING.sql",
+	"sql/tables/SEASON_RATING.sql",
+	"sql/tables/SETTING.sql",
+
+	// alters (columns added after a table was first released)
+	"sql/alters/DECK_PASSWORD_ROLE.sql",
+	"sql/alters/DECK_SOURCE_DECK.sql",
+	"sql/alters/CARD_SOURCE_CARD.sql",
+	"sql/alters/USER_ACCESS_DECK_ROLE.sql",
+	"sql/alters/USER_ACCESS_DECK_ROLE_EDITOR.sql",
+	"sql/alters/USER_ACCESS_DECK_ROLE_OWNER.sql",
+	"sql/alters/USER_ACCESS_DECK_ROLE_MIGRATED.sql",
+	"sql/alters/LOBBY_CHAT_MESSAGE_MESSAGE.sql",
+	"sql/alters/LOBBY_CHAT_MESSAGE_RAW.sql",
+	"sql/alters/LOBBY_CHAT_MESSAGE_RAW_MIGRATED.sql",
+	"sql/alters/RESPONSE_CARD_SLOT.sql",
+	"sql/alters/CARD_CONTENT_RATING.sql",
+	"sql/alters/AUDIT_CARD_CONTENT_RATING.sql",
+	"sql/alters/AUDIT_CARD_TAGS.sql",
+	"sql/alters/DECK_CONTENT_RATING.sql",
+	"sql/alters/LOBBY_MAX_CONTENT_RATING.sql",
+	"sql/alters/LOBBY_DEDUPLICATE_CARDS.sql",
+	"sql/alters/LOG_RESPONSE_CARD_CREATED_ON_DATE.sql",
+	"sql/alters/LOG_RESPONSE_CARD_RESPONSE_ID.sql",
+	"sql/alters/LOG_RESPONSE_CARD_ROUND_ID.sql",
+	"sql/alters/LOG_RESPONSE_CARD_PROMPT_TEXT.sql",
+	"sql/alters/LOG_RESPONSE_CARD_RESPONSE_TEXT.sql",
+	"sql/alters/LOG_RESPONSE_CARD_LOBBY_HAS_PASSWORD.sql",
+	"sql/alters/LOG_WIN_CREATED_ON_DATE.sql",
+	"sql/alters/LOG_DISCARD_CREATED_ON_DATE.sql",
+	"sql/alters/LOG_SKIP_CREATED_ON_DATE.sql",
+	"sql/alters/LOG_CREDITS_SPENT_CREATED_ON_DATE.sql",
+	"sql/alters/LOG_KICK_CREATED_ON_DATE.sql",
+	"sql/alters/LOG_FLIP_TABLE_CREATED_ON_DATE.sql",
+	"sql/alters/LOG_LOBBY_BACKFILL.sql",
+	"sql/alters/STAT_RATING_DAY_BACKFILL.sql",
 
 	// views
 	"sql/views/V_ROUND_WINNER.sql",
 	"sql/views/V_GAME_WINNER.sql",
+	"sql/views/V_STAT_ROLLUP_DAY.sql",
+	"sql/views/V_STAT_RESPONSE_CARD_LOG.sql",
+	"sql/views/V_STAT_USER_DAY_LOG.sql",
+	"sql/views/V_STAT_CARD_DAY_LOG.sql",
+	"sql/views/V_STAT_CREDITS_DAY_LOG.sql",
+	"sql/views/V_STAT_PICK_DAY_LOG.sql",
+	"sql/views/V_STAT_PICK_CARD_DAY_LOG.sql",
+	"sql/views/V_STAT_SPECIAL_DAY_LOG.sql",
+	"sql/views/V_STAT_USER_DAY.sql",
+	"sql/views/V_STAT_CARD_DAY.sql",
+	"sql/views/V_STAT_CREDITS_DAY.sql",
+	"sql/views/V_STAT_PICK_DAY.sql",
+	"sql/views/V_STAT_PICK_CARD_DAY.sql",
+	"sql/views/V_STAT_SPECIAL_DAY.sql",
+	"sql/views/V_STAT_GAME_WINNER.sql",
+	"sql/views/V_LOBBY_NAME.sql",
+	"sql/views/V_SEASON.sql",
 
 	// functions
 	"sql/functions/FN_GET_DRAW_PILE_CARD_ID.sql",
 	"sql/functions/FN_GET_LOBBY_JUDGE_BLANK_COUNT.sql",
 	"sql/functions/FN_GET_LOBBY_JUDGE_PLAYER_ID.sql",
+	"sql/functions/FN_GET_LOBBY_OWNER_PLAYER_ID.sql",
 	"sql/functions/FN_GET_LOGIN_ATTEMPT_IS_ALLOWED.sql",
 	"sql/functions/FN_GET_PLAYER_HANDICAP.sql",
 	"sql/functions/FN_GET_PLAYER_HANDICAP_INVERSE.sql",
 	"sql/functions/FN_GET_PLAYER_LOBBY_ID.sql",
+	"sql/functions/FN_GET_PLAYER_OPEN_RESPONSE_ID.sql",
 	"sql/functions/FN_GET_PLAYER_RESPONSE_CARD_COUNT.sql",
 	"sql/functions/FN_GET_PLAYER_RESPONSE_COUNT.sql",
 	"sql/functions/FN_GET_SPECIAL_COST.sql",
+	"sql/functions/FN_GET_SPECIAL_IS_ENABLED.sql",
 	"sql/functions/FN_USER_HAS_DECK_ACCESS.sql",
 	"sql/functions/FN_USER_HAS_LOBBY_ACCESS.sql",
 
 	// procedures
 	"sql/procedures/SP_ADD_EXTRA_RESPONSE.sql",
 	"sql/procedures/SP_ADD_EXTRA_RESPONSE_UNDO.sql",
+	"sql/procedures/SP_ADD_LOBBY_CHAT_MESSAGE.sql",
 	"sql/procedures/SP_ALERT_LOBBY.sql",
 	"sql/procedures/SP_BET_ON_WIN.sql",
 	"sql/procedures/SP_BET_ON_WIN_UNDO.sql",
@@ -71,6 +151,8 @@ var SQLFiles = []string{
 	"sql/procedures/SP_FLIP_TABLE.sql",
 	"sql/procedures/SP_GAMBLE_CREDITS.sql",
 	"sql/procedures/SP_GET_READABLE_DECKS.sql",
+	"sql/procedures/SP_KICK_PLAYER.sql",
+	"sql/procedures/SP_MOVE_RESPONSE_CARD.sql",
 	"sql/procedures/SP_PERK_DISCARD_ADVANTAGE.sql",
 	"sql/procedures/SP_PERK_HANDICAP_ADVANTAGE.sql",
 	"sql/procedures/SP_PERK_HAND_SIZE_ADVANTAGE.sql",
@@ -78,7 +160,9 @@ var SQLFiles = []string{
 	"sql/procedures/SP_PICK_RANDOM_WINNER.sql",
 	"sql/procedures/SP_PICK_WINNER.sql",
 	"sql/procedures/SP_PURCHASE_CREDITS.sql",
+	"sql/procedures/SP_REPORT_CARD.sql",
 	"sql/procedures/SP_RESET_RESPONSES.sql",
+	"sql/procedures/SP_REVERT_CARD.sql",
 	"sql/procedures/SP_RESPOND_WITH_CARD.sql",
 	"sql/procedures/SP_RESPOND_WITH_FIND_CARD.sql",
 	"sql/procedures/SP_RESPOND_WITH_FORCE_CARD.sql",
@@ -101,6 +185,8 @@ var SQLFiles = []string{
 	"sql/procedures/SP_SPEND_CREDITS.sql",
 	"sql/procedures/SP_SPEND_CREDITS_UNDO.sql",
 	"sql/procedures/SP_START_NEW_ROUND.sql",
+	"sql/procedures/SP_UNDO_CARD_BATCH.sql",
+	"sql/procedures/SP_UPDATE_STAT_ROLLUPS.sql",
 	"sql/procedures/SP_VOTE_TO_KICK.sql",
 	"sql/procedures/SP_VOTE_TO_KICK_UNDO.sql",
 	"sql/procedures/SP_WITHDRAW_CARD.sql",
@@ -111,7 +197,9 @@ var SQLFiles = []string{
 	"sql/events/EVT_CLEAN_AUDIT_TABLES.sql",
 	"sql/events/EVT_CLEAN_BAD_PROMPT_CARDS.sql",
 	"sql/events/EVT_CLEAN_BAD_RESPONSE_CARDS.sql",
+	"sql/events/EVT_CLEAN_LOBBY_CHAT_MESSAGES.sql",
 	"sql/events/EVT_CLEAN_LOGIN_ATTEMPTS.sql",
+	"sql/events/EVT_UPDATE_STAT_ROLLUPS.sql",
 
 	// triggers
 	"sql/triggers/TR_AUDIT_CARD_DELETE.sql",
@@ -131,4 +219,5 @@ var SQLFiles = []string{
 	"sql/triggers/TR_SET_CHANGED_ON_DATE_BF_UP_CARD.sql",
 	"sql/triggers/TR_SET_CHANGED_ON_DATE_BF_UP_DECK.sql",
 	"sql/triggers/TR_SET_CHANGED_ON_DATE_BF_UP_USER.sql",
+	"sql/triggers/TR_USER_RATING_HISTORY_AFTER_INSERT.sql",
 }
@@ -2,9 +2,12 @@ package websocket
 
 import (
 	"bytes"
+	"fmt"
+	"html"
 	"log"
 	"net/http"
 	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
@@ -24,6 +27,18 @@ const (
 
 	// Maximum message size allowed from peer.
 	maxMessageSize = 512
+
+	// Number of stored chat messages replayed to a client on connect.
+	chatHistorySize = 50
+
+	// Maximum length of a chat message in characters.
+	maxChatMessageLength = 255
+
+	// Number of chat messages a client can send in a burst.
+	chatBurstSize = 5
+
+	// Time for a client to earn back one chat message of its burst.
+	chatRefillPeriod = 2 * time.Second
 )
 
 var (
@@ -49,6 +64,10 @@ type Client struct {
 
 	// Buffered channel of outbound messages.
 	send chan []byte
+
+	// Token bucket limiting how fast the client can chat.
+	chatTokens     float64
+	chatTokensTime time.Time
 }
 
 // readPump pumps messages from the websocket connection to the hub.
@@ -73,11 +92,56 @@ func (c *Client) readPump() {
 			break
 		}
 		message = bytes.TrimSpace(bytes.Replace(message, newline, space, -1))
-		message = []byte("<green>" + c.user.Name + "</>: " + string(message))
-		c.hub.broadcast <- message
+		if len(message) == 0 {
+			continue
+		}
+
+		if utf8.RuneCount(message) > maxChatMessageLength {
+			c.hub.sendPrivate(c.user.Id, []byte(fmt.Sprintf("<red>Message not sent</>: Messages cannot be longer than %d characters.", maxChatMessageLength)))
+			continue
+		}
+
+		if !c.allowChatMessage() {
+			c.hub.sendPrivate(c.user.Id, []byte("<red>Message not sent</>: You are sending messages too quickly."))
+			continue
+		}
+
+		isMuted, err := database.UserIsMutedInLobby(c.hub.lobbyId, c.user.Id)
+		if err == nil && isMuted {
+			c.hub.sendPrivate(c.user.Id, []byte("<red>Message not sent</>: You have been muted in this lobby."))
+			continue
+		}
+
+		if bytes.HasPrefix(message, []byte("/")) {
+			c.runCommand(string(message))
+			continue
+		}
+
+		text, _ := database.FilterLobbyText(c.hub.lobbyId, string(message))
+		err = database.AddLobbyChatMessage(c.hub.lobbyId, c.user.Id, text)
+		if err != nil {
+			log.Println(err)
+		}
+		c.hub.broadcast <- []byte(userChatMessage(c.user.Name, text))
 	}
 }
 
+// allowChatMessage refills the client's token bucket for the time passed and
+// spends a token if one is available.
+func (c *Client) allowChatMessage() bool {
+	now := time.Now()
+	c.chatTokens += float64(now.Sub(c.chatTokensTime)) / float64(chatRefillPeriod)
+	c.chatTokens = min(c.chatTokens, chatBurstSize)
+	c.chatTokensTime = now
+
+	if c.chatTokens < 1 {
+		return false
+	}
+
+	c.chatTokens--
+	return true
+}
+
 // writePump pumps messages from the hub to the websocket connection.
 //
 // A goroutine running writePump is started for each connection. The
@@ -117,6 +181,26 @@ func (c *Client) writePump() {
 	}
 }
 
+// ChatHistoryMessage formats a stored chat message so the client can render it
+// with its original timestamp.
+func ChatHistoryMessage(chatMessage database.ChatMessage) string {
+	message := chatMessage.Message
+	if chatMessage.UserId.Valid {
+		message = userChatMessage(chatMessage.UserName, chatMessage.Message)
+	}
+	return fmt.Sprintf("history;;%d;;%s", chatMessage.CreatedOnDate.UnixMilli(), message)
+}
+
+func userChatMessage(userName string, text string) string {
+	return "<green>" + EscapeText(userName) + "</>: " + EscapeText(text)
+}
+
+// EscapeText escapes user provided text so it cannot inject chat markup or
+// HTML into the messages other clients render.
+func EscapeText(text string) string {
+	return html.EscapeString(text)
+}
+
 // ServeWs handles websocket requests from the peer.
 func ServeWs(w http.ResponseWriter, r *http.Request) {
 	lobbyIdString := r.PathValue("lobbyId")
@@ -158,7 +242,19 @@ func ServeWs(w http.ResponseWriter, r *http.Request) {
 		conn: conn,
 		send: make(chan []byte, 256),
 		user: user,
+
+		chatTokens:     chatBurstSize,
+		chatTokensTime: time.Now(),
 	}
+
+	// replay recent chat before registering so it lands ahead of the join message
+	chatMessages, err := database.GetLobbyChatMessages(lobbyId, time.Now(), chatHistorySize)
+	if err == nil {
+		for _, chatMessage := range chatMessages {
+			client.send <- []byte(ChatHistoryMessage(chatMessage))
+		}
+	}
+
 	client.hub.register <- client
 
 	// Allow collection of memory referenced by the caller by doing all work in
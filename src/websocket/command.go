@@ -0,0 +1,262 @@
+package websocket
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/grantfbarnes/card-judge/database"
+)
+
+type chatCommand struct {
+	usage     string
+	ownerOnly bool
+	run       func(c *Client, player database.Player, args string) error
+}
+
+var chatCommands map[string]chatCommand
+
+func init() {
+	chatCommands = map[string]chatCommand{
+		"roll":    {usage: "/roll <count>d<sides>", run: commandRoll},
+		"coin":    {usage: "/coin", run: commandCoin},
+		"me":      {usage: "/me <action>", run: commandMe},
+		"whisper": {usage: "/whisper <player> <message>", run: commandWhisper},
+		"scores":  {usage: "/scores", run: commandScores},
+		"timer":   {usage: "/timer", run: commandTimer},
+		"kick":    {usage: "/kick <player>", ownerOnly: true, run: commandKick},
+		"skip":    {usage: "/skip", ownerOnly: true, run: commandSkip},
+	}
+}
+
+// runCommand routes a chat message starting with a slash to its command and
+// sends any error back to the client privately.
+func (c *Client) runCommand(text string) {
+	name, args, _ := strings.Cut(strings.TrimPrefix(text, "/"), " ")
+	args = strings.TrimSpace(args)
+
+	err := c.executeCommand(strings.ToLower(name), args)
+	if err != nil {
+		c.hub.sendPrivate(c.user.Id, []byte("<red>Command failed</>: "+EscapeText(err.Error())))
+	}
+}
+
+func (c *Client) executeCommand(name string, args string) error {
+	command, ok := chatCommands[name]
+	if !ok {
+		names := make([]string, 0, len(chatCommands))
+		for name := range chatCommands {
+			names = append(names, "/"+name)
+		}
+		slices.Sort(names)
+		return fmt.Errorf("unknown command, try one of %s", strings.Join(names, ", "))
+	}
+
+	player, err := database.GetLobbyUserPlayer(c.hub.lobbyId, c.user.Id)
+	if err != nil {
+		return err
+	}
+
+	if command.ownerOnly {
+		ownerPlayerId, err := database.GetLobbyOwnerPlayerId(c.hub.lobbyId)
+		if err != nil {
+			return err
+		}
+
+		if player.Id != ownerPlayerId {
+			return errors.New("only the lobby owner can use /" + name)
+		}
+	}
+
+	err = command.run(c, player, args)
+	if errors.Is(err, errCommandUsage) {
+		return errors.New("usage is " + command.usage)
+	}
+
+	return err
+}
+
+var errCommandUsage = errors.New("bad command usage")
+
+// findLobbyPlayerByName finds the active player whose name starts the text,
+// preferring the longest match since names can contain spaces, and returns the
+// remaining text after the name. The player id is nil when nobody matches.
+func findLobbyPlayerByName(lobbyId uuid.UUID, text string) (database.Player, string, error) {
+	var found database.Player
+	var rest string
+
+	players, err := database.GetActiveLobbyPlayers(lobbyId)
+	if err != nil {
+		return found, rest, err
+	}
+
+	for _, player := range players {
+		name := player.Name
+		if len(text) < len(name) || !strings.EqualFold(text[:len(name)], name) {
+			continue
+		}
+
+		remaining := text[len(name):]
+		if remaining != "" && remaining[0] != ' ' {
+			continue
+		}
+
+		if found.Id == uuid.Nil || len(name) > len(found.Name) {
+			found = player
+			rest = strings.TrimSpace(remaining)
+		}
+	}
+
+	return found, rest, nil
+}
+
+func commandRoll(c *Client, player database.Player, args string) error {
+	countString, sidesString, ok := strings.Cut(strings.ToLower(args), "d")
+	if !ok {
+		return errCommandUsage
+	}
+
+	count := 1
+	if countString != "" {
+		var err error
+		count, err = strconv.Atoi(countString)
+		if err != nil {
+			return errCommandUsage
+		}
+	}
+
+	sides, err := strconv.Atoi(sidesString)
+	if err != nil {
+		return errCommandUsage
+	}
+
+	if count < 1 || count > 20 {
+		return errors.New("you can roll between 1 and 20 dice")
+	}
+
+	if sides < 2 || sides > 1000 {
+		return errors.New("dice must have between 2 and 1000 sides")
+	}
+
+	total := 0
+	rolls := make([]string, 0, count)
+	for range count {
+		roll := rand.IntN(sides) + 1
+		total += roll
+		rolls = append(rolls, strconv.Itoa(roll))
+	}
+
+	LobbyBroadcast(c.hub.lobbyId, fmt.Sprintf("<green>%s</> rolled %dd%d: %s = <blue>%d</>", EscapeText(player.Name), count, sides, strings.Join(rolls, " + "), total))
+	return nil
+}
+
+func commandCoin(c *Client, player database.Player, args string) error {
+	side := "Heads"
+	if rand.IntN(2) == 0 {
+		side = "Tails"
+	}
+
+	LobbyBroadcast(c.hub.lobbyId, "<green>"+EscapeText(player.Name)+"</> flipped a coin: <blue>"+side+"</>")
+	return nil
+}
+
+func commandMe(c *Client, player database.Player, args string) error {
+	if args == "" {
+		return errCommandUsage
+	}
+
+	text, _ := database.FilterLobbyText(c.hub.lobbyId, args)
+	LobbyBroadcast(c.hub.lobbyId, "<green>* "+EscapeText(player.Name)+"</> "+EscapeText(text))
+	return nil
+}
+
+func commandWhisper(c *Client, player database.Player, args string) error {
+	targetPlayer, text, err := findLobbyPlayerByName(c.hub.lobbyId, args)
+	if err != nil {
+		return err
+	}
+
+	if targetPlayer.Id == uuid.Nil || text == "" {
+		return errCommandUsage
+	}
+
+	text, _ = database.FilterLobbyText(c.hub.lobbyId, text)
+	text = EscapeText(text)
+	PlayerBroadcast(targetPlayer.Id, "<blue>Whisper from</> <green>"+EscapeText(player.Name)+"</>: "+text)
+	c.hub.sendPrivate(c.user.Id, []byte("<blue>Whisper to</> <green>"+EscapeText(targetPlayer.Name)+"</>: "+text))
+	return nil
+}
+
+func commandScores(c *Client, player database.Player, args string) error {
+	data, err := database.GetLobbyGameStatsData(player.Id)
+	if err != nil {
+		return err
+	}
+
+	scores := make([]string, 0, len(data.Wins))
+	for _, win := range data.Wins {
+		scores = append(scores, fmt.Sprintf("<green>%s</> %d", EscapeText(win.Name), win.Count))
+	}
+
+	c.hub.sendPrivate(c.user.Id, []byte("<blue>Scores</>: "+strings.Join(scores, ", ")))
+	return nil
+}
+
+func commandTimer(c *Client, player database.Player, args string) error {
+	lobby, err := database.GetLobby(c.hub.lobbyId)
+	if err != nil {
+		return err
+	}
+
+	if lobby.RoundTimer <= 0 {
+		return errors.New("the lobby does not have a round timer")
+	}
+
+	LobbyBroadcast(c.hub.lobbyId, fmt.Sprintf("timer;;%d", lobby.RoundTimer))
+	LobbyBroadcast(c.hub.lobbyId, "<green>"+EscapeText(player.Name)+"</>: Reset the round timer.")
+	return nil
+}
+
+func commandKick(c *Client, player database.Player, args string) error {
+	targetPlayer, rest, err := findLobbyPlayerByName(c.hub.lobbyId, args)
+	if err != nil {
+		return err
+	}
+
+	if targetPlayer.Id == uuid.Nil || rest != "" {
+		return errCommandUsage
+	}
+
+	if targetPlayer.Id == player.Id {
+		return errors.New("you cannot kick yourself")
+	}
+
+	err = database.KickPlayer(targetPlayer.Id)
+	if err != nil {
+		return err
+	}
+
+	LobbyBroadcast(c.hub.lobbyId, "<red>Player Kicked</>: <green>"+EscapeText(targetPlayer.Name)+"</>")
+	LobbyBroadcast(c.hub.lobbyId, "player-kicked")
+	go func() {
+		time.Sleep(2 * time.Second)
+		PlayerBroadcast(targetPlayer.Id, "exit")
+	}()
+	return nil
+}
+
+func commandSkip(c *Client, player database.Player, args string) error {
+	err := database.SkipPrompt(c.hub.lobbyId)
+	if err != nil {
+		return err
+	}
+
+	LobbyBroadcast(c.hub.lobbyId, "<green>"+EscapeText(player.Name)+"</>: Skipped the prompt.")
+	LobbyBroadcast(c.hub.lobbyId, "refresh")
+	return nil
+}
@@ -1,6 +1,9 @@
 package websocket
 
 import (
+	"log"
+	"strings"
+
 	"github.com/google/uuid"
 	"github.com/grantfbarnes/card-judge/database"
 )
@@ -22,6 +25,17 @@ type Hub struct {
 
 	// Unregister requests from clients.
 	unregister chan *Client
+
+	// Messages for the clients of a single user.
+	private chan privateMessage
+
+	// Closed once the hub stops running.
+	done chan struct{}
+}
+
+type privateMessage struct {
+	userId  uuid.UUID
+	message []byte
 }
 
 func newHub(lobbyId uuid.UUID) *Hub {
@@ -30,6 +44,8 @@ func newHub(lobbyId uuid.UUID) *Hub {
 		broadcast:  make(chan []byte),
 		register:   make(chan *Client),
 		unregister: make(chan *Client),
+		private:    make(chan privateMessage),
+		done:       make(chan struct{}),
 		clients:    make(map[*Client]bool),
 	}
 }
@@ -44,17 +60,20 @@ func (h *Hub) run() {
 			if len(h.clients) == 0 {
 				_ = database.DeleteLobby(h.lobbyId)
 				delete(lobbyHubs, h.lobbyId)
+				close(h.done)
 				return
 			}
 		case message := <-h.broadcast:
 			h.broadcastMessage(message)
+		case private := <-h.private:
+			h.privateMessage(private.userId, private.message)
 		}
 	}
 }
 
 func (h *Hub) registerClient(client *Client) {
 	h.clients[client] = true
-	h.broadcastMessage([]byte("<blue>Player Joined</>: <green>" + client.user.Name + "</>"))
+	h.broadcastMessage([]byte("<blue>Player Joined</>: <green>" + EscapeText(client.user.Name) + "</>"))
 	h.broadcastMessage([]byte("refresh"))
 }
 
@@ -64,7 +83,7 @@ func (h *Hub) unregisterClient(client *Client) {
 		close(client.send)
 		_ = database.SetPlayerInactive(h.lobbyId, client.user.Id)
 	}
-	h.broadcastMessage([]byte("<red>Player Left</>: <green>" + client.user.Name + "</>"))
+	h.broadcastMessage([]byte("<red>Player Left</>: <green>" + EscapeText(client.user.Name) + "</>"))
 	h.broadcastMessage([]byte("refresh"))
 }
 
@@ -79,9 +98,43 @@ func (h *Hub) broadcastMessage(message []byte) {
 	}
 }
 
+func (h *Hub) privateMessage(userId uuid.UUID, message []byte) {
+	for client := range h.clients {
+		if client.user.Id != userId {
+			continue
+		}
+
+		select {
+		case client.send <- message:
+		default:
+			close(client.send)
+			delete(h.clients, client)
+		}
+	}
+}
+
+// sendPrivate hands a message to the hub goroutine, which owns the clients and
+// their send channels. It gives up if the hub has already stopped.
+func (h *Hub) sendPrivate(userId uuid.UUID, message []byte) {
+	select {
+	case h.private <- privateMessage{userId: userId, message: message}:
+	case <-h.done:
+	}
+}
+
 func LobbyBroadcast(lobbyId uuid.UUID, message string) {
+	if isChatMessage(message) {
+		err := database.AddLobbyChatMessage(lobbyId, uuid.Nil, strings.ReplaceAll(message, "\n", " "))
+		if err != nil {
+			log.Println(err)
+		}
+	}
+
 	if hub, ok := lobbyHubs[lobbyId]; ok {
-		hub.broadcastMessage([]byte(message))
+		select {
+		case hub.broadcast <- []byte(message):
+		case <-hub.done:
+		}
 	}
 }
 
@@ -92,10 +145,19 @@ func PlayerBroadcast(playerId uuid.UUID, message string) {
 	}
 
 	if hub, ok := lobbyHubs[player.LobbyId]; ok {
-		for client := range hub.clients {
-			if client.user.Id == player.UserId {
-				client.send <- []byte(message)
-			}
-		}
+		hub.sendPrivate(player.UserId, []byte(message))
 	}
 }
+
+// isChatMessage reports whether a message is displayed in the chat, as opposed
+// to a control message the client acts on.
+func isChatMessage(message string) bool {
+	switch message {
+	case "refresh", "table-flipped", "player-kicked", "exit":
+		return false
+	}
+
+	return !strings.HasPrefix(message, "refresh-") &&
+		!strings.HasPrefix(message, "timer;;") &&
+		!strings.HasPrefix(message, "alert;;")
+}